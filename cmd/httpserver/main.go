@@ -1,9 +1,15 @@
 package main
 
 import (
+	"context"
 	"fmt"
 	"log"
+	"net/url"
 	"os"
+	"os/signal"
+	"strconv"
+	"syscall"
+	"time"
 
 	"github.com/joakim-ribier/go-utils/pkg/genericsutil"
 	"github.com/joakim-ribier/go-utils/pkg/iosutil"
@@ -13,6 +19,7 @@ import (
 	"github.com/joakim-ribier/go-utils/pkg/stringsutil"
 	"github.com/joakim-ribier/mockapic/internal"
 	"github.com/joakim-ribier/mockapic/internal/server"
+	"github.com/joakim-ribier/mockapic/pkg"
 )
 
 func main() {
@@ -28,18 +35,124 @@ func main() {
 	if arg, ok := args["--req_max"]; ok {
 		internal.MOCKAPIC_REQ_MAX_LIMIT = stringsutil.Int(arg, -1)
 	}
+	if arg, ok := args["--auto_clean_every"]; ok {
+		internal.MOCKAPIC_AUTO_CLEAN_EVERY = stringsutil.Int(arg, -1)
+	}
+	if arg, ok := args["--auto_clean_max"]; ok {
+		internal.MOCKAPIC_AUTO_CLEAN_MAX = stringsutil.Int(arg, -1)
+	}
+	if arg, ok := args["--storage_max"]; ok {
+		internal.MOCKAPIC_STORAGE_MAX_SIZE = stringsutil.Int(arg, -1)
+	}
+	if arg, ok := args["--cache_size"]; ok {
+		internal.MOCKAPIC_CACHE_SIZE = stringsutil.Int(arg, -1)
+	}
+	if arg, ok := args["--compress"]; ok {
+		internal.MOCKAPIC_COMPRESS = stringsutil.Bool(arg)
+	}
+	if arg, ok := args["--body_max"]; ok {
+		internal.MOCKAPIC_BODY_MAX_SIZE = stringsutil.Int(arg, -1)
+	}
+	if arg, ok := args["--default_content_type"]; ok {
+		internal.MOCKAPIC_DEFAULT_CONTENT_TYPE = arg
+	}
+	if internal.MOCKAPIC_DEFAULT_CONTENT_TYPE != "" && !slicesutil.Exist(pkg.CONTENT_TYPES, internal.MOCKAPIC_DEFAULT_CONTENT_TYPE) {
+		log.Fatalf("'--default_content_type' parameter must be a supported content type.")
+	}
+	if arg, ok := args["--default_charset"]; ok {
+		internal.MOCKAPIC_DEFAULT_CHARSET = arg
+	}
+	if internal.MOCKAPIC_DEFAULT_CHARSET != "" && !slicesutil.Exist(pkg.CHARSET, internal.MOCKAPIC_DEFAULT_CHARSET) {
+		log.Fatalf("'--default_charset' parameter must be a supported charset.")
+	}
+	if arg, ok := args["--seed"]; ok {
+		internal.MOCKAPIC_SEED_FILE = arg
+	}
+	if arg, ok := args["--rate_limit"]; ok {
+		internal.MOCKAPIC_RATE_LIMIT = stringsutil.Int(arg, -1)
+	}
+	if arg, ok := args["--rate_burst"]; ok {
+		internal.MOCKAPIC_RATE_BURST = stringsutil.Int(arg, -1)
+	}
+	if arg, ok := args["--history_size"]; ok {
+		internal.MOCKAPIC_HISTORY_SIZE = stringsutil.Int(arg, -1)
+	}
+	if arg, ok := args["--metrics"]; ok {
+		internal.MOCKAPIC_METRICS = stringsutil.Bool(arg)
+	}
+	if arg, ok := args["--files"]; ok {
+		internal.MOCKAPIC_FILES_DIRECTORY = arg
+	}
+	if internal.MOCKAPIC_FILES_DIRECTORY != "" {
+		if info, err := os.Stat(internal.MOCKAPIC_FILES_DIRECTORY); err != nil || !info.IsDir() {
+			log.Fatalf("'--files' parameter must be a valid directory.")
+		}
+	}
+	if arg, ok := args["--body_url_ttl"]; ok {
+		internal.MOCKAPIC_BODY_URL_TTL = arg
+	}
+	bodyURLTTL := time.Minute
+	if internal.MOCKAPIC_BODY_URL_TTL != "" {
+		ttl, err := time.ParseDuration(internal.MOCKAPIC_BODY_URL_TTL)
+		if err != nil || ttl < 0 {
+			log.Fatalf("'--body_url_ttl' parameter must be a valid duration.")
+		}
+		bodyURLTTL = ttl
+	}
+	if arg, ok := args["--body_url_private"]; ok {
+		internal.MOCKAPIC_BODY_URL_PRIVATE = stringsutil.Bool(arg)
+	}
+	if arg, ok := args["--disable_delay"]; ok {
+		internal.MOCKAPIC_DISABLE_DELAY = stringsutil.Bool(arg)
+	}
+	if arg, ok := args["--global_failure_rate"]; ok {
+		failureRate, err := strconv.ParseFloat(arg, 64)
+		if err != nil || failureRate < 0 || failureRate > 1 {
+			log.Fatalf("'--global_failure_rate' parameter must be a number between 0 and 1.")
+		}
+		internal.MOCKAPIC_GLOBAL_FAILURE_RATE = failureRate
+	}
+	if arg, ok := args["--not_found_mock"]; ok {
+		internal.MOCKAPIC_NOT_FOUND_MOCK = arg
+	}
+	if arg, ok := args["--signing_secret"]; ok {
+		internal.MOCKAPIC_SIGNING_SECRET = arg
+	}
+	if arg, ok := args["--admin_token"]; ok {
+		internal.MOCKAPIC_ADMIN_TOKEN = arg
+	}
+	if internal.MOCKAPIC_SIGNING_SECRET != "" && internal.MOCKAPIC_ADMIN_TOKEN == "" {
+		log.Fatalf("'--signing_secret' parameter requires the '--admin_token' parameter.")
+	}
+	if arg, ok := args["--proxy_target"]; ok {
+		internal.MOCKAPIC_PROXY_TARGET = arg
+	}
+	var proxyTarget *url.URL
+	if internal.MOCKAPIC_PROXY_TARGET != "" {
+		target, err := server.ParseProxyTarget(internal.MOCKAPIC_PROXY_TARGET)
+		if err != nil {
+			log.Fatalf("'--proxy_target' parameter must be a valid URL.\n%v", err)
+		}
+		proxyTarget = target
+	}
 	if arg, ok := args["--port"]; ok {
 		internal.MOCKAPIC_PORT = arg
 	}
 	if arg, ok := args["--cert"]; ok {
 		internal.MOCKAPIC_CERT_DIRECTORY = arg
 	}
+	if arg, ok := args["--ssl_self_signed"]; ok {
+		internal.MOCKAPIC_SSL_SELF_SIGNED = stringsutil.Bool(arg)
+	}
 	if arg, ok := args["--ssl"]; ok {
 		internal.MOCKAPIC_SSL = stringsutil.Bool(arg)
 		if internal.MOCKAPIC_SSL && internal.MOCKAPIC_CERT_DIRECTORY == "" {
 			internal.MOCKAPIC_CERT_DIRECTORY = internal.MOCKAPIC_HOME
 		}
 	}
+	if internal.MOCKAPIC_SSL_SELF_SIGNED && !internal.MOCKAPIC_SSL {
+		log.Fatalf("'--ssl_self_signed' parameter requires the '--ssl' parameter.")
+	}
 
 	logger, err := logsutil.NewLogger(internal.MOCKAPIC_HOME+"/application.log", "mockapic")
 	if err != nil {
@@ -50,7 +163,30 @@ func main() {
 		"home", internal.MOCKAPIC_HOME,
 		"port", internal.MOCKAPIC_PORT,
 		"ssl", internal.MOCKAPIC_SSL,
+		"ssl_self_signed", internal.MOCKAPIC_SSL_SELF_SIGNED,
 		"req_max", internal.MOCKAPIC_REQ_MAX_LIMIT,
+		"auto_clean_every", internal.MOCKAPIC_AUTO_CLEAN_EVERY,
+		"auto_clean_max", internal.MOCKAPIC_AUTO_CLEAN_MAX,
+		"seed", internal.MOCKAPIC_SEED_FILE,
+		"default_content_type", internal.MOCKAPIC_DEFAULT_CONTENT_TYPE,
+		"default_charset", internal.MOCKAPIC_DEFAULT_CHARSET,
+		"rate_limit", internal.MOCKAPIC_RATE_LIMIT,
+		"rate_burst", internal.MOCKAPIC_RATE_BURST,
+		"metrics", internal.MOCKAPIC_METRICS,
+		"history_size", internal.MOCKAPIC_HISTORY_SIZE,
+		"proxy_target", internal.MOCKAPIC_PROXY_TARGET,
+		"not_found_mock", internal.MOCKAPIC_NOT_FOUND_MOCK,
+		"global_failure_rate", internal.MOCKAPIC_GLOBAL_FAILURE_RATE,
+		"disable_delay", internal.MOCKAPIC_DISABLE_DELAY,
+		"body_url_ttl", bodyURLTTL.String(),
+		"body_url_private", internal.MOCKAPIC_BODY_URL_PRIVATE,
+		"files", internal.MOCKAPIC_FILES_DIRECTORY,
+		"admin_token", internal.MOCKAPIC_ADMIN_TOKEN != "",
+		"signing_secret", internal.MOCKAPIC_SIGNING_SECRET != "",
+		"storage_max", internal.MOCKAPIC_STORAGE_MAX_SIZE,
+		"cache_size", internal.MOCKAPIC_CACHE_SIZE,
+		"compress", internal.MOCKAPIC_COMPRESS,
+		"body_max", internal.MOCKAPIC_BODY_MAX_SIZE,
 	)
 
 	err = os.MkdirAll(internal.MOCKAPIC_REQUEST(), os.ModePerm)
@@ -69,20 +205,69 @@ func main() {
 		}
 	}
 
+	mocker := internal.NewMock(internal.MOCKAPIC_REQUEST(), predefinedMockedRequests, *logger,
+		internal.WithDefaults(internal.MOCKAPIC_DEFAULT_CONTENT_TYPE, internal.MOCKAPIC_DEFAULT_CHARSET),
+		internal.WithCacheSize(internal.MOCKAPIC_CACHE_SIZE),
+		internal.WithCompression(internal.MOCKAPIC_COMPRESS))
+	if internal.MOCKAPIC_SEED_FILE != "" {
+		nb, err := mocker.Seed(internal.MOCKAPIC_SEED_FILE)
+		if err != nil {
+			log.Fatalf("'--seed' parameter must be a valid seed file.\n%v", err)
+		}
+		logger.Info("seed file loaded", "seed", internal.MOCKAPIC_SEED_FILE, "created", nb)
+	}
+
 	httpServer := server.NewHTTPServer(
 		stringsutil.OrElse(internal.MOCKAPIC_PORT, "3333"),
 		internal.MOCKAPIC_SSL,
 		internal.MOCKAPIC_CERT_DIRECTORY,
 		internal.MOCKAPIC_HOME,
-		internal.NewMock(internal.MOCKAPIC_REQUEST(), predefinedMockedRequests, *logger),
-		*logger)
+		mocker,
+		*logger,
+		server.WithMaxBodySize(int64(internal.MOCKAPIC_BODY_MAX_SIZE)),
+		server.WithAccessLog(true),
+		server.WithStorageQuota(int64(internal.MOCKAPIC_STORAGE_MAX_SIZE), time.Minute),
+		server.WithRateLimit(float64(internal.MOCKAPIC_RATE_LIMIT), internal.MOCKAPIC_RATE_BURST),
+		server.WithMetrics(internal.MOCKAPIC_METRICS),
+		server.WithSelfSignedCert(internal.MOCKAPIC_SSL_SELF_SIGNED),
+		server.WithProxyTarget(proxyTarget),
+		server.WithHistory(internal.MOCKAPIC_HISTORY_SIZE),
+		server.WithNotFoundMock(internal.MOCKAPIC_NOT_FOUND_MOCK),
+		server.WithAdminToken(internal.MOCKAPIC_ADMIN_TOKEN),
+		server.WithSigningSecret(internal.MOCKAPIC_SIGNING_SECRET),
+		server.WithGlobalFailureRate(internal.MOCKAPIC_GLOBAL_FAILURE_RATE),
+		server.WithoutDelay(internal.MOCKAPIC_DISABLE_DELAY),
+		server.WithBodyURLTTL(bodyURLTTL),
+		server.WithPrivateBodyURL(internal.MOCKAPIC_BODY_URL_PRIVATE),
+		server.WithFilesDirectory(internal.MOCKAPIC_FILES_DIRECTORY),
+		server.WithAutoClean(internal.MOCKAPIC_AUTO_CLEAN_EVERY, internal.MOCKAPIC_AUTO_CLEAN_MAX))
 
 	fmt.Print(internal.LOGO)
 	fmt.Printf("\nServer running on port %s[:%s]....\n",
 		genericsutil.When(internal.MOCKAPIC_SSL, func(arg bool) bool { return arg }, "https", "http"),
 		httpServer.Port)
 
+	// drain the in-flight requests (30s max) on SIGINT or SIGTERM
+	signals := make(chan os.Signal, 1)
+	signal.Notify(signals, os.Interrupt, syscall.SIGTERM)
+	stopped := make(chan struct{})
+	go func() {
+		defer close(stopped)
+
+		<-signals
+
+		ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
+		defer cancel()
+		if err := httpServer.Shutdown(ctx); err != nil {
+			logger.Error(err, "error to shut down the server")
+		}
+	}()
+
 	if err := httpServer.Listen(); err != nil {
 		log.Fatal("could not open httpServer", err)
 	}
+	// the server may be stopped without signal ("/admin/shutdown"), the in-flight requests are drained as well
+	signal.Stop(signals)
+	close(signals)
+	<-stopped
 }
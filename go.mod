@@ -3,9 +3,11 @@ module github.com/joakim-ribier/mockapic
 go 1.22.5
 
 require (
+	github.com/andybalholm/brotli v1.2.0
 	github.com/google/uuid v1.6.0
 	github.com/jedib0t/go-pretty/v6 v6.5.9
 	github.com/joakim-ribier/go-utils v0.0.0-20240807210644-38116094b686
+	gopkg.in/yaml.v3 v3.0.1
 )
 
 require (
@@ -0,0 +1,115 @@
+package internal
+
+import (
+	"container/list"
+	"os"
+	"sync"
+)
+
+// mockCache is a LRU cache of the parsed mocked requests by id,
+// a nil cache is a disabled cache (nothing is kept, all the methods are no-op).
+type mockCache struct {
+	mu      sync.Mutex
+	size    int
+	entries map[string]*list.Element
+	order   *list.List
+}
+
+func newMockCache(size int) *mockCache {
+	return &mockCache{size: size, entries: map[string]*list.Element{}, order: list.New()}
+}
+
+// caches contains a cache by working directory to share it between the {Mock} of a same storage
+var caches sync.Map
+
+// cacheFor returns the cache of the {workingDirectory} (created with the {size} on the first call),
+// it returns nil if the {size} is not positive.
+func cacheFor(workingDirectory string, size int) *mockCache {
+	if size < 1 {
+		return nil
+	}
+	cache, _ := caches.LoadOrStore(workingDirectory, newMockCache(size))
+	return cache.(*mockCache)
+}
+
+// get returns a copy of the cached mocked request {mockId} and marks it as the most recently used.
+func (c *mockCache) get(mockId string) (*MockedRequest, bool) {
+	if c == nil {
+		return nil, false
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	element, is := c.entries[mockId]
+	if !is {
+		return nil, false
+	}
+	c.order.MoveToFront(element)
+	mock := element.Value.(MockedRequest)
+	return &mock, true
+}
+
+// put keeps a copy of the {mock}, the least recently used request is evicted once the size is exceeded.
+func (c *mockCache) put(mock MockedRequest) {
+	if c == nil {
+		return
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if element, is := c.entries[mock.Id]; is {
+		element.Value = mock
+		c.order.MoveToFront(element)
+		return
+	}
+
+	c.entries[mock.Id] = c.order.PushFront(mock)
+	if c.order.Len() > c.size {
+		oldest := c.order.Back()
+		c.order.Remove(oldest)
+		delete(c.entries, oldest.Value.(MockedRequest).Id)
+	}
+}
+
+// remove evicts the mocked request {mockId}.
+func (c *mockCache) remove(mockId string) {
+	if c == nil {
+		return
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if element, is := c.entries[mockId]; is {
+		c.order.Remove(element)
+		delete(c.entries, mockId)
+	}
+}
+
+// clear evicts all the mocked requests.
+func (c *mockCache) clear() {
+	if c == nil {
+		return
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	clear(c.entries)
+	c.order.Init()
+}
+
+// read finds the mocked request {mockId} in the cache or on the storage (kept in the cache once read).
+func (m Mock) read(mockId string) (*MockedRequest, error) {
+	if mock, is := m.cache.get(mockId); is {
+		return mock, nil
+	}
+
+	mock, err := get[MockedRequest](os.DirFS(m.workingDirectory), mockId, m.logger)
+	if mock != nil {
+		m.cache.put(*mock)
+	}
+	return mock, err
+}
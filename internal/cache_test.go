@@ -0,0 +1,122 @@
+package internal
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func newCachedMock(t testing.TB, size int) (Mock, string) {
+	mocker := NewMock(t.TempDir(), nil, *logger, WithCacheSize(size))
+	id, err := mocker.New(map[string][]string{
+		"status":      {"200"},
+		"contentType": {"text/plain"},
+		"charset":     {"UTF-8"},
+	}, []byte("Hello World"))
+	if err != nil {
+		t.Fatalf(err.Error())
+	}
+	return mocker, *id
+}
+
+// TestGetWithCache calls Mock.Get,
+// checking for a valid return value.
+func TestGetWithCache(t *testing.T) {
+	mocker, id := newCachedMock(t, 10)
+	if _, err := mocker.Get(id); err != nil {
+		t.Fatalf(err.Error())
+	}
+
+	// test if the second call does not read the storage
+	if err := os.Remove(filepath.Join(mocker.workingDirectory, id+".json")); err != nil {
+		t.Fatalf(err.Error())
+	}
+	if mock, err := mocker.Get(id); err != nil || string(mock.Body64) != "Hello World" {
+		t.Fatalf(`result: {%v} but expected {%v}`, err, "Hello World")
+	}
+
+	// test if the reload clears the cache
+	if err := mocker.Reload(); err != nil {
+		t.Fatalf(err.Error())
+	}
+	if _, err := mocker.Get(id); err == nil {
+		t.Fatalf(`result: {%v} but expected {%v}`, err, "mocked request does not exist")
+	}
+}
+
+// TestHitWithCache calls Mock.Hit,
+// checking for a valid return value.
+func TestHitWithCache(t *testing.T) {
+	mocker, id := newCachedMock(t, 10)
+	for range 3 {
+		if _, err := mocker.Hit(id); err != nil {
+			t.Fatalf(err.Error())
+		}
+	}
+
+	// test if the hits are kept in the cache and persisted on the storage
+	if mock, _ := mocker.Get(id); mock.Hits != 3 {
+		t.Fatalf(`result: {%v} but expected {%v}`, mock.Hits, 3)
+	}
+	if mock, _ := NewMock(mocker.workingDirectory, nil, *logger).Get(id); mock.Hits != 3 {
+		t.Fatalf(`result: {%v} but expected {%v}`, mock.Hits, 3)
+	}
+}
+
+// TestDeleteManyWithCache calls Mock.DeleteMany,
+// checking for a valid return value.
+func TestDeleteManyWithCache(t *testing.T) {
+	mocker, id := newCachedMock(t, 10)
+	if _, err := mocker.Get(id); err != nil {
+		t.Fatalf(err.Error())
+	}
+
+	if nb, _ := mocker.DeleteMany([]string{id}); nb != 1 {
+		t.Fatalf(`result: {%v} but expected {%v}`, nb, 1)
+	}
+	if _, err := mocker.Get(id); err == nil {
+		t.Fatalf(`result: {%v} but expected {%v}`, err, "mocked request does not exist")
+	}
+}
+
+// TestMockCacheEviction calls mockCache.put,
+// checking for a valid return value.
+func TestMockCacheEviction(t *testing.T) {
+	cache := newMockCache(2)
+	for _, id := range []string{"1", "2"} {
+		cache.put(MockedRequest{MockedRequestLight: MockedRequestLight{Id: id}})
+	}
+	cache.get("1")
+	cache.put(MockedRequest{MockedRequestLight: MockedRequestLight{Id: "3"}})
+
+	for id, expected := range map[string]bool{"1": true, "2": false, "3": true} {
+		if _, is := cache.get(id); is != expected {
+			t.Fatalf(`%s - result: {%v} but expected {%v}`, id, is, expected)
+		}
+	}
+
+	// test if a nil cache is disabled
+	var disabled *mockCache
+	disabled.put(MockedRequest{MockedRequestLight: MockedRequestLight{Id: "1"}})
+	if _, is := disabled.get("1"); is {
+		t.Fatalf(`result: {%v} but expected {%v}`, is, false)
+	}
+}
+
+func benchmarkGet(b *testing.B, size int) {
+	mocker, id := newCachedMock(b, size)
+	b.ResetTimer()
+	for range b.N {
+		if _, err := mocker.Get(id); err != nil {
+			b.Fatalf(err.Error())
+		}
+	}
+}
+
+func BenchmarkGet(b *testing.B) {
+	benchmarkGet(b, 0)
+}
+
+func BenchmarkGetWithCache(b *testing.B) {
+	benchmarkGet(b, 10)
+}
@@ -0,0 +1,92 @@
+package internal
+
+import (
+	"maps"
+
+	"github.com/google/uuid"
+	"github.com/joakim-ribier/go-utils/pkg/stringsutil"
+)
+
+// cloneMockedRequest copies the {source} mocked request with a new identifier and a fresh creation date,
+// the {reqParams} (status, contentType, charset and the other values as headers) override the copied values.
+func cloneMockedRequest(source MockedRequest, reqParams map[string][]string) (*MockedRequest, error) {
+	mock := source
+	mock.Id = uuid.NewString()
+	mock.CreatedAt = timestamp()
+	mock.Hits = 0
+	mock.LastAccessedAt = ""
+	mock.SequenceIndex = 0
+	mock.Headers = maps.Clone(source.Headers)
+	if mock.Headers == nil {
+		mock.Headers = map[string]string{}
+	}
+	mock.MultiHeaders = maps.Clone(source.MultiHeaders)
+
+	for name, values := range reqParams {
+		if len(values) == 0 {
+			continue
+		}
+		switch name {
+		case "status":
+			mock.Status = stringsutil.Int(values[0], -1)
+		case "contentType":
+			mock.ContentType = values[0]
+		case "charset":
+			mock.Charset = values[0]
+		default:
+			mock.Headers[name] = values[0]
+			delete(mock.MultiHeaders, name)
+		}
+	}
+
+	mock.canonicalize()
+	stripHopByHopHeaders(&mock)
+	if err := validate(mock); err != nil {
+		return nil, err
+	}
+
+	return &mock, nil
+}
+
+// Clone copies the mocked request {mockId} (see {cloneMockedRequest}), stores it and returns the new identifier.
+func (m Mock) Clone(mockId string, reqParams map[string][]string) (*string, error) {
+	source, err := m.Get(mockId)
+	if err != nil {
+		return nil, err
+	}
+
+	mock, err := cloneMockedRequest(*source, reqParams)
+	if err != nil {
+		return nil, err
+	}
+
+	mu := lock(m.workingDirectory)
+	mu.Lock()
+	defer mu.Unlock()
+
+	if err := m.write(mock); err != nil {
+		return nil, err
+	}
+
+	return &mock.Id, nil
+}
+
+// Clone copies the mocked request {mockId} (see {cloneMockedRequest}), keeps it and returns the new identifier.
+func (m *InMemoryMock) Clone(mockId string, reqParams map[string][]string) (*string, error) {
+	source, err := m.Get(mockId)
+	if err != nil {
+		return nil, err
+	}
+
+	mock, err := cloneMockedRequest(*source, reqParams)
+	if err != nil {
+		return nil, err
+	}
+
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	m.mockedRequests[mock.Id] = *mock
+
+	return &mock.Id, nil
+}
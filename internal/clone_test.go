@@ -0,0 +1,42 @@
+package internal
+
+import (
+	"testing"
+)
+
+// TestClone calls InMemoryMock.Clone(string, map[string][]string),
+// checking for a valid return value.
+func TestClone(t *testing.T) {
+	mocker := NewInMemoryMock(*logger)
+	id, err := mocker.New(map[string][]string{
+		"status":      {"200"},
+		"contentType": {"text/plain"},
+		"charset":     {"UTF-8"},
+		"X-Source":    {"source"},
+	}, []byte("Hello World"))
+	if err != nil {
+		t.Fatalf(err.Error())
+	}
+	mocker.Hit(*id)
+
+	cloneId, err := mocker.Clone(*id, map[string][]string{"status": {"201"}, "X-Clone": {"clone"}})
+	if err != nil {
+		t.Fatalf(err.Error())
+	}
+
+	clone, _ := mocker.Get(*cloneId)
+	if *cloneId == *id || clone.Status != 201 || clone.Hits != 0 || string(clone.Body64) != "Hello World" ||
+		clone.Headers["X-Source"] != "source" || clone.Headers["X-Clone"] != "clone" {
+		t.Fatalf(`result: {%v} but expected {%v}`, clone, "a copy with the overrides")
+	}
+
+	// test if the headers of the source are not shared
+	if source, _ := mocker.Get(*id); source.Headers["X-Clone"] != "" {
+		t.Fatalf(`result: {%v} but expected {%v}`, source.Headers, "no X-Clone header")
+	}
+
+	// test if the mocked request does not exist
+	if _, err := mocker.Clone("id-does-not-exist", nil); err == nil {
+		t.Fatalf(`result: {%v} but expected {%v}`, err, "error")
+	}
+}
@@ -0,0 +1,58 @@
+package internal
+
+import (
+	"errors"
+	"slices"
+	"strings"
+)
+
+// Collection represents a named group of mocked requests
+type Collection struct {
+	Name  string `json:"name"`
+	Count int    `json:"count"`
+}
+
+// Collections lists the collections of the mocked requests with their number of mocked requests sorted by name,
+// the mocked requests without collection are ignored.
+func Collections(mocker Mocker) ([]Collection, error) {
+	mockedRequestLights, err := mocker.List()
+	if err != nil {
+		return nil, err
+	}
+
+	collections := []Collection{}
+	for _, mrl := range mockedRequestLights {
+		if mrl.Collection == "" {
+			continue
+		}
+		if i := slices.IndexFunc(collections, func(c Collection) bool { return c.Name == mrl.Collection }); i >= 0 {
+			collections[i].Count++
+		} else {
+			collections = append(collections, Collection{Name: mrl.Collection, Count: 1})
+		}
+	}
+
+	slices.SortFunc(collections, func(c1, c2 Collection) int { return strings.Compare(c1.Name, c2.Name) })
+	return collections, nil
+}
+
+// DeleteCollection removes all the mocked requests of the {collection},
+// it returns the number of deleted mocked requests and the errors by id of the others.
+func DeleteCollection(mocker Mocker, collection string) (int, map[string]string, error) {
+	if collection == "" {
+		return 0, nil, errors.New("collection must be defined")
+	}
+
+	mockedRequestLights, err := mocker.Search(SearchFilter{Collection: collection})
+	if err != nil {
+		return 0, nil, err
+	}
+
+	mockIds := []string{}
+	for _, mrl := range mockedRequestLights {
+		mockIds = append(mockIds, mrl.Id)
+	}
+
+	deleted, errs := mocker.DeleteMany(mockIds)
+	return deleted, errs, nil
+}
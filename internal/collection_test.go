@@ -0,0 +1,39 @@
+package internal
+
+import (
+	"reflect"
+	"testing"
+)
+
+// TestCollections calls Collections(Mocker) and DeleteCollection(Mocker, string),
+// checking for a valid return value.
+func TestCollections(t *testing.T) {
+	mocker := NewInMemoryMock(*logger)
+	for _, collection := range []string{"checkout", "users", "checkout", ""} {
+		if _, err := mocker.New(map[string][]string{
+			"contentType": {"text/plain"}, "charset": {"UTF-8"}, "collection": {collection},
+		}, nil); err != nil {
+			t.Fatalf(err.Error())
+		}
+	}
+
+	collections, err := Collections(mocker)
+	expected := []Collection{{Name: "checkout", Count: 2}, {Name: "users", Count: 1}}
+	if err != nil || !reflect.DeepEqual(collections, expected) {
+		t.Fatalf(`result: {%v} but expected {%v}`, collections, expected)
+	}
+
+	deleted, errs, err := DeleteCollection(mocker, "checkout")
+	if err != nil || deleted != 2 || len(errs) != 0 {
+		t.Fatalf(`result: {%v} but expected {%v}`, deleted, 2)
+	}
+
+	collections, _ = Collections(mocker)
+	if expected := []Collection{{Name: "users", Count: 1}}; !reflect.DeepEqual(collections, expected) {
+		t.Fatalf(`result: {%v} but expected {%v}`, collections, expected)
+	}
+
+	if _, _, err := DeleteCollection(mocker, ""); err == nil || err.Error() != "collection must be defined" {
+		t.Fatalf(`result: {%v} but expected {%v}`, err, "collection must be defined")
+	}
+}
@@ -0,0 +1,96 @@
+package internal
+
+import (
+	"bytes"
+	"compress/gzip"
+	"errors"
+	"io"
+	"io/fs"
+	"os"
+)
+
+// compressedExtension is the extension of the mocked requests stored gzip-compressed,
+// the "{id}.json" and "{id}.json.gz" files can coexist in the storage.
+const compressedExtension = ".json.gz"
+
+// WithCompression stores the new and the updated mocked requests gzip-compressed ("{id}.json.gz" files),
+// the uncompressed files are still read and compressed on their next update (ignored by the {InMemoryMock}).
+func WithCompression(compress bool) MockOption {
+	return func(d *mockDefaults) {
+		d.compress = compress
+	}
+}
+
+// readMockFile reads the content of the mocked request {mockId} from the "{mockId}.json" file
+// or else from the "{mockId}.json.gz" file (decompressed).
+func readMockFile(fsys fs.FS, mockId string) ([]byte, error) {
+	data, err := fs.ReadFile(fsys, mockId+".json")
+	if !errors.Is(err, fs.ErrNotExist) {
+		return data, err
+	}
+
+	file, gzErr := fsys.Open(mockId + compressedExtension)
+	if gzErr != nil {
+		// the error of the uncompressed file is kept as the request is not stored in any format
+		return nil, err
+	}
+	defer file.Close()
+
+	reader, err := gzip.NewReader(file)
+	if err != nil {
+		return nil, err
+	}
+	defer reader.Close()
+
+	return io.ReadAll(reader)
+}
+
+// compress returns the {data} gzip-compressed.
+func compress(data []byte) ([]byte, error) {
+	var buf bytes.Buffer
+	writer := gzip.NewWriter(&buf)
+	if _, err := writer.Write(data); err != nil {
+		return nil, err
+	}
+	if err := writer.Close(); err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
+
+// exists returns true if the mocked request {mockId} is stored in any format.
+func (m Mock) exists(mockId string) bool {
+	path, err := safeMockPath(m.workingDirectory, mockId)
+	if err != nil {
+		return false
+	}
+	for _, name := range []string{path, path + ".gz"} {
+		if _, err := os.Stat(name); err == nil {
+			return true
+		}
+	}
+	return false
+}
+
+// remove removes the files of the mocked request {mockId} in any format,
+// it returns a {fs.ErrNotExist} error if the request is not stored.
+func (m Mock) remove(mockId string) error {
+	path, err := safeMockPath(m.workingDirectory, mockId)
+	if err != nil {
+		return err
+	}
+	m.cache.remove(mockId)
+
+	removed := false
+	for _, name := range []string{path, path + ".gz"} {
+		if err := os.Remove(name); err == nil {
+			removed = true
+		} else if !errors.Is(err, fs.ErrNotExist) {
+			return err
+		}
+	}
+	if !removed {
+		return &fs.PathError{Op: "remove", Path: path, Err: fs.ErrNotExist}
+	}
+	return nil
+}
@@ -0,0 +1,71 @@
+package internal
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+// TestNewWithCompression calls Mocker.New,
+// checking for a valid return value.
+func TestNewWithCompression(t *testing.T) {
+	workingDirectory := t.TempDir()
+	reqParams := map[string][]string{
+		"status":      {"200"},
+		"contentType": {"text/plain"},
+		"charset":     {"UTF-8"},
+	}
+	reqBody := strings.Repeat("Hello World", 1024)
+
+	compressed := NewMock(workingDirectory, nil, *logger, WithCompression(true))
+	id, err := compressed.New(reqParams, []byte(reqBody))
+	if err != nil {
+		t.Fatalf(err.Error())
+	}
+
+	info, err := os.Stat(filepath.Join(workingDirectory, *id+".json.gz"))
+	if err != nil || info.Size() >= int64(len(reqBody)) {
+		t.Fatalf(`result: {%v} but expected {%v}`, err, "a compressed file")
+	}
+	if _, err := os.Stat(filepath.Join(workingDirectory, *id+".json")); err == nil {
+		t.Fatalf(`result: {%v} but expected {%v}`, err, "no uncompressed file")
+	}
+
+	// test if the compressed request is read back identically
+	mock, err := compressed.Get(*id)
+	if err != nil || string(mock.Body64) != reqBody || mock.Id != *id {
+		t.Fatalf(`result: {%v} but expected {%v}`, err, reqBody)
+	}
+
+	if data, err := compressed.Stored(*id); err != nil || !strings.Contains(string(data), `"id":"`+*id+`"`) {
+		t.Fatalf(`result: {%v} but expected {%v}`, err, "the decompressed file")
+	}
+
+	// test if both formats coexist
+	uncompressed := NewMock(workingDirectory, nil, *logger)
+	uncompressedId, err := uncompressed.New(reqParams, []byte("Hello World"))
+	if err != nil {
+		t.Fatalf(err.Error())
+	}
+	for _, mocker := range []Mock{compressed, uncompressed} {
+		if mockedRequests, err := mocker.List(); err != nil || len(mockedRequests) != 2 {
+			t.Fatalf(`result: {%v} but expected {%v}`, mockedRequests, 2)
+		}
+	}
+
+	// test if an uncompressed request is compressed on its next update
+	if _, err := compressed.Hit(*uncompressedId); err != nil {
+		t.Fatalf(err.Error())
+	}
+	if _, err := os.Stat(filepath.Join(workingDirectory, *uncompressedId+".json")); err == nil {
+		t.Fatalf(`result: {%v} but expected {%v}`, err, "no uncompressed file")
+	}
+	if mock, err := uncompressed.Get(*uncompressedId); err != nil || mock.Hits != 1 {
+		t.Fatalf(`result: {%v} but expected {%v}`, err, 1)
+	}
+
+	if nb, errs := uncompressed.DeleteMany([]string{*id, *uncompressedId}); nb != 2 {
+		t.Fatalf(`result: {%v} but expected {%v}`, errs, 2)
+	}
+}
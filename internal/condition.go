@@ -0,0 +1,69 @@
+package internal
+
+import (
+	"bytes"
+	"errors"
+	"net/http"
+)
+
+// Condition represents an alternate response served when the header and/or the body of the incoming request matches
+type Condition struct {
+	Header       string `json:"header,omitempty"`
+	Value        string `json:"value,omitempty"`
+	BodyContains string `json:"bodyContains,omitempty"`
+	Status       int    `json:"status,omitempty"`
+	Body         string `json:"body,omitempty"`
+	Body64       []byte `json:"body64,omitempty"`
+}
+
+// Match returns true if the {req} has the header of the condition (with the same value if defined)
+// and if its body contains the expected value (if defined).
+func (c Condition) Match(req *http.Request) bool {
+	if c.BodyContains != "" && !bytes.Contains(peekBody(req), []byte(c.BodyContains)) {
+		return false
+	}
+	if c.Header == "" {
+		return true
+	}
+
+	values, is := req.Header[http.CanonicalHeaderKey(c.Header)]
+	if !is {
+		return false
+	}
+	if c.Value == "" {
+		return true
+	}
+
+	for _, value := range values {
+		if value == c.Value {
+			return true
+		}
+	}
+	return false
+}
+
+// validate returns an error if neither the header nor the body of the condition is defined.
+func (c Condition) validate() error {
+	if c.Header == "" && c.BodyContains == "" {
+		return errors.New("condition header or bodyContains must be defined")
+	}
+	return nil
+}
+
+// ApplyConditions returns the mocked request to serve for the {req}:
+// the status and the body of the first matching condition override the default ones.
+func (m MockedRequest) ApplyConditions(req *http.Request) MockedRequest {
+	if req == nil {
+		return m
+	}
+
+	for _, condition := range m.Conditions {
+		if condition.Match(req) {
+			m.Status = condition.Status
+			m.Body = ""
+			m.Body64 = condition.Body64
+			return m
+		}
+	}
+	return m
+}
@@ -0,0 +1,97 @@
+package internal
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+// TestConditionMatch calls Condition.Match(*http.Request),
+// checking for a valid return value.
+func TestConditionMatch(t *testing.T) {
+	req := httptest.NewRequest("GET", "http://localhost:3333/", nil)
+	req.Header.Set("Authorization", "Bearer token")
+
+	tests := []struct {
+		condition Condition
+		expected  bool
+	}{
+		{Condition{Header: "Authorization"}, true},
+		{Condition{Header: "authorization", Value: "Bearer token"}, true},
+		{Condition{Header: "Authorization", Value: "Bearer wrong"}, false},
+		{Condition{Header: "X-Api-Key"}, false},
+	}
+
+	for _, test := range tests {
+		if r := test.condition.Match(req); r != test.expected {
+			t.Fatalf(`%v - result: {%v} but expected {%v}`, test.condition, r, test.expected)
+		}
+	}
+}
+
+// TestConditionMatchWithBodyContains calls Condition.Match(*http.Request),
+// checking for a valid return value.
+func TestConditionMatchWithBodyContains(t *testing.T) {
+	newRequest := func() *http.Request {
+		req := httptest.NewRequest("POST", "http://localhost:3333/", strings.NewReader(`{"action":"delete"}`))
+		req.Header.Set("Authorization", "Bearer token")
+		return req
+	}
+
+	tests := []struct {
+		condition Condition
+		expected  bool
+	}{
+		{Condition{BodyContains: `"action":"delete"`}, true},
+		{Condition{BodyContains: `"action":"create"`}, false},
+		{Condition{Header: "Authorization", BodyContains: `"action":"delete"`}, true},
+		{Condition{Header: "X-Api-Key", BodyContains: `"action":"delete"`}, false},
+	}
+
+	for _, test := range tests {
+		if r := test.condition.Match(newRequest()); r != test.expected {
+			t.Fatalf(`%v - result: {%v} but expected {%v}`, test.condition, r, test.expected)
+		}
+	}
+}
+
+// TestApplyConditions calls MockedRequest.ApplyConditions(*http.Request),
+// checking for a valid return value.
+func TestApplyConditions(t *testing.T) {
+	mock := MockedRequest{
+		MockedRequestLight: MockedRequestLight{MockedRequestHeader: MockedRequestHeader{Status: 401}},
+		Conditions: []Condition{
+			{Header: "Authorization", Value: "Bearer admin", Status: 403, Body64: []byte("forbidden")},
+			{Header: "Authorization", Status: 200, Body64: []byte("ok")},
+		},
+		Body64: []byte("unauthorized"),
+	}
+
+	tests := []struct {
+		authorization  string
+		expectedStatus int
+		expectedBody   string
+	}{
+		{"", 401, "unauthorized"},
+		{"Bearer admin", 403, "forbidden"},
+		{"Bearer user", 200, "ok"},
+	}
+
+	for _, test := range tests {
+		req := httptest.NewRequest("GET", "http://localhost:3333/", nil)
+		if test.authorization != "" {
+			req.Header.Set("Authorization", test.authorization)
+		}
+
+		r := mock.ApplyConditions(req)
+		if r.Status != test.expectedStatus || string(r.Body64) != test.expectedBody {
+			t.Fatalf(`%s - result: {%v} but expected {%v}`, test.authorization, r, test.expectedStatus)
+		}
+	}
+
+	// test if the request is not defined
+	if r := mock.ApplyConditions(nil); r.Status != 401 {
+		t.Fatalf(`result: {%v} but expected {%v}`, r, 401)
+	}
+}
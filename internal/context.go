@@ -25,10 +25,47 @@ var MOCKAPIC_REQ_PREDEFINED_FILE = func() string {
 }
 
 var MOCKAPIC_REQ_MAX_LIMIT = stringsutil.Int(os.Getenv("MOCKAPIC_REQ_MAX_LIMIT"), -1)
+var MOCKAPIC_STORAGE_MAX_SIZE = stringsutil.Int(os.Getenv("MOCKAPIC_STORAGE_MAX_SIZE"), -1)
+var MOCKAPIC_BODY_MAX_SIZE = stringsutil.Int(os.Getenv("MOCKAPIC_BODY_MAX_SIZE"), -1)
+var MOCKAPIC_CACHE_SIZE = stringsutil.Int(os.Getenv("MOCKAPIC_CACHE_SIZE"), -1)
+var MOCKAPIC_COMPRESS = stringsutil.Bool(os.Getenv("MOCKAPIC_COMPRESS"))
+
+var MOCKAPIC_DEFAULT_CONTENT_TYPE = os.Getenv("MOCKAPIC_DEFAULT_CONTENT_TYPE")
+var MOCKAPIC_DEFAULT_CHARSET = os.Getenv("MOCKAPIC_DEFAULT_CHARSET")
+
+var MOCKAPIC_SEED_FILE = os.Getenv("MOCKAPIC_SEED")
+
+var MOCKAPIC_RATE_LIMIT = stringsutil.Int(os.Getenv("MOCKAPIC_RATE_LIMIT"), -1)
+var MOCKAPIC_RATE_BURST = stringsutil.Int(os.Getenv("MOCKAPIC_RATE_BURST"), -1)
+
+var MOCKAPIC_AUTO_CLEAN_EVERY = stringsutil.Int(os.Getenv("MOCKAPIC_AUTO_CLEAN_EVERY"), -1)
+var MOCKAPIC_AUTO_CLEAN_MAX = stringsutil.Int(os.Getenv("MOCKAPIC_AUTO_CLEAN_MAX"), -1)
+
+var MOCKAPIC_FILES_DIRECTORY = os.Getenv("MOCKAPIC_FILES")
+
+var MOCKAPIC_BODY_URL_TTL = os.Getenv("MOCKAPIC_BODY_URL_TTL")
+var MOCKAPIC_BODY_URL_PRIVATE = stringsutil.Bool(os.Getenv("MOCKAPIC_BODY_URL_PRIVATE"))
+
+var MOCKAPIC_DISABLE_DELAY = stringsutil.Bool(os.Getenv("MOCKAPIC_DISABLE_DELAY"))
+
+var MOCKAPIC_GLOBAL_FAILURE_RATE = parseFloat(os.Getenv("MOCKAPIC_GLOBAL_FAILURE_RATE"), 0)
+
+var MOCKAPIC_NOT_FOUND_MOCK = os.Getenv("MOCKAPIC_NOT_FOUND_MOCK")
+
+var MOCKAPIC_ADMIN_TOKEN = os.Getenv("MOCKAPIC_ADMIN_TOKEN")
+
+var MOCKAPIC_SIGNING_SECRET = os.Getenv("MOCKAPIC_SIGNING_SECRET")
+
+var MOCKAPIC_PROXY_TARGET = os.Getenv("MOCKAPIC_PROXY_TARGET")
+
+var MOCKAPIC_HISTORY_SIZE = stringsutil.Int(os.Getenv("MOCKAPIC_HISTORY_SIZE"), -1)
+
+var MOCKAPIC_METRICS = stringsutil.Bool(os.Getenv("MOCKAPIC_METRICS"))
 
 var MOCKAPIC_PORT = os.Getenv("MOCKAPIC_PORT")
 
 var MOCKAPIC_SSL = stringsutil.Bool(os.Getenv("MOCKAPIC_SSL"))
 var MOCKAPIC_CERT_DIRECTORY = os.Getenv("MOCKAPIC_CERT")
+var MOCKAPIC_SSL_SELF_SIGNED = stringsutil.Bool(os.Getenv("MOCKAPIC_SSL_SELF_SIGNED"))
 var MOCKAPIC_CERT_FILENAME = "mockapic.crt"
 var MOCKAPIC_PEM_FILENAME = "mockapic.key"
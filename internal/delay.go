@@ -0,0 +1,27 @@
+package internal
+
+import (
+	"fmt"
+	"strings"
+	"time"
+)
+
+// ParseDelay parses the {delay} value as a duration (`100ms`) or an ordered range of durations (`100ms-500ms`),
+// a single duration is considered as a range with the same lower and upper values.
+func ParseDelay(delay string) (time.Duration, time.Duration, error) {
+	from, to, isRange := strings.Cut(delay, "-")
+
+	lower, err := time.ParseDuration(from)
+	if err != nil {
+		return 0, 0, fmt.Errorf("delay {%s} is malformed", delay)
+	}
+	if !isRange {
+		return lower, lower, nil
+	}
+
+	upper, err := time.ParseDuration(to)
+	if err != nil || upper < lower {
+		return 0, 0, fmt.Errorf("delay {%s} is malformed", delay)
+	}
+	return lower, upper, nil
+}
@@ -0,0 +1,26 @@
+package internal
+
+import (
+	"testing"
+	"time"
+)
+
+// TestParseDelay calls ParseDelay(string),
+// checking for a valid return value.
+func TestParseDelay(t *testing.T) {
+	lower, upper, err := ParseDelay("100ms")
+	if err != nil || lower != 100*time.Millisecond || upper != 100*time.Millisecond {
+		t.Fatalf(`result: {%v, %v} but expected {%v}`, lower, upper, "100ms-100ms")
+	}
+
+	lower, upper, err = ParseDelay("100ms-1s")
+	if err != nil || lower != 100*time.Millisecond || upper != time.Second {
+		t.Fatalf(`result: {%v, %v} but expected {%v}`, lower, upper, "100ms-1s")
+	}
+
+	for _, delay := range []string{"wrong", "1s-wrong", "1s-100ms", "-1s"} {
+		if _, _, err := ParseDelay(delay); err == nil || err.Error() != "delay {"+delay+"} is malformed" {
+			t.Fatalf(`result: {%v} but expected {%v}`, err, "delay {"+delay+"} is malformed")
+		}
+	}
+}
@@ -0,0 +1,222 @@
+package internal
+
+import (
+	"errors"
+	"fmt"
+	"io/fs"
+	"net/http"
+	"time"
+
+	"github.com/joakim-ribier/go-utils/pkg/logsutil"
+	"github.com/joakim-ribier/go-utils/pkg/slicesutil"
+)
+
+// ReadOnlyError is returned when an update is requested on a read-only {Mocker}
+type ReadOnlyError struct{}
+
+func (e ReadOnlyError) Error() string {
+	return "mocked requests are read-only"
+}
+
+// EmbeddedMock is a read-only {Mocker} implementation which serves the mocked requests of a file system
+// (e.g. an {embed.FS} bundled in the binary) with the same layout as the storage ("{id}.json" files, namespaces as directories),
+// the updates return a {ReadOnlyError}.
+type EmbeddedMock struct {
+	fsys   fs.FS
+	logger logsutil.Logger
+}
+
+// NewEmbeddedMock creates an {EmbeddedMock} struct which reads the mocked requests of the {fsys} file system
+func NewEmbeddedMock(fsys fs.FS, logger logsutil.Logger) EmbeddedMock {
+	return EmbeddedMock{
+		fsys:   fsys,
+		logger: logger.Namespace("embedded-mock"),
+	}
+}
+
+// Get finds the mocked request by {mockId} value on the file system.
+func (m EmbeddedMock) Get(mockId string) (*MockedRequest, error) {
+	mock, err := get[MockedRequest](m.fsys, mockId, m.logger)
+	if err != nil {
+		return nil, err
+	}
+	if mock.isExpired(time.Now()) {
+		return nil, ExpiredError{Id: mockId}
+	}
+	return mock, nil
+}
+
+// Hit finds the mocked request by {mockId} value and returns the mocked request to serve,
+// the number of hits and the sequence are not persisted (the first element of the sequence is always served).
+func (m EmbeddedMock) Hit(mockId string) (*MockedRequest, error) {
+	mock, err := m.Get(mockId)
+	if err != nil {
+		return nil, err
+	}
+
+	served := mock.serve()
+	return &served, nil
+}
+
+// HitMethod returns the mocked request {mockId} to serve (see {EmbeddedMock.Hit}) if it allows the request {method}
+// (see {MockedRequest.AllowMethod}), a "HEAD" request gets the mocked request to serve (see {MockedRequest.peek}).
+func (m EmbeddedMock) HitMethod(mockId, method string) (*MockedRequest, error) {
+	mock, err := m.Get(mockId)
+	if err != nil {
+		return nil, err
+	}
+	if err := mock.AllowMethod(method); err != nil {
+		return nil, err
+	}
+	if method == http.MethodHead {
+		peeked := mock.peek()
+		return &peeked, nil
+	}
+
+	served := mock.serve()
+	return &served, nil
+}
+
+// Reset returns a {ReadOnlyError}.
+func (m EmbeddedMock) Reset(mockId string) error {
+	return ReadOnlyError{}
+}
+
+// List gets all mocked requests on the file system sorted from the most recently created,
+// the files which cannot be read or unmarshaled are skipped.
+func (m EmbeddedMock) List() ([]MockedRequestLight, error) {
+	fileEntries, err := m.readDir()
+	if err != nil {
+		return nil, err
+	}
+
+	mockedRequestsLight := slicesutil.TransformT[fs.DirEntry, MockedRequestLight](fileEntries, func(e fs.DirEntry) (*MockedRequestLight, error) {
+		mockId, err := toMockId(e)
+		if err != nil {
+			return nil, err
+		}
+		return get[MockedRequestLight](m.fsys, mockId, m.logger)
+	})
+
+	return slicesutil.SortT[MockedRequestLight, string](
+		mockedRequestsLight, func(mrl1, mrl2 MockedRequestLight) (string, string) {
+			return sortableTime(mrl2.CreatedAt), sortableTime(mrl1.CreatedAt)
+		}), nil
+}
+
+// readDir reads the root of the file system, a missing root (unknown namespace) has no mocked request.
+func (m EmbeddedMock) readDir() ([]fs.DirEntry, error) {
+	fileEntries, err := fs.ReadDir(m.fsys, ".")
+	if err != nil && !errors.Is(err, fs.ErrNotExist) {
+		m.logger.Error(err, "error to read directory", "fs", m.fsys)
+		return nil, err
+	}
+	return fileEntries, nil
+}
+
+// Search gets the mocked requests which match the {filter}.
+func (m EmbeddedMock) Search(filter SearchFilter) ([]MockedRequestLight, error) {
+	mockedRequestsLight, err := m.List()
+	if err != nil {
+		return nil, err
+	}
+	return search(mockedRequestsLight, filter), nil
+}
+
+// Count counts the mocked requests on the file system (without loading them).
+func (m EmbeddedMock) Count() (int, error) {
+	fileEntries, err := m.readDir()
+	if err != nil {
+		return 0, err
+	}
+
+	return len(slicesutil.FilterT[fs.DirEntry](fileEntries, func(e fs.DirEntry) bool {
+		_, err := toMockId(e)
+		return err == nil
+	})), nil
+}
+
+// Match finds the most recently created mocked request which matches the incoming {req}.
+func (m EmbeddedMock) Match(req *http.Request) (*MockedRequest, error) {
+	fileEntries, err := m.readDir()
+	if err != nil {
+		return nil, err
+	}
+
+	mockedRequests := slicesutil.TransformT[fs.DirEntry, MockedRequest](fileEntries, func(e fs.DirEntry) (*MockedRequest, error) {
+		mockId, err := toMockId(e)
+		if err != nil {
+			return nil, err
+		}
+		return get[MockedRequest](m.fsys, mockId, m.logger)
+	})
+
+	return match(mockedRequests, req)
+}
+
+// New returns a {ReadOnlyError}.
+func (m EmbeddedMock) New(reqParams map[string][]string, reqBody []byte) (*string, error) {
+	return nil, ReadOnlyError{}
+}
+
+// Clone returns a {ReadOnlyError}.
+func (m EmbeddedMock) Clone(mockId string, reqParams map[string][]string) (*string, error) {
+	return nil, ReadOnlyError{}
+}
+
+// Import returns a {ReadOnlyError}.
+func (m EmbeddedMock) Import(mockedRequests []MockedRequest) (int, error) {
+	return 0, ReadOnlyError{}
+}
+
+// Validate returns a {ReadOnlyError}.
+func (m EmbeddedMock) Validate(reqParams map[string][]string, reqBody []byte) error {
+	return ReadOnlyError{}
+}
+
+// Clean returns a {ReadOnlyError}.
+func (m EmbeddedMock) Clean(maxLimit int) (int, error) {
+	return 0, ReadOnlyError{}
+}
+
+// CleanBySize returns a {ReadOnlyError}.
+func (m EmbeddedMock) CleanBySize(maxBytes int64) (int, error) {
+	return 0, ReadOnlyError{}
+}
+
+// CleanExpired does nothing, the expired mocked requests are never served (see {EmbeddedMock.Get}).
+func (m EmbeddedMock) CleanExpired() (int, error) {
+	return 0, nil
+}
+
+// Reload does nothing, the file system is read on each request.
+func (m EmbeddedMock) Reload() error {
+	return nil
+}
+
+// DeleteMany returns a {ReadOnlyError} for each of the {mockIds}.
+func (m EmbeddedMock) DeleteMany(mockIds []string) (int, map[string]string) {
+	errs := map[string]string{}
+	for _, mockId := range mockIds {
+		errs[mockId] = ReadOnlyError{}.Error()
+	}
+	return 0, errs
+}
+
+// DeleteAll returns a {ReadOnlyError}.
+func (m EmbeddedMock) DeleteAll() (int, error) {
+	return 0, ReadOnlyError{}
+}
+
+// ForNamespace returns a {Mocker} which reads the mocked requests of the {namespace} directory of the file system.
+func (m EmbeddedMock) ForNamespace(namespace string) (Mocker, error) {
+	if err := validateNamespace(namespace); err != nil {
+		return nil, err
+	}
+
+	fsys, err := fs.Sub(m.fsys, namespace)
+	if err != nil {
+		return nil, fmt.Errorf("namespace {%s} cannot be read", namespace)
+	}
+	return EmbeddedMock{fsys: fsys, logger: m.logger}, nil
+}
@@ -0,0 +1,90 @@
+package internal
+
+import (
+	"errors"
+	"net/http/httptest"
+	"testing"
+	"testing/fstest"
+)
+
+var embeddedFS = fstest.MapFS{
+	"8d7e5a9e-3c1b-4f25-9a4e-2f0c6b1d7e01.json": {Data: []byte(
+		`{"id":"8d7e5a9e-3c1b-4f25-9a4e-2f0c6b1d7e01","createdAt":"2024-08-01 10:00:00","status":200,"contentType":"text/plain","charset":"UTF-8","body64":"SGVsbG8gV29ybGQ="}`)},
+	"8d7e5a9e-3c1b-4f25-9a4e-2f0c6b1d7e02.json": {Data: []byte(
+		`{"id":"8d7e5a9e-3c1b-4f25-9a4e-2f0c6b1d7e02","createdAt":"2024-08-02 10:00:00","status":201,"contentType":"text/plain","charset":"UTF-8","when":{"method":"GET","path":"/hello"}}`)},
+	"README.md": {Data: []byte("not a mocked request")},
+	"team-a/8d7e5a9e-3c1b-4f25-9a4e-2f0c6b1d7e03.json": {Data: []byte(
+		`{"id":"8d7e5a9e-3c1b-4f25-9a4e-2f0c6b1d7e03","createdAt":"2024-08-03 10:00:00","status":202,"contentType":"text/plain","charset":"UTF-8"}`)},
+}
+
+// TestEmbeddedMockGetAndList calls EmbeddedMock.Get and EmbeddedMock.List,
+// checking for a valid return value.
+func TestEmbeddedMockGetAndList(t *testing.T) {
+	var mocker Mocker = NewEmbeddedMock(embeddedFS, *logger)
+
+	r, err := mocker.Get("8d7e5a9e-3c1b-4f25-9a4e-2f0c6b1d7e01")
+	if err != nil || r.Status != 200 || string(r.Body64) != "Hello World" {
+		t.Fatalf(`result: {%v} but expected {%v}`, r, "Hello World")
+	}
+
+	// test if the mocked request does not exist
+	if r, err := mocker.Get("8d7e5a9e-3c1b-4f25-9a4e-2f0c6b1d7e09"); err == nil {
+		t.Fatalf(`result: {%v} but expected error`, r)
+	}
+
+	values, err := mocker.List()
+	if err != nil || len(values) != 2 ||
+		values[0].Id != "8d7e5a9e-3c1b-4f25-9a4e-2f0c6b1d7e02" || values[1].Id != "8d7e5a9e-3c1b-4f25-9a4e-2f0c6b1d7e01" {
+		t.Fatalf(`result: {%v} but expected {%v}`, values, "2 mocked requests")
+	}
+
+	if nb, err := mocker.Count(); err != nil || nb != 2 {
+		t.Fatalf(`result: {%v} but expected {%v}`, nb, 2)
+	}
+
+	r, err = mocker.Match(httptest.NewRequest("GET", "http://localhost:3333/hello", nil))
+	if err != nil || r.Id != "8d7e5a9e-3c1b-4f25-9a4e-2f0c6b1d7e02" {
+		t.Fatalf(`result: {%v} but expected {%v}`, r, "8d7e5a9e-3c1b-4f25-9a4e-2f0c6b1d7e02")
+	}
+}
+
+// TestEmbeddedMockForNamespace calls EmbeddedMock.ForNamespace,
+// checking for a valid return value.
+func TestEmbeddedMockForNamespace(t *testing.T) {
+	scoped, err := NewEmbeddedMock(embeddedFS, *logger).ForNamespace("team-a")
+	if err != nil {
+		t.Fatalf(err.Error())
+	}
+
+	values, err := scoped.List()
+	if err != nil || len(values) != 1 || values[0].Id != "8d7e5a9e-3c1b-4f25-9a4e-2f0c6b1d7e03" {
+		t.Fatalf(`result: {%v} but expected {%v}`, values, "1 mocked request")
+	}
+
+	// test if an unknown namespace has no mocked request
+	scoped, err = NewEmbeddedMock(embeddedFS, *logger).ForNamespace("team-b")
+	if err != nil {
+		t.Fatalf(err.Error())
+	}
+	if values, err := scoped.List(); err != nil || len(values) != 0 {
+		t.Fatalf(`result: {%v} but expected {%v}`, values, "no mocked request")
+	}
+}
+
+// TestEmbeddedMockIsReadOnly calls EmbeddedMock.New and EmbeddedMock.Clean,
+// checking for a valid return value.
+func TestEmbeddedMockIsReadOnly(t *testing.T) {
+	mocker := NewEmbeddedMock(embeddedFS, *logger)
+
+	if _, err := mocker.New(reqParams, []byte("Hello World")); !errors.As(err, &ReadOnlyError{}) {
+		t.Fatalf(`result: {%v} but expected {%v}`, err, ReadOnlyError{})
+	}
+
+	if _, err := mocker.Clean(1); !errors.As(err, &ReadOnlyError{}) {
+		t.Fatalf(`result: {%v} but expected {%v}`, err, ReadOnlyError{})
+	}
+
+	if nb, err := mocker.Count(); err != nil || nb != 2 {
+		t.Fatalf(`result: {%v} but expected {%v}`, nb, 2)
+	}
+}
@@ -0,0 +1,104 @@
+package internal
+
+import (
+	"errors"
+	"fmt"
+	"io/fs"
+	"time"
+)
+
+// ExpiredError is returned when the mocked request exists but its TTL has elapsed
+type ExpiredError struct {
+	Id string
+}
+
+func (e ExpiredError) Error() string {
+	return fmt.Sprintf("mocked request {%s} has expired", e.Id)
+}
+
+// isExpired returns true if the mocked request has an expiration date before {now}.
+func (m MockedRequestLight) isExpired(now time.Time) bool {
+	if m.ExpiresAt == "" {
+		return false
+	}
+	expiresAt, err := time.Parse(time.RFC3339Nano, m.ExpiresAt)
+	return err == nil && !now.Before(expiresAt)
+}
+
+// newExpiresAt returns the expiration date of a mocked request created now with the {ttl} duration.
+func newExpiresAt(ttl string) (string, error) {
+	duration, err := time.ParseDuration(ttl)
+	if err != nil || duration <= 0 {
+		return "", fmt.Errorf("ttl {%s} must be a positive duration", ttl)
+	}
+	return time.Now().Add(duration).Format(time.RFC3339Nano), nil
+}
+
+// expire removes the expired mocked request {mockId} from the storage.
+func (m Mock) expire(mockId string) error {
+	if err := m.remove(mockId); err != nil && !errors.Is(err, fs.ErrNotExist) {
+		m.logger.Error(err, "error to delete expired data", "mockId", mockId, "workingDirectory", m.workingDirectory)
+	}
+	return ExpiredError{Id: mockId}
+}
+
+// CleanExpired removes the mocked requests (namespaces included) whose TTL has elapsed.
+func (m Mock) CleanExpired() (int, error) {
+	nb, err := m.cleanExpired()
+	if err != nil {
+		return nb, err
+	}
+
+	for _, namespace := range m.namespaces() {
+		scopedNb, err := namespace.cleanExpired()
+		if err != nil {
+			return nb, err
+		}
+		nb = nb + scopedNb
+	}
+	return nb, nil
+}
+
+// cleanExpired removes the mocked requests of the working directory whose TTL has elapsed.
+func (m Mock) cleanExpired() (int, error) {
+	mu := lock(m.workingDirectory)
+	mu.Lock()
+	defer mu.Unlock()
+
+	mockedRequests, err := m.list()
+	if err != nil {
+		return 0, err
+	}
+
+	nb := 0
+	now := time.Now()
+	for _, mockedRequest := range mockedRequests {
+		if !mockedRequest.isExpired(now) {
+			continue
+		}
+		if m.remove(mockedRequest.Id) == nil {
+			nb = nb + 1
+		}
+	}
+	return nb, nil
+}
+
+// CleanExpired removes the mocked requests (namespaces included) whose TTL has elapsed.
+func (m *InMemoryMock) CleanExpired() (int, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	nb := 0
+	now := time.Now()
+	for mockId, mockedRequest := range m.mockedRequests {
+		if mockedRequest.isExpired(now) {
+			delete(m.mockedRequests, mockId)
+			nb = nb + 1
+		}
+	}
+	for _, namespace := range m.namespaces {
+		scopedNb, _ := namespace.CleanExpired()
+		nb = nb + scopedNb
+	}
+	return nb, nil
+}
@@ -0,0 +1,115 @@
+package internal
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"sync"
+	"testing"
+	"time"
+)
+
+// TestGetExpired calls Mock.Get(string),
+// checking for a valid return value.
+func TestGetExpired(t *testing.T) {
+	workingDirectory := t.TempDir()
+	mocker := NewMock(workingDirectory, nil, *logger)
+
+	id, err := mocker.New(map[string][]string{
+		"contentType": {"text/plain"},
+		"charset":     {"UTF-8"},
+		"ttl":         {"100ms"},
+	}, []byte("Hello World"))
+	if err != nil {
+		t.Fatalf(err.Error())
+	}
+
+	if mock, err := mocker.Get(*id); err != nil || mock.ExpiresAt == "" {
+		t.Fatalf(`result: {%v} but expected {%v}`, err, nil)
+	}
+
+	time.Sleep(150 * time.Millisecond)
+
+	var expiredError ExpiredError
+	if _, err := mocker.Hit(*id); !errors.As(err, &expiredError) {
+		t.Fatalf(`result: {%v} but expected {%v}`, err, ExpiredError{Id: *id})
+	}
+	if _, err := os.Stat(workingDirectory + "/" + *id + ".json"); !os.IsNotExist(err) {
+		t.Fatalf(`result: {%v} but expected {%v}`, err, "file removed")
+	}
+}
+
+// TestGetExpiredConcurrently calls Mock.Get(string),
+// checking for a valid return value.
+func TestGetExpiredConcurrently(t *testing.T) {
+	workingDirectory := t.TempDir()
+	mocker := NewMock(workingDirectory, nil, *logger)
+
+	id, err := mocker.New(map[string][]string{
+		"contentType": {"text/plain"},
+		"charset":     {"UTF-8"},
+		"ttl":         {"50ms"},
+	}, []byte("Hello World"))
+	if err != nil {
+		t.Fatalf(err.Error())
+	}
+	time.Sleep(100 * time.Millisecond)
+
+	var wg sync.WaitGroup
+	errs := make(chan error, 10)
+	for range 10 {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			if mock, err := mocker.Get(*id); err == nil {
+				errs <- fmt.Errorf("mocked request {%s} is served", mock.Id)
+			}
+		}()
+	}
+	wg.Wait()
+	close(errs)
+
+	if err := <-errs; err != nil {
+		t.Fatalf(`result: {%v} but expected {%v}`, err, "expired")
+	}
+	if _, err := os.Stat(workingDirectory + "/" + *id + ".json"); !os.IsNotExist(err) {
+		t.Fatalf(`result: {%v} but expected {%v}`, err, "file removed")
+	}
+}
+
+// TestCleanExpired calls InMemoryMock.CleanExpired(),
+// checking for a valid return value.
+func TestCleanExpired(t *testing.T) {
+	mocker := NewInMemoryMock(*logger)
+	params := func(ttl string) map[string][]string {
+		return map[string][]string{"contentType": {"text/plain"}, "charset": {"UTF-8"}, "ttl": {ttl}}
+	}
+
+	mocker.New(params("50ms"), nil)
+	id, _ := mocker.New(params("1h"), nil)
+
+	time.Sleep(100 * time.Millisecond)
+
+	if nb, err := mocker.CleanExpired(); err != nil || nb != 1 {
+		t.Fatalf(`result: {%v} but expected {%v}`, nb, 1)
+	}
+	if nb, _ := mocker.Count(); nb != 1 {
+		t.Fatalf(`result: {%v} but expected {%v}`, nb, 1)
+	}
+	if _, err := mocker.Get(*id); err != nil {
+		t.Fatalf(`result: {%v} but expected {%v}`, err, nil)
+	}
+}
+
+// TestNewWithBadTTL calls Mocker.New,
+// checking for a valid return value.
+func TestNewWithBadTTL(t *testing.T) {
+	for _, ttl := range []string{"wrong", "-1s", "0s"} {
+		_, err := NewInMemoryMock(*logger).New(map[string][]string{
+			"contentType": {"text/plain"}, "charset": {"UTF-8"}, "ttl": {ttl},
+		}, nil)
+		if err == nil || err.Error() != "ttl {"+ttl+"} must be a positive duration" {
+			t.Fatalf(`result: {%v} but expected {%v}`, err, "ttl {"+ttl+"} must be a positive duration")
+		}
+	}
+}
@@ -0,0 +1,34 @@
+package internal
+
+import (
+	"fmt"
+
+	"github.com/joakim-ribier/mockapic/pkg"
+)
+
+// defaultFailureStatus is the status of the injected failures if the failure status is not defined
+const defaultFailureStatus = 500
+
+// ApplyFailure returns the mocked request to serve: the failure status (without body) replaces the response
+// if the random {draw} (in [0, 1)) falls under the failure rate.
+func (m MockedRequest) ApplyFailure(draw float64) MockedRequest {
+	if m.FailureRate <= 0 || draw >= m.FailureRate {
+		return m
+	}
+
+	m.Status = m.FailureStatus
+	m.Body = ""
+	m.Body64 = nil
+	return m
+}
+
+// validateFailure returns an error if the failure rate is not in [0, 1] or if the failure status does not exist.
+func validateFailure(mock MockedRequest) error {
+	if mock.FailureRate < 0 || mock.FailureRate > 1 {
+		return fmt.Errorf("failure rate {%v} must be between 0 and 1", mock.FailureRate)
+	}
+	if _, is := pkg.HTTP_CODES[mock.FailureStatus]; mock.FailureRate > 0 && !is {
+		return fmt.Errorf("failure status {%d} does not exist", mock.FailureStatus)
+	}
+	return nil
+}
@@ -0,0 +1,66 @@
+package internal
+
+import (
+	"testing"
+)
+
+// TestApplyFailure calls MockedRequest.ApplyFailure(float64),
+// checking for a valid return value.
+func TestApplyFailure(t *testing.T) {
+	mock := MockedRequest{
+		MockedRequestLight: MockedRequestLight{MockedRequestHeader: MockedRequestHeader{Status: 200}},
+		FailureRate:        0.25,
+		FailureStatus:      503,
+		Body64:             []byte("Hello World"),
+	}
+
+	tests := []struct {
+		draw     float64
+		expected int
+	}{
+		{0, 503},
+		{0.24, 503},
+		{0.25, 200},
+		{0.99, 200},
+	}
+	for _, test := range tests {
+		if r := mock.ApplyFailure(test.draw); r.Status != test.expected || (r.Status == 503 && r.Body64 != nil) {
+			t.Fatalf(`%v - result: {%v} but expected {%v}`, test.draw, r.Status, test.expected)
+		}
+	}
+
+	// test if the default rate does not inject any failure
+	if r := (MockedRequest{MockedRequestLight: MockedRequestLight{MockedRequestHeader: MockedRequestHeader{Status: 200}}}).ApplyFailure(0); r.Status != 200 {
+		t.Fatalf(`result: {%v} but expected {%v}`, r.Status, 200)
+	}
+}
+
+// TestNewWithFailure calls Mocker.New,
+// checking for a valid return value.
+func TestNewWithFailure(t *testing.T) {
+	mocker := NewInMemoryMock(*logger)
+	params := func(failureRate, failureStatus string) map[string][]string {
+		return map[string][]string{
+			"contentType":   {"text/plain"},
+			"charset":       {"UTF-8"},
+			"failureRate":   {failureRate},
+			"failureStatus": {failureStatus},
+		}
+	}
+
+	id, err := mocker.New(map[string][]string{"contentType": {"text/plain"}, "charset": {"UTF-8"}, "failureRate": {"0.5"}}, nil)
+	if r, _ := mocker.Get(*id); err != nil || r.FailureRate != 0.5 || r.FailureStatus != 500 {
+		t.Fatalf(`result: {%v} but expected {%v}`, r, "failure status 500")
+	}
+
+	tests := map[string][]string{
+		"failure rate {1.5} must be between 0 and 1": {"1.5", "500"},
+		"failure rate {-1} must be between 0 and 1":  {"wrong", "500"},
+		"failure status {999} does not exist":        {"0.5", "999"},
+	}
+	for expected, values := range tests {
+		if _, err := mocker.New(params(values[0], values[1]), nil); err == nil || err.Error() != expected {
+			t.Fatalf(`result: {%v} but expected {%v}`, err, expected)
+		}
+	}
+}
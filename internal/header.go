@@ -0,0 +1,78 @@
+package internal
+
+import (
+	"fmt"
+	"net/http"
+	"slices"
+	"strings"
+)
+
+// hopByHopHeaders are the headers of the connection (RFC 9110) which cannot be forced by a mocked request
+var hopByHopHeaders = []string{"Connection", "Keep-Alive", "Proxy-Connection", "Te", "Transfer-Encoding", "Upgrade"}
+
+// headerNameSpecialChars are the characters (other than letters and digits) allowed in a header name (token of RFC 9110)
+const headerNameSpecialChars = "!#$%&'*+-.^_`|~"
+
+// InvalidHeaderError is returned when a header name of a mocked request is not a valid token
+type InvalidHeaderError struct {
+	Name string
+}
+
+func (e InvalidHeaderError) Error() string {
+	return fmt.Sprintf("header {%s} is malformed", e.Name)
+}
+
+// IsHopByHopHeader returns true if the header {name} is a hop-by-hop header.
+func IsHopByHopHeader(name string) bool {
+	return slices.Contains(hopByHopHeaders, http.CanonicalHeaderKey(name))
+}
+
+// isValidHeaderName returns true if the header {name} is a non-empty token (no space, no control character).
+func isValidHeaderName(name string) bool {
+	if name == "" {
+		return false
+	}
+	for _, c := range name {
+		isAlphaNum := (c >= 'a' && c <= 'z') || (c >= 'A' && c <= 'Z') || (c >= '0' && c <= '9')
+		if !isAlphaNum && !strings.ContainsRune(headerNameSpecialChars, c) {
+			return false
+		}
+	}
+	return true
+}
+
+// validateHeaderNames returns an error if a name of the headers or of the trailers is not a valid header name.
+func validateHeaderNames(mock MockedRequest) error {
+	names := []string{}
+	for name := range mock.Headers {
+		names = append(names, name)
+	}
+	for name := range mock.MultiHeaders {
+		names = append(names, name)
+	}
+	for name := range mock.Trailers {
+		names = append(names, name)
+	}
+
+	slices.Sort(names)
+	for _, name := range names {
+		if !isValidHeaderName(name) {
+			return InvalidHeaderError{Name: name}
+		}
+	}
+	return nil
+}
+
+// stripHopByHopHeaders removes the hop-by-hop headers of the {mock} (see {IsHopByHopHeader}).
+func stripHopByHopHeaders(mock *MockedRequest) {
+	for name := range mock.Headers {
+		if IsHopByHopHeader(name) {
+			delete(mock.Headers, name)
+		}
+	}
+	for name := range mock.MultiHeaders {
+		if IsHopByHopHeader(name) {
+			delete(mock.MultiHeaders, name)
+		}
+	}
+}
@@ -0,0 +1,47 @@
+package internal
+
+import (
+	"testing"
+)
+
+// TestNewWithInvalidHeaderName calls Mocker.New,
+// checking for a valid return value.
+func TestNewWithInvalidHeaderName(t *testing.T) {
+	mocker := NewInMemoryMock(*logger)
+
+	tests := map[string]string{
+		"X Custom":      "header {X Custom} is malformed",
+		"X-Custom\x01":  "header {X-Custom\x01} is malformed",
+		"trailer.X:Sum": "header {X:Sum} is malformed",
+	}
+	for name, expected := range tests {
+		_, err := mocker.New(map[string][]string{"contentType": {"text/plain"}, "charset": {"UTF-8"}, name: {"value"}}, nil)
+		if err == nil || err.Error() != expected {
+			t.Fatalf(`result: {%v} but expected {%v}`, err, expected)
+		}
+	}
+
+	id, err := mocker.New(map[string][]string{"contentType": {"text/plain"}, "charset": {"UTF-8"}, "X-Custom_1.v2": {"value"}}, nil)
+	if r, _ := mocker.Get(*id); err != nil || r.Headers["X-Custom_1.v2"] != "value" {
+		t.Fatalf(`result: {%v} but expected {%v}`, r, "X-Custom_1.v2: value")
+	}
+}
+
+// TestNewWithHopByHopHeaders calls Mocker.New,
+// checking for a valid return value.
+func TestNewWithHopByHopHeaders(t *testing.T) {
+	mocker := NewInMemoryMock(*logger)
+
+	id, err := mocker.New(map[string][]string{
+		"contentType":       {"text/plain"},
+		"charset":           {"UTF-8"},
+		"Connection":        {"close"},
+		"transfer-encoding": {"chunked", "gzip"},
+		"X-Custom":          {"value"},
+	}, nil)
+
+	r, _ := mocker.Get(*id)
+	if err != nil || len(r.Headers) != 1 || r.Headers["X-Custom"] != "value" || len(r.MultiHeaders) != 0 {
+		t.Fatalf(`result: {%v} but expected {%v}`, r, "X-Custom: value")
+	}
+}
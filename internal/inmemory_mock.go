@@ -0,0 +1,308 @@
+package internal
+
+import (
+	"fmt"
+	"net/http"
+	"sync"
+	"time"
+
+	"github.com/joakim-ribier/go-utils/pkg/jsonsutil"
+	"github.com/joakim-ribier/go-utils/pkg/logsutil"
+	"github.com/joakim-ribier/go-utils/pkg/slicesutil"
+)
+
+// InMemoryMock is a {Mocker} implementation which keeps the mocked requests in memory (without any file system access)
+type InMemoryMock struct {
+	mu             *sync.RWMutex
+	mockedRequests map[string]MockedRequest
+	namespaces     map[string]*InMemoryMock
+	logger         logsutil.Logger
+	defaults       mockDefaults
+}
+
+// NewInMemoryMock creates and initializes an empty {InMemoryMock} struct
+func NewInMemoryMock(logger logsutil.Logger, opts ...MockOption) *InMemoryMock {
+	return &InMemoryMock{
+		mu:             &sync.RWMutex{},
+		mockedRequests: map[string]MockedRequest{},
+		namespaces:     map[string]*InMemoryMock{},
+		logger:         logger.Namespace("inmemory-mock"),
+		defaults:       newMockDefaults(opts),
+	}
+}
+
+// Get finds the mocked request by {mockId} value.
+func (m *InMemoryMock) Get(mockId string) (*MockedRequest, error) {
+	m.mu.RLock()
+	mock, is := m.mockedRequests[mockId]
+	m.mu.RUnlock()
+
+	if !is {
+		return nil, fmt.Errorf("mocked request {%s} does not exist", mockId)
+	}
+	if mock.isExpired(time.Now()) {
+		return nil, m.expire(mockId)
+	}
+	return &mock, nil
+}
+
+// expire removes the expired mocked request {mockId} if it's still expired (it can be replaced concurrently).
+func (m *InMemoryMock) expire(mockId string) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	if mock, is := m.mockedRequests[mockId]; is && mock.isExpired(time.Now()) {
+		delete(m.mockedRequests, mockId)
+	}
+	return ExpiredError{Id: mockId}
+}
+
+// Hit finds the mocked request by {mockId} value, increments its number of hits and advances its sequence,
+// it returns the mocked request to serve (the current element of the sequence if defined).
+func (m *InMemoryMock) Hit(mockId string) (*MockedRequest, error) {
+	return m.hit(mockId, "")
+}
+
+// HitMethod hits the mocked request {mockId} (see {InMemoryMock.Hit}) if it allows the request {method}
+// (see {MockedRequest.AllowMethod}), a "HEAD" request gets the mocked request to serve without any hit (see {MockedRequest.peek}).
+func (m *InMemoryMock) HitMethod(mockId, method string) (*MockedRequest, error) {
+	return m.hit(mockId, method)
+}
+
+// hit hits the mocked request {mockId}, the {method} is not checked if it's empty
+func (m *InMemoryMock) hit(mockId, method string) (*MockedRequest, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	mock, is := m.mockedRequests[mockId]
+	if !is {
+		return nil, fmt.Errorf("mocked request {%s} does not exist", mockId)
+	}
+	if mock.isExpired(time.Now()) {
+		delete(m.mockedRequests, mockId)
+		return nil, ExpiredError{Id: mockId}
+	}
+	if method != "" {
+		if err := mock.AllowMethod(method); err != nil {
+			return nil, err
+		}
+		if method == http.MethodHead {
+			peeked := mock.peek()
+			return &peeked, nil
+		}
+	}
+
+	served := mock.serve()
+	m.mockedRequests[mockId] = mock
+
+	return &served, nil
+}
+
+// Reset sets the sequence index of the mocked request {mockId} back to zero.
+func (m *InMemoryMock) Reset(mockId string) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	mock, is := m.mockedRequests[mockId]
+	if !is {
+		return fmt.Errorf("mocked request {%s} does not exist", mockId)
+	}
+
+	mock.SequenceIndex = 0
+	m.mockedRequests[mockId] = mock
+
+	return nil
+}
+
+// List gets all mocked requests sorted from the most recently created.
+func (m *InMemoryMock) List() ([]MockedRequestLight, error) {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+
+	return m.list(), nil
+}
+
+func (m *InMemoryMock) list() []MockedRequestLight {
+	mockedRequestsLight := slicesutil.TransformT[MockedRequest, MockedRequestLight](
+		m.values(), func(mr MockedRequest) (*MockedRequestLight, error) {
+			return &mr.MockedRequestLight, nil
+		})
+
+	return slicesutil.SortT[MockedRequestLight, string](
+		mockedRequestsLight, func(mrl1, mrl2 MockedRequestLight) (string, string) {
+			return sortableKey(mrl2.CreatedAt, mrl2.Id), sortableKey(mrl1.CreatedAt, mrl1.Id)
+		})
+}
+
+// values returns the mocked requests sorted by id (the iteration order of the map is random)
+func (m *InMemoryMock) values() []MockedRequest {
+	mockedRequests := make([]MockedRequest, 0, len(m.mockedRequests))
+	for _, mockedRequest := range m.mockedRequests {
+		mockedRequests = append(mockedRequests, mockedRequest)
+	}
+	return slicesutil.SortT[MockedRequest, string](mockedRequests, func(mr1, mr2 MockedRequest) (string, string) {
+		return mr1.Id, mr2.Id
+	})
+}
+
+// Search gets the mocked requests which match the {filter}.
+func (m *InMemoryMock) Search(filter SearchFilter) ([]MockedRequestLight, error) {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+
+	return search(m.list(), filter), nil
+}
+
+// Count counts the mocked requests.
+func (m *InMemoryMock) Count() (int, error) {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+
+	return len(m.mockedRequests), nil
+}
+
+// Match finds the most recently created mocked request which matches the incoming {req}.
+func (m *InMemoryMock) Match(req *http.Request) (*MockedRequest, error) {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+
+	return match(m.values(), req)
+}
+
+// New creates a new mocked request and returns the new identifier (provided by the "uuid" parameter or random),
+// a {DuplicateIdError} is returned if the identifier already exists.
+func (m *InMemoryMock) New(reqParams map[string][]string, reqBody []byte) (*string, error) {
+	mock, err := newMockedRequest(reqParams, reqBody, m.defaults)
+	if err != nil {
+		return nil, err
+	}
+	if mock.hasIgnoredBody() {
+		m.logger.Info("the body of the mocked request is ignored by its status", "mockId", mock.Id, "status", mock.Status)
+	}
+
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	if _, is := m.mockedRequests[mock.Id]; is {
+		return nil, DuplicateIdError{Id: mock.Id}
+	}
+	m.mockedRequests[mock.Id] = *mock
+
+	return &mock.Id, nil
+}
+
+// Validate runs the same checks as {InMemoryMock.New} without keeping the mocked request.
+func (m *InMemoryMock) Validate(reqParams map[string][]string, reqBody []byte) error {
+	_, err := newMockedRequest(reqParams, reqBody, m.defaults)
+	return err
+}
+
+// DeleteMany removes the mocked requests {mockIds},
+// it returns the number of deleted requests and the error of each request which cannot be deleted.
+func (m *InMemoryMock) DeleteMany(mockIds []string) (int, map[string]string) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	nb := 0
+	errs := map[string]string{}
+	for _, mockId := range mockIds {
+		if err := validateId(mockId); err != nil {
+			errs[mockId] = err.Error()
+			continue
+		}
+		if _, is := m.mockedRequests[mockId]; !is {
+			errs[mockId] = fmt.Sprintf("mocked request {%s} does not exist", mockId)
+			continue
+		}
+		delete(m.mockedRequests, mockId)
+		nb = nb + 1
+	}
+	return nb, errs
+}
+
+// DeleteAll removes all the mocked requests (the namespaces are not removed),
+// it returns the number of deleted requests.
+func (m *InMemoryMock) DeleteAll() (int, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	nb := len(m.mockedRequests)
+	clear(m.mockedRequests)
+	return nb, nil
+}
+
+// Clean removes the x (nb mocked request - max limit) last requests.
+func (m *InMemoryMock) Clean(maxLimit int) (int, error) {
+	if maxLimit < 1 {
+		return 0, nil
+	}
+
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	mockedRequests := m.list()
+
+	nbToDelete := len(mockedRequests) - maxLimit
+	if nbToDelete < 1 {
+		return 0, nil
+	}
+
+	for _, mockedRequest := range mockedRequests[len(mockedRequests)-nbToDelete:] {
+		delete(m.mockedRequests, mockedRequest.Id)
+	}
+	return nbToDelete, nil
+}
+
+// CleanBySize removes the oldest requests until the total size of the requests (JSON encoded, namespaces included)
+// is under the {maxBytes} limit.
+func (m *InMemoryMock) CleanBySize(maxBytes int64) (int, error) {
+	if maxBytes < 1 {
+		return 0, nil
+	}
+
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	type storedRequest struct {
+		mocker    *InMemoryMock
+		mockId    string
+		createdAt string
+		size      int64
+	}
+
+	mockers := []*InMemoryMock{m}
+	for _, namespace := range m.namespaces {
+		namespace.mu.Lock()
+		defer namespace.mu.Unlock()
+		mockers = append(mockers, namespace)
+	}
+
+	var totalSize int64
+	storedRequests := []storedRequest{}
+	for _, mocker := range mockers {
+		for mockId, mockedRequest := range mocker.mockedRequests {
+			data, err := jsonsutil.Marshal(mockedRequest)
+			if err != nil {
+				return 0, err
+			}
+			totalSize += int64(len(data))
+			storedRequests = append(storedRequests, storedRequest{
+				mocker: mocker, mockId: mockId, createdAt: sortableTime(mockedRequest.CreatedAt), size: int64(len(data))})
+		}
+	}
+
+	storedRequests = slicesutil.SortT[storedRequest, string](storedRequests, func(sr1, sr2 storedRequest) (string, string) {
+		return sr1.createdAt, sr2.createdAt
+	})
+
+	nb := 0
+	for _, storedRequest := range storedRequests {
+		if totalSize <= maxBytes {
+			break
+		}
+		delete(storedRequest.mocker.mockedRequests, storedRequest.mockId)
+		totalSize -= storedRequest.size
+		nb = nb + 1
+	}
+	return nb, nil
+}
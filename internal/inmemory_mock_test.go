@@ -0,0 +1,285 @@
+package internal
+
+import (
+	"net/http/httptest"
+	"reflect"
+	"slices"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/joakim-ribier/go-utils/pkg/slicesutil"
+)
+
+var reqParams = map[string][]string{
+	"status":      {"200"},
+	"contentType": {"text/plain"},
+	"charset":     {"UTF-8"},
+	"x-language":  {"golang"},
+}
+
+// TestInMemoryMockNewAndGet calls InMemoryMock.New and InMemoryMock.Get,
+// checking for a valid return value.
+func TestInMemoryMockNewAndGet(t *testing.T) {
+	var mocker Mocker = NewInMemoryMock(*logger)
+
+	id, err := mocker.New(reqParams, []byte("Hello World"))
+	if err != nil {
+		t.Fatalf(err.Error())
+	}
+
+	r, err := mocker.Get(*id)
+	if err != nil {
+		t.Fatalf(err.Error())
+	}
+
+	expected := MockedRequest{
+		MockedRequestLight: MockedRequestLight{
+			MockedRequestHeader: MockedRequestHeader{
+				Status:      200,
+				ContentType: "text/plain",
+				Charset:     "UTF-8",
+				Headers:     map[string]string{"x-language": "golang"},
+			},
+		},
+		Body64: []byte("Hello World"),
+	}
+	if !r.Equals(expected) || r.Id != *id {
+		t.Fatalf(`result: {%v} but expected {%v}`, r, expected)
+	}
+
+	// test if the mocked request does not exist
+	if r, err := mocker.Get("id-does-not-exist"); err == nil {
+		t.Fatalf(`result: {%v} but expected error`, r)
+	}
+}
+
+// TestInMemoryMockNewWithBadRequest calls InMemoryMock.New,
+// checking for a valid return value.
+func TestInMemoryMockNewWithBadRequest(t *testing.T) {
+	mocker := NewInMemoryMock(*logger)
+
+	_, err := mocker.New(map[string][]string{"status": {"-1"}}, nil)
+	if err == nil || err.Error() != "status {-1} does not exist (nearest supported: 100, 101, 102)" {
+		t.Fatalf(`result: {%v} but expected {%v}`, err, "status does not exist")
+	}
+
+	if r, _ := mocker.Count(); r != 0 {
+		t.Fatalf(`result: {%v} but expected {%v}`, r, 0)
+	}
+}
+
+// TestInMemoryMockHit calls InMemoryMock.Hit,
+// checking for a valid return value.
+func TestInMemoryMockHit(t *testing.T) {
+	mocker := NewInMemoryMock(*logger)
+	id, _ := mocker.New(reqParams, nil)
+
+	var wg sync.WaitGroup
+	for i := 0; i < 20; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			mocker.Hit(*id)
+		}()
+	}
+	wg.Wait()
+
+	if r, err := mocker.Get(*id); err != nil || r.Hits != 20 {
+		t.Fatalf(`result: {%v} but expected {%v}`, r, 20)
+	}
+
+	// test if the mocked request does not exist
+	if r, err := mocker.Hit("id-does-not-exist"); err == nil {
+		t.Fatalf(`result: {%v} but expected error`, r)
+	}
+}
+
+// TestInMemoryMockHitWithSequence calls InMemoryMock.Hit and InMemoryMock.Reset,
+// checking for a valid return value.
+func TestInMemoryMockHitWithSequence(t *testing.T) {
+	mocker := NewInMemoryMock(*logger)
+	id, err := mocker.New(map[string][]string{
+		"status":      {"200"},
+		"contentType": {"text/plain"},
+		"charset":     {"UTF-8"},
+		"sequence":    {"true"},
+	}, []byte(`[{"status":503},{"status":200}]`))
+	if err != nil {
+		t.Fatalf(err.Error())
+	}
+
+	statuses := []int{}
+	for i := 0; i < 3; i++ {
+		r, _ := mocker.Hit(*id)
+		statuses = append(statuses, r.Status)
+	}
+	if !reflect.DeepEqual(statuses, []int{503, 200, 503}) {
+		t.Fatalf(`result: {%v} but expected {%v}`, statuses, []int{503, 200, 503})
+	}
+
+	mocker.Reset(*id)
+	if r, _ := mocker.Hit(*id); r.Status != 503 {
+		t.Fatalf(`result: {%v} but expected {%v}`, r, 503)
+	}
+
+	// test if the mocked request does not exist
+	if err := mocker.Reset("id-does-not-exist"); err == nil {
+		t.Fatalf(`result: {%v} but expected error`, err)
+	}
+}
+
+// TestInMemoryMockListAndCount calls InMemoryMock.List and InMemoryMock.Count,
+// checking for a valid return value.
+func TestInMemoryMockListAndCount(t *testing.T) {
+	mocker := NewInMemoryMock(*logger)
+	id1, _ := mocker.New(reqParams, nil)
+	id2, _ := mocker.New(reqParams, nil)
+	setCreatedAt(mocker, *id1, "1970-01-01 00:00:01")
+
+	r, err := mocker.List()
+	if err != nil || len(r) != 2 || r[0].Id != *id2 || r[1].Id != *id1 {
+		t.Fatalf(`result: {%v} but expected {%v}`, r, []string{*id2, *id1})
+	}
+
+	if r, err := mocker.Count(); err != nil || r != 2 {
+		t.Fatalf(`result: {%v} but expected {%v}`, r, 2)
+	}
+
+	if r, err := mocker.Search(SearchFilter{Since: time.Now().Add(-time.Hour)}); err != nil || len(r) != 1 || r[0].Id != *id2 {
+		t.Fatalf(`result: {%v} but expected {%v}`, r, []string{*id2})
+	}
+}
+
+// TestInMemoryMockListWithSameCreationDate calls InMemoryMock.List,
+// checking for a valid return value.
+func TestInMemoryMockListWithSameCreationDate(t *testing.T) {
+	mocker := NewInMemoryMock(*logger)
+	ids := []string{}
+	for i := 0; i < 10; i++ {
+		id, _ := mocker.New(reqParams, nil)
+		setCreatedAt(mocker, *id, "1970-01-01 00:00:01")
+		ids = append(ids, *id)
+	}
+	slices.Sort(ids)
+	slices.Reverse(ids)
+
+	// test if the id breaks the tie (whatever the iteration order of the map)
+	for i := 0; i < 10; i++ {
+		r, _ := mocker.List()
+		if got := slicesutil.TransformT[MockedRequestLight, string](r, func(mrl MockedRequestLight) (*string, error) {
+			return &mrl.Id, nil
+		}); !slices.Equal(got, ids) {
+			t.Fatalf(`result: {%v} but expected {%v}`, got, ids)
+		}
+	}
+}
+
+// TestInMemoryMockMatch calls InMemoryMock.Match,
+// checking for a valid return value.
+func TestInMemoryMockMatch(t *testing.T) {
+	mocker := NewInMemoryMock(*logger)
+	mocker.New(reqParams, nil)
+	id, _ := mocker.New(map[string][]string{
+		"status":      {"200"},
+		"contentType": {"text/plain"},
+		"charset":     {"UTF-8"},
+		"when.path":   {"/api/*"},
+	}, nil)
+
+	r, err := mocker.Match(httptest.NewRequest("GET", "http://localhost:3333/api/users", nil))
+	if err != nil || r.Id != *id {
+		t.Fatalf(`result: {%v} but expected {%v}`, r, *id)
+	}
+
+	// test if no mocked request matches
+	if r, err := mocker.Match(httptest.NewRequest("GET", "http://localhost:3333/users", nil)); err == nil {
+		t.Fatalf(`result: {%v} but expected error`, r)
+	}
+}
+
+// TestInMemoryMockClean calls InMemoryMock.Clean,
+// checking for a valid return value.
+func TestInMemoryMockClean(t *testing.T) {
+	mocker := NewInMemoryMock(*logger)
+	id1, _ := mocker.New(reqParams, nil)
+	id2, _ := mocker.New(reqParams, nil)
+	id3, _ := mocker.New(reqParams, nil)
+	setCreatedAt(mocker, *id1, "1970-01-01 00:00:01")
+	setCreatedAt(mocker, *id2, "1970-01-01 00:00:02")
+
+	// test if the max limit is < 0
+	if r, err := mocker.Clean(-1); r != 0 || err != nil {
+		t.Fatalf(`result: {%v} but expected {%v}`, r, 0)
+	}
+
+	// test if the max limit is > to the total nb mocked request
+	if r, err := mocker.Clean(100); r != 0 || err != nil {
+		t.Fatalf(`result: {%v} but expected {%v}`, r, 0)
+	}
+
+	r, err := mocker.Clean(1)
+	if r != 2 || err != nil {
+		t.Fatalf(`result: {%v} but expected {%v}`, r, 2)
+	}
+
+	if r, _ := mocker.List(); len(r) != 1 || r[0].Id != *id3 {
+		t.Fatalf(`result: {%v} but expected {%v}`, r, []string{*id3})
+	}
+}
+
+// TestInMemoryMockCleanBySize calls InMemoryMock.CleanBySize,
+// checking for a valid return value.
+func TestInMemoryMockCleanBySize(t *testing.T) {
+	mocker := NewInMemoryMock(*logger)
+	id1, _ := mocker.New(reqParams, nil)
+	id2, _ := mocker.New(reqParams, nil)
+	setCreatedAt(mocker, *id1, "1970-01-01 00:00:01")
+
+	// test if the max size is < 0
+	if r, err := mocker.CleanBySize(-1); r != 0 || err != nil {
+		t.Fatalf(`result: {%v} but expected {%v}`, r, 0)
+	}
+
+	r, err := mocker.CleanBySize(1)
+	if r != 2 || err != nil {
+		t.Fatalf(`result: {%v} but expected {%v}`, r, 2)
+	}
+
+	id3, _ := mocker.New(reqParams, nil)
+	if r, err := mocker.CleanBySize(1 << 20); r != 0 || err != nil {
+		t.Fatalf(`result: {%v} but expected {%v}`, r, 0)
+	}
+
+	if r, _ := mocker.List(); len(r) != 1 || r[0].Id != *id3 || *id2 == *id3 {
+		t.Fatalf(`result: {%v} but expected {%v}`, r, []string{*id3})
+	}
+}
+
+// TestInMemoryMockDeleteMany calls InMemoryMock.DeleteMany,
+// checking for a valid return value.
+func TestInMemoryMockDeleteMany(t *testing.T) {
+	mocker := NewInMemoryMock(*logger)
+	id1, _ := mocker.New(reqParams, nil)
+	id2, _ := mocker.New(reqParams, nil)
+
+	nb, errs := mocker.DeleteMany([]string{*id1, *id1, "malformed"})
+
+	expected := map[string]string{
+		*id1:        "mocked request {" + *id1 + "} does not exist",
+		"malformed": "mocked request id {malformed} is malformed",
+	}
+	if nb != 1 || !reflect.DeepEqual(errs, expected) {
+		t.Fatalf(`result: {%v, %v} but expected {%v, %v}`, nb, errs, 1, expected)
+	}
+
+	if r, _ := mocker.List(); len(r) != 1 || r[0].Id != *id2 {
+		t.Fatalf(`result: {%v} but expected {%v}`, r, []string{*id2})
+	}
+}
+
+func setCreatedAt(mocker *InMemoryMock, mockId, createdAt string) {
+	mock := mocker.mockedRequests[mockId]
+	mock.CreatedAt = createdAt
+	mocker.mockedRequests[mockId] = mock
+}
@@ -0,0 +1,40 @@
+package internal
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+)
+
+// JSONSyntaxError is a JSON parse error with the position (line and column) of the invalid character
+type JSONSyntaxError struct {
+	Line   int
+	Column int
+	Msg    string
+}
+
+func (e JSONSyntaxError) Error() string {
+	return fmt.Sprintf("%s at line %d, column %d", e.Msg, e.Line, e.Column)
+}
+
+// newJSONSyntaxError converts the {err} of the parsed {data} to a {JSONSyntaxError},
+// it returns false if the {err} is not a JSON syntax error.
+func newJSONSyntaxError(data []byte, err error) (JSONSyntaxError, bool) {
+	var syntaxError *json.SyntaxError
+	if !errors.As(err, &syntaxError) {
+		return JSONSyntaxError{}, false
+	}
+
+	// the offset is the number of bytes read when the error occurred, the last one is the invalid character
+	offset := min(max(int(syntaxError.Offset)-1, 0), len(data))
+	line, column := 1, 1
+	for _, c := range data[:offset] {
+		if c == '\n' {
+			line, column = line+1, 1
+		} else {
+			column++
+		}
+	}
+
+	return JSONSyntaxError{Line: line, Column: column, Msg: syntaxError.Error()}, true
+}
@@ -0,0 +1,29 @@
+package internal
+
+import (
+	"encoding/json"
+	"errors"
+	"testing"
+)
+
+// TestNewJSONSyntaxError calls newJSONSyntaxError([]byte, error),
+// checking for a valid return value.
+func TestNewJSONSyntaxError(t *testing.T) {
+	tests := map[string]string{
+		`{"id": 1,`:           "unexpected end of JSON input at line 1, column 9",
+		"{\n  \"id\": x\n}":   "invalid character 'x' looking for beginning of value at line 2, column 9",
+		`{"id": "a` + "\t\"}": "invalid character '\\t' in string at line 1, column 10",
+		`["a" "b"]`:           `invalid character '"' after array element at line 1, column 6`,
+	}
+	for data, expected := range tests {
+		var value any
+		err := json.Unmarshal([]byte(data), &value)
+		if syntaxError, is := newJSONSyntaxError([]byte(data), err); !is || syntaxError.Error() != expected {
+			t.Fatalf(`result: {%v} but expected {%v}`, syntaxError, expected)
+		}
+	}
+
+	if _, is := newJSONSyntaxError([]byte(`{}`), errors.New("not a syntax error")); is {
+		t.Fatalf(`result: {%v} but expected {%v}`, is, false)
+	}
+}
@@ -0,0 +1,89 @@
+package internal
+
+import (
+	"bufio"
+	"encoding/json"
+	"fmt"
+	"io"
+	"strings"
+)
+
+// jsonlFlushInterval is the number of exported lines between two flushes of the writer
+const jsonlFlushInterval = 100
+
+// jsonlImportBatchSize is the number of imported lines kept in memory before they are created
+const jsonlImportBatchSize = 100
+
+// ExportJSONL writes each mocked request of {mockedRequestLights} (full content) as one JSON line to {w}
+// without loading them all in memory, the {flush} function is called every {jsonlFlushInterval} lines,
+// it returns the number of exported requests.
+func ExportJSONL(mocker Mocker, mockedRequestLights []MockedRequestLight, w io.Writer, flush func()) (int, error) {
+	nb := 0
+	for _, mrl := range mockedRequestLights {
+		// the mocked requests removed in the meantime are skipped
+		mock, err := mocker.Get(mrl.Id)
+		if err != nil {
+			continue
+		}
+
+		line, err := json.Marshal(mock)
+		if err != nil {
+			return nb, err
+		}
+		if _, err := w.Write(append(line, '\n')); err != nil {
+			return nb, err
+		}
+
+		nb = nb + 1
+		if nb%jsonlFlushInterval == 0 {
+			flush()
+		}
+	}
+	flush()
+	return nb, nil
+}
+
+// ImportJSONL reads the mocked requests (one JSON object by line) of {r} and creates the ones which do not exist yet
+// by batches of {jsonlImportBatchSize} lines, each line must be a valid mocked request (see {toImportedMockedRequest}),
+// the batches created before an invalid line are kept. It returns the number of created requests.
+func ImportJSONL(mocker Mocker, r io.Reader, maxLineSize int) (int, error) {
+	scanner := bufio.NewScanner(r)
+	scanner.Buffer(make([]byte, 0, 64<<10), maxLineSize)
+
+	nb := 0
+	mockedRequests := make([]MockedRequest, 0, jsonlImportBatchSize)
+	flush := func() error {
+		created, err := mocker.Import(mockedRequests)
+		nb = nb + created
+		mockedRequests = mockedRequests[:0]
+		return err
+	}
+
+	for i := 1; scanner.Scan(); i++ {
+		line := strings.TrimSpace(scanner.Text())
+		if line == "" {
+			continue
+		}
+
+		var mock MockedRequest
+		if err := json.Unmarshal([]byte(line), &mock); err != nil {
+			return nb, fmt.Errorf("line[%d]: line must be a JSON mocked request", i)
+		}
+		mock, err := toImportedMockedRequest(mock)
+		if err != nil {
+			return nb, fmt.Errorf("line[%d]: %w", i, err)
+		}
+		mockedRequests = append(mockedRequests, mock)
+
+		if len(mockedRequests) == jsonlImportBatchSize {
+			if err := flush(); err != nil {
+				return nb, err
+			}
+		}
+	}
+	if err := scanner.Err(); err != nil {
+		return nb, err
+	}
+
+	return nb, flush()
+}
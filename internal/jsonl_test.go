@@ -0,0 +1,89 @@
+package internal
+
+import (
+	"bytes"
+	"fmt"
+	"strings"
+	"testing"
+)
+
+// TestExportImportJSONL calls ExportJSONL(Mocker, []MockedRequestLight, io.Writer, func())
+// and ImportJSONL(Mocker, io.Reader, int), checking for a valid return value.
+func TestExportImportJSONL(t *testing.T) {
+	source := NewMock(t.TempDir(), nil, *logger)
+	ids := []string{}
+	for _, body := range [][]byte{[]byte("Hello World"), nil, {0x89, 0x50, 0x4e, 0x47}} {
+		id, err := source.New(reqParams, body)
+		if err != nil {
+			t.Fatalf(err.Error())
+		}
+		ids = append(ids, *id)
+	}
+	source.Hit(ids[0])
+
+	mockedRequestLights, _ := source.List()
+	var buf bytes.Buffer
+	flushed := 0
+	nb, err := ExportJSONL(source, mockedRequestLights, &buf, func() { flushed++ })
+	if err != nil || nb != 3 || strings.Count(buf.String(), "\n") != 3 || flushed != 1 {
+		t.Fatalf(`result: {%v} but expected {%v}`, buf.String(), "3 lines")
+	}
+
+	target := NewInMemoryMock(*logger)
+	if nb, err := ImportJSONL(target, strings.NewReader(buf.String()), 1<<20); err != nil || nb != 3 {
+		t.Fatalf(`result: {%v} but expected {%v}`, err, 3)
+	}
+	for _, id := range ids {
+		expected, _ := source.Get(id)
+		if r, err := target.Get(id); err != nil || !r.Equals(*expected) || r.CreatedAt != expected.CreatedAt || r.Hits != expected.Hits {
+			t.Fatalf(`result: {%v} but expected {%v}`, r, expected)
+		}
+	}
+
+	// the existing mocked requests are skipped
+	if nb, err := ImportJSONL(target, strings.NewReader(buf.String()), 1<<20); err != nil || nb != 0 {
+		t.Fatalf(`result: {%v} but expected {%v}`, err, 0)
+	}
+}
+
+// TestImportJSONLWithBadLine calls ImportJSONL(Mocker, io.Reader, int),
+// checking for a valid return value.
+func TestImportJSONLWithBadLine(t *testing.T) {
+	tests := map[string]string{
+		"line[3]: line must be a JSON mocked request":                             `{"id":"00000000-0000-0000-0000-000000000001","contentType":"text/plain","charset":"UTF-8"}` + "\n\nwrong",
+		"line[1]: status {999} does not exist (nearest supported: 508, 510, 511)": `{"id":"00000000-0000-0000-0000-000000000001","status":999,"contentType":"text/plain","charset":"UTF-8"}`,
+	}
+	for expected, data := range tests {
+		mocker := NewInMemoryMock(*logger)
+		if _, err := ImportJSONL(mocker, strings.NewReader(data), 1<<20); err == nil || err.Error() != expected {
+			t.Fatalf(`result: {%v} but expected {%v}`, err, expected)
+		}
+		if nb, _ := mocker.Count(); nb != 0 {
+			t.Fatalf(`result: {%v} but expected {%v}`, nb, 0)
+		}
+	}
+}
+
+// TestImportJSONLByBatches calls ImportJSONL(Mocker, io.Reader, int),
+// checking for a valid return value.
+func TestImportJSONLByBatches(t *testing.T) {
+	var data strings.Builder
+	for i := 1; i <= jsonlImportBatchSize+10; i++ {
+		data.WriteString(fmt.Sprintf(`{"id":"00000000-0000-0000-0000-%012d","contentType":"text/plain","charset":"UTF-8"}`+"\n", i))
+	}
+
+	mocker := NewInMemoryMock(*logger)
+	if nb, err := ImportJSONL(mocker, strings.NewReader(data.String()), 1<<20); err != nil || nb != jsonlImportBatchSize+10 {
+		t.Fatalf(`result: {%v} but expected {%v}`, nb, jsonlImportBatchSize+10)
+	}
+
+	// test if the batches created before an invalid line are kept
+	mocker = NewInMemoryMock(*logger)
+	nb, err := ImportJSONL(mocker, strings.NewReader(data.String()+"wrong"), 1<<20)
+	if err == nil || nb != jsonlImportBatchSize {
+		t.Fatalf(`result: {%v} but expected {%v}`, nb, jsonlImportBatchSize)
+	}
+	if count, _ := mocker.Count(); count != jsonlImportBatchSize {
+		t.Fatalf(`result: {%v} but expected {%v}`, count, jsonlImportBatchSize)
+	}
+}
@@ -0,0 +1,103 @@
+package internal
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"net/http"
+	"path"
+	"regexp"
+	"strings"
+	"sync"
+)
+
+// Matcher represents the conditions that an incoming request must fulfil to be served by a mocked request
+type Matcher struct {
+	Method       string            `json:"method,omitempty"`
+	Path         string            `json:"path,omitempty"`
+	PathRegex    string            `json:"pathRegex,omitempty"`
+	QueryParams  map[string]string `json:"queryParams,omitempty"`
+	BodyContains string            `json:"bodyContains,omitempty"`
+}
+
+// Match returns true if the {req} fulfils all the defined conditions of the matcher.
+func (m Matcher) Match(req *http.Request) bool {
+	if m.Method != "" && !strings.EqualFold(m.Method, req.Method) {
+		return false
+	}
+
+	if m.Path != "" {
+		if matched, err := path.Match(m.Path, req.URL.Path); err != nil || !matched {
+			return false
+		}
+	}
+
+	if m.PathRegex != "" {
+		if re, err := compilePathRegex(m.PathRegex); err != nil || !re.MatchString(req.URL.Path) {
+			return false
+		}
+	}
+
+	query := req.URL.Query()
+	for key, value := range m.QueryParams {
+		if !query.Has(key) || query.Get(key) != value {
+			return false
+		}
+	}
+
+	if m.BodyContains != "" && !bytes.Contains(peekBody(req), []byte(m.BodyContains)) {
+		return false
+	}
+
+	return true
+}
+
+// peekBody reads the body of the {req} and restores it so it can be read again by the next matchers.
+func peekBody(req *http.Request) []byte {
+	if req.Body == nil {
+		return nil
+	}
+
+	body, err := io.ReadAll(req.Body)
+	req.Body = io.NopCloser(bytes.NewReader(body))
+	if err != nil {
+		return nil
+	}
+	return body
+}
+
+// validate returns an error if the path pattern or the path regex is malformed.
+func (m Matcher) validate() error {
+	if _, err := path.Match(m.Path, ""); err != nil {
+		return fmt.Errorf("path pattern {%s} is malformed", m.Path)
+	}
+	if _, err := compilePathRegex(m.PathRegex); err != nil {
+		return fmt.Errorf("path regex {%s} is malformed", m.PathRegex)
+	}
+	return nil
+}
+
+// pathRegexes contains the compiled path regexes by expression to compile them only once
+var pathRegexes sync.Map
+
+// clearPathRegexes removes all the compiled path regexes
+func clearPathRegexes() {
+	pathRegexes.Range(func(expr, _ any) bool {
+		pathRegexes.Delete(expr)
+		return true
+	})
+}
+
+// compilePathRegex returns the compiled {expr} which must match the whole request path.
+func compilePathRegex(expr string) (*regexp.Regexp, error) {
+	if re, is := pathRegexes.Load(expr); is {
+		return re.(*regexp.Regexp), nil
+	}
+
+	re, err := regexp.Compile("^(?:" + expr + ")$")
+	if err != nil {
+		return nil, err
+	}
+	pathRegexes.Store(expr, re)
+	return re, nil
+}
@@ -0,0 +1,84 @@
+package internal
+
+import (
+	"io"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+// TestMatcherMatch calls Matcher.Match(*http.Request),
+// checking for a valid return value.
+func TestMatcherMatch(t *testing.T) {
+	matcher := Matcher{
+		Method:      "GET",
+		Path:        "/api/users/*",
+		QueryParams: map[string]string{"active": "true"},
+	}
+
+	if r := matcher.Match(httptest.NewRequest("GET", "http://localhost:3333/api/users/1?active=true", nil)); !r {
+		t.Fatalf(`result: {%v} but expected {%v}`, r, true)
+	}
+
+	// test if the method is different
+	if r := matcher.Match(httptest.NewRequest("POST", "http://localhost:3333/api/users/1?active=true", nil)); r {
+		t.Fatalf(`result: {%v} but expected {%v}`, r, false)
+	}
+
+	// test if the path does not match
+	if r := matcher.Match(httptest.NewRequest("GET", "http://localhost:3333/api/groups/1?active=true", nil)); r {
+		t.Fatalf(`result: {%v} but expected {%v}`, r, false)
+	}
+
+	// test if a query param is missing
+	if r := matcher.Match(httptest.NewRequest("GET", "http://localhost:3333/api/users/1", nil)); r {
+		t.Fatalf(`result: {%v} but expected {%v}`, r, false)
+	}
+}
+
+// TestMatcherMatchWithEmptyMatcher calls Matcher.Match(*http.Request),
+// checking for a valid return value.
+func TestMatcherMatchWithEmptyMatcher(t *testing.T) {
+	if r := (Matcher{}).Match(httptest.NewRequest("DELETE", "http://localhost:3333/anything", nil)); !r {
+		t.Fatalf(`result: {%v} but expected {%v}`, r, true)
+	}
+}
+
+// TestMatcherMatchWithBodyContains calls Matcher.Match(*http.Request),
+// checking for a valid return value.
+func TestMatcherMatchWithBodyContains(t *testing.T) {
+	matcher := Matcher{Method: "POST", BodyContains: `"action":"delete"`}
+
+	req := httptest.NewRequest("POST", "http://localhost:3333/api/users", strings.NewReader(`{"id":1,"action":"delete"}`))
+	if r := matcher.Match(req); !r {
+		t.Fatalf(`result: {%v} but expected {%v}`, r, true)
+	}
+
+	// test if the body is restored for the next matchers
+	if body, _ := io.ReadAll(req.Body); string(body) != `{"id":1,"action":"delete"}` {
+		t.Fatalf(`result: {%v} but expected {%v}`, string(body), `{"id":1,"action":"delete"}`)
+	}
+
+	// test if the body does not contain the value
+	req = httptest.NewRequest("POST", "http://localhost:3333/api/users", strings.NewReader(`{"id":1,"action":"create"}`))
+	if r := matcher.Match(req); r {
+		t.Fatalf(`result: {%v} but expected {%v}`, r, false)
+	}
+}
+
+// TestMatcherMatchWithPathRegex calls Matcher.Match(*http.Request),
+// checking for a valid return value.
+func TestMatcherMatchWithPathRegex(t *testing.T) {
+	matcher := Matcher{Method: "GET", PathRegex: `/users/\d+`}
+
+	if r := matcher.Match(httptest.NewRequest("GET", "http://localhost:3333/users/42", nil)); !r {
+		t.Fatalf(`result: {%v} but expected {%v}`, r, true)
+	}
+
+	// test if the path does not match the regex (the whole path must match)
+	for _, target := range []string{"/users/abc", "/users/42/orders", "/api/users/42"} {
+		if r := matcher.Match(httptest.NewRequest("GET", "http://localhost:3333"+target, nil)); r {
+			t.Fatalf(`%s - result: {%v} but expected {%v}`, target, r, false)
+		}
+	}
+}
@@ -2,14 +2,26 @@ package internal
 
 import (
 	"bytes"
+	"encoding/base64"
+	"encoding/json"
+	"encoding/xml"
+	"errors"
 	"fmt"
+	"io"
 	"io/fs"
+	"net/http"
+	"net/url"
 	"os"
+	"path/filepath"
 	"reflect"
+	"slices"
+	"strconv"
+	"strings"
+	"sync"
 	"time"
 
 	"github.com/google/uuid"
-	"github.com/joakim-ribier/go-utils/pkg/iosutil"
+	"github.com/joakim-ribier/go-utils/pkg/genericsutil"
 	"github.com/joakim-ribier/go-utils/pkg/jsonsutil"
 	"github.com/joakim-ribier/go-utils/pkg/logsutil"
 	"github.com/joakim-ribier/go-utils/pkg/slicesutil"
@@ -18,22 +30,72 @@ import (
 )
 
 type MockedRequestHeader struct {
-	Status      int               `json:"status,omitempty"`
-	ContentType string            `json:"contentType,omitempty"`
-	Charset     string            `json:"charset,omitempty"`
-	Headers     map[string]string `json:"headers,omitempty"`
+	Status       int                 `json:"status,omitempty"`
+	ContentType  string              `json:"contentType,omitempty"`
+	Charset      string              `json:"charset,omitempty"`
+	Location     string              `json:"location,omitempty"`
+	Headers      map[string]string   `json:"headers,omitempty"`
+	MultiHeaders map[string][]string `json:"multiHeaders,omitempty"`
 }
 
+// canonicalize sets the content type and the charset to the casing of the supported values
+// (see {pkg.CONTENT_TYPES} and {pkg.CHARSET}), e.g. "Application/JSON" to "application/json" and "utf-8" to "UTF-8".
+func (h *MockedRequestHeader) canonicalize() {
+	h.ContentType = canonicalValue(pkg.CONTENT_TYPES, h.ContentType)
+	h.Charset = canonicalValue(pkg.CHARSET, h.Charset)
+}
+
+// canonicalValue returns the element of the {values} equal to the {value} ignoring the case,
+// or the {value} itself if there is none (rejected by the validation).
+func canonicalValue(values []string, value string) string {
+	if i := slices.IndexFunc(values, func(v string) bool { return strings.EqualFold(v, value) }); i > -1 {
+		return values[i]
+	}
+	return value
+}
+
+// redirectStatus are the status codes which require a location
+var redirectStatus = []int{301, 302, 303, 307, 308}
+
 type MockedRequestLight struct {
-	Id        string `json:"id,omitempty"`
-	CreatedAt string `json:"createdAt,omitempty"`
+	Id             string   `json:"id,omitempty"`
+	CreatedAt      string   `json:"createdAt,omitempty"`
+	Hits           int      `json:"hits,omitempty"`
+	LastAccessedAt string   `json:"lastAccessedAt,omitempty"`
+	CreatedBy      string   `json:"createdBy,omitempty"`
+	Tags           []string `json:"tags,omitempty"`
+	Collection     string   `json:"collection,omitempty"`
+	ExpiresAt      string   `json:"expiresAt,omitempty"`
 	MockedRequestHeader
 }
 
 type MockedRequest struct {
 	MockedRequestLight
-	Body   string `json:"body,omitempty"`
-	Body64 []byte `json:"body64,omitempty"`
+	StatusText         string            `json:"statusText,omitempty"`
+	Method             string            `json:"method,omitempty"`
+	When               *Matcher          `json:"when,omitempty"`
+	Conditions         []Condition       `json:"conditions,omitempty"`
+	Templated          bool              `json:"templated,omitempty"`
+	Sequence           []MockedRequest   `json:"sequence,omitempty"`
+	SequenceIndex      int               `json:"sequenceIndex,omitempty"`
+	Pool               []MockedRequest   `json:"pool,omitempty"`
+	Variants           []MockedRequest   `json:"variants,omitempty"`
+	Trailers           map[string]string `json:"trailers,omitempty"`
+	FailureRate        float64           `json:"failureRate,omitempty"`
+	FailureStatus      int               `json:"failureStatus,omitempty"`
+	StatusWeights      map[int]float64   `json:"statusWeights,omitempty"`
+	StatusBodies       map[int]string    `json:"statusBodies,omitempty"`
+	Frames             []string          `json:"frames,omitempty"`
+	FrameDelay         string            `json:"frameDelay,omitempty"`
+	Delay              string            `json:"delay,omitempty"`
+	DelayPerKB         string            `json:"delayPerKB,omitempty"`
+	ChunkSize          int               `json:"chunkSize,omitempty"`
+	ChunkDelay         string            `json:"chunkDelay,omitempty"`
+	BandwidthKbps      int               `json:"bandwidthKbps,omitempty"`
+	ForceContentLength *int              `json:"forceContentLength,omitempty"`
+	BodyURL            string            `json:"bodyURL,omitempty"`
+	Body               string            `json:"body,omitempty"`
+	Body64             []byte            `json:"body64,omitempty"`
 }
 
 type PredefinedMockedRequest struct {
@@ -41,52 +103,187 @@ type PredefinedMockedRequest struct {
 }
 
 func (m PredefinedMockedRequest) toMockedRequest() *MockedRequest {
-	body := m.Body64
+	mock := m.MockedRequest
 	if len(m.Body) > 0 {
-		body = []byte(m.Body)
-	}
-	return &MockedRequest{
-		MockedRequestLight: MockedRequestLight{
-			MockedRequestHeader: m.MockedRequestHeader,
-			Id:                  m.Id},
-		Body64: body,
+		mock.Body64 = []byte(m.Body)
+		mock.Body = ""
 	}
+	return &mock
 }
 
 // Equals returns true if the two requests are equal
 func (m MockedRequest) Equals(arg MockedRequest) bool {
 	return m.Status == arg.Status &&
+		m.StatusText == arg.StatusText &&
+		m.Method == arg.Method &&
 		m.ContentType == arg.ContentType &&
 		m.Charset == arg.Charset &&
+		m.Location == arg.Location &&
 		m.Body == arg.Body &&
+		m.BodyURL == arg.BodyURL &&
 		bytes.Equal(m.Body64, arg.Body64) &&
-		reflect.DeepEqual(m.Headers, arg.Headers)
+		reflect.DeepEqual(m.Headers, arg.Headers) &&
+		reflect.DeepEqual(m.MultiHeaders, arg.MultiHeaders) &&
+		reflect.DeepEqual(m.When, arg.When) &&
+		reflect.DeepEqual(m.Conditions, arg.Conditions) &&
+		m.Templated == arg.Templated &&
+		reflect.DeepEqual(m.Sequence, arg.Sequence) &&
+		reflect.DeepEqual(m.Pool, arg.Pool) &&
+		reflect.DeepEqual(m.Variants, arg.Variants) &&
+		reflect.DeepEqual(m.Frames, arg.Frames) &&
+		m.FrameDelay == arg.FrameDelay &&
+		m.Delay == arg.Delay &&
+		m.DelayPerKB == arg.DelayPerKB &&
+		m.ChunkSize == arg.ChunkSize &&
+		m.ChunkDelay == arg.ChunkDelay &&
+		m.BandwidthKbps == arg.BandwidthKbps &&
+		reflect.DeepEqual(m.ForceContentLength, arg.ForceContentLength) &&
+		reflect.DeepEqual(m.Trailers, arg.Trailers) &&
+		m.FailureRate == arg.FailureRate &&
+		m.FailureStatus == arg.FailureStatus &&
+		reflect.DeepEqual(m.StatusWeights, arg.StatusWeights) &&
+		reflect.DeepEqual(m.StatusBodies, arg.StatusBodies)
+}
+
+// hasIgnoredBody returns true if the mocked request has a body which is never served
+// because of its status ("204 No Content" or "304 Not Modified").
+func (m MockedRequest) hasIgnoredBody() bool {
+	return len(m.Body64) > 0 && (m.Status == 204 || m.Status == 304)
+}
+
+// serve increments the hits and advances the sequence index of the mocked request,
+// it returns the mocked request to serve (the current element of the sequence if defined).
+func (m *MockedRequest) serve() MockedRequest {
+	m.Hits = m.Hits + 1
+	m.LastAccessedAt = timestamp()
+
+	served := m.peek()
+	if len(m.Sequence) > 0 {
+		m.SequenceIndex = (m.SequenceIndex + 1) % len(m.Sequence)
+	}
+	return served
+}
+
+// peek returns the mocked request to serve (the current element of the sequence if defined) as {serve} does,
+// without any hit nor advancing the sequence index (e.g. for a "HEAD" request).
+func (m MockedRequest) peek() MockedRequest {
+	if len(m.Sequence) == 0 {
+		return m
+	}
+
+	element := m.Sequence[m.SequenceIndex%len(m.Sequence)]
+	m.MockedRequestHeader = element.MockedRequestHeader
+	m.Body = ""
+	m.Body64 = element.Body64
+	if len(element.Body) > 0 {
+		m.Body64 = []byte(element.Body)
+	}
+	return m
 }
 
 type Mocker interface {
 	Get(mockId string) (*MockedRequest, error)
+	Stored(mockId string) ([]byte, error)
 	List() ([]MockedRequestLight, error)
 	New(params map[string][]string, body []byte) (*string, error)
+	Clone(mockId string, params map[string][]string) (*string, error)
+	Import(mockedRequests []MockedRequest) (int, error)
+	Validate(params map[string][]string, body []byte) error
 	Clean(maxLimit int) (int, error)
+	CleanBySize(maxBytes int64) (int, error)
+	CleanExpired() (int, error)
+	DeleteMany(mockIds []string) (int, map[string]string)
+	DeleteAll() (int, error)
+	Match(req *http.Request) (*MockedRequest, error)
+	Hit(mockId string) (*MockedRequest, error)
+	HitMethod(mockId, method string) (*MockedRequest, error)
+	Count() (int, error)
+	Reset(mockId string) error
+	Search(filter SearchFilter) ([]MockedRequestLight, error)
+	ForNamespace(namespace string) (Mocker, error)
+	Reload() error
 }
 
 type Mock struct {
 	workingDirectory         string
 	logger                   logsutil.Logger
 	predefinedMockedRequests []PredefinedMockedRequest
+	defaults                 mockDefaults
+	cache                    *mockCache
 }
 
-func NewMock(workingDirectory string, predefinedMockedRequests []PredefinedMockedRequest, logger logsutil.Logger) Mock {
+// mockDefaults contains the content type and the charset of the new mocked requests which do not define them,
+// the size of the cache of the parsed mocked requests (see {WithCacheSize}) and the storage format (see {WithCompression})
+type mockDefaults struct {
+	contentType string
+	charset     string
+	cacheSize   int
+	compress    bool
+}
+
+// MockOption configures the optional settings of a {Mock} or an {InMemoryMock}
+type MockOption func(*mockDefaults)
+
+// WithDefaults sets the content type and the charset of the new mocked requests which do not define them,
+// the values which are not supported (see {pkg.CONTENT_TYPES} and {pkg.CHARSET}) are ignored
+func WithDefaults(contentType, charset string) MockOption {
+	return func(d *mockDefaults) {
+		if contentType = canonicalValue(pkg.CONTENT_TYPES, contentType); slicesutil.Exist(pkg.CONTENT_TYPES, contentType) {
+			d.contentType = contentType
+		}
+		if charset = canonicalValue(pkg.CHARSET, charset); slicesutil.Exist(pkg.CHARSET, charset) {
+			d.charset = charset
+		}
+	}
+}
+
+// WithCacheSize keeps the {size} most recently used mocked requests in memory to avoid reading the storage on each request,
+// the cache is disabled if the {size} is not positive (ignored by the {InMemoryMock}).
+func WithCacheSize(size int) MockOption {
+	return func(d *mockDefaults) {
+		d.cacheSize = size
+	}
+}
+
+func newMockDefaults(opts []MockOption) mockDefaults {
+	defaults := mockDefaults{}
+	for _, opt := range opts {
+		opt(&defaults)
+	}
+	return defaults
+}
+
+// locks contains a mutex by working directory to synchronize the updates (write lock)
+// and the enumerations (read lock) of the stored requests
+var locks sync.Map
+
+func lock(workingDirectory string) *sync.RWMutex {
+	mu, _ := locks.LoadOrStore(workingDirectory, &sync.RWMutex{})
+	return mu.(*sync.RWMutex)
+}
+
+func NewMock(workingDirectory string, predefinedMockedRequests []PredefinedMockedRequest, logger logsutil.Logger, opts ...MockOption) Mock {
+	defaults := newMockDefaults(opts)
 	return Mock{
 		workingDirectory:         workingDirectory,
 		logger:                   logger.Namespace("mock"),
-		predefinedMockedRequests: predefinedMockedRequests}
+		predefinedMockedRequests: predefinedMockedRequests,
+		defaults:                 defaults,
+		cache:                    cacheFor(workingDirectory, defaults.cacheSize)}
 }
 
-// Get finds the mocked request by {mockId} value on the storage or in the predefined requests.
+// Get finds the mocked request by {mockId} value in the cache, on the storage or in the predefined requests.
 func (m Mock) Get(mockId string) (*MockedRequest, error) {
-	mock, err := get[MockedRequest](m.workingDirectory, mockId, m.logger)
+	// the read lock prevents to cache a request read before a concurrent hit is persisted
+	mu := lock(m.workingDirectory)
+	mu.RLock()
+	mock, err := m.read(mockId)
+	mu.RUnlock()
+
 	if mock != nil {
+		if mock.isExpired(time.Now()) {
+			return m.getExpired(mockId)
+		}
 		return mock, nil
 	}
 
@@ -98,23 +295,272 @@ func (m Mock) Get(mockId string) (*MockedRequest, error) {
 	return nil, err
 }
 
-func get[T any](workingDirectory, mockId string, logger logsutil.Logger) (*T, error) {
-	bytes, err := iosutil.Load(workingDirectory + "/" + mockId + ".json")
+// getExpired removes the mocked request {mockId} read as expired under the write lock,
+// it's read again as it can be removed or replaced concurrently (returned if it's not expired anymore).
+func (m Mock) getExpired(mockId string) (*MockedRequest, error) {
+	mu := lock(m.workingDirectory)
+	mu.Lock()
+	defer mu.Unlock()
+
+	mock, err := m.read(mockId)
+	if mock == nil {
+		return nil, err
+	}
+	if mock.isExpired(time.Now()) {
+		return nil, m.expire(mockId)
+	}
+	return mock, nil
+}
+
+// Hit finds the mocked request by {mockId} value, increments its number of hits, advances its sequence and persists it,
+// it returns the mocked request to serve (the current element of the sequence if defined).
+func (m Mock) Hit(mockId string) (*MockedRequest, error) {
+	return m.hit(mockId, "")
+}
+
+// HitMethod hits the mocked request {mockId} (see {Mock.Hit}) if it allows the request {method} (see {MockedRequest.AllowMethod}),
+// the method is checked in the same lock and a "HEAD" request gets the mocked request to serve without any hit (see {MockedRequest.peek}).
+func (m Mock) HitMethod(mockId, method string) (*MockedRequest, error) {
+	return m.hit(mockId, method)
+}
+
+// hit hits the mocked request {mockId}, the {method} is not checked if it's empty
+func (m Mock) hit(mockId, method string) (*MockedRequest, error) {
+	mu := lock(m.workingDirectory)
+	mu.Lock()
+	defer mu.Unlock()
+
+	mock, err := m.read(mockId)
+	if mock != nil {
+		if mock.isExpired(time.Now()) {
+			return nil, m.expire(mockId)
+		}
+		if method != "" {
+			if err := mock.AllowMethod(method); err != nil {
+				return nil, err
+			}
+			if method == http.MethodHead {
+				peeked := mock.peek()
+				return &peeked, nil
+			}
+		}
+		served := mock.serve()
+		if err := m.write(mock); err != nil {
+			return nil, err
+		}
+		return &served, nil
+	}
+
+	// the predefined requests are not persisted so their hits and sequence index are only kept in memory
+	if i := m.findPredefined(mockId); i > -1 {
+		if method != "" {
+			if err := m.predefinedMockedRequests[i].AllowMethod(method); err != nil {
+				return nil, err
+			}
+			if method == http.MethodHead {
+				return PredefinedMockedRequest{MockedRequest: m.predefinedMockedRequests[i].peek()}.toMockedRequest(), nil
+			}
+		}
+		served := m.predefinedMockedRequests[i].serve()
+		return PredefinedMockedRequest{MockedRequest: served}.toMockedRequest(), nil
+	}
+
+	return nil, err
+}
+
+// Reset sets the sequence index of the mocked request {mockId} back to zero.
+func (m Mock) Reset(mockId string) error {
+	mu := lock(m.workingDirectory)
+	mu.Lock()
+	defer mu.Unlock()
+
+	mock, err := m.read(mockId)
+	if mock != nil {
+		mock.SequenceIndex = 0
+		return m.write(mock)
+	}
+
+	if i := m.findPredefined(mockId); i > -1 {
+		m.predefinedMockedRequests[i].SequenceIndex = 0
+		return nil
+	}
+
+	return err
+}
+
+// DeleteMany removes the mocked requests {mockIds} from the storage (the predefined requests cannot be deleted),
+// it returns the number of deleted requests and the error of each request which cannot be deleted.
+func (m Mock) DeleteMany(mockIds []string) (int, map[string]string) {
+	mu := lock(m.workingDirectory)
+	mu.Lock()
+	defer mu.Unlock()
+
+	nb := 0
+	errs := map[string]string{}
+	for _, mockId := range mockIds {
+		if err := validateId(mockId); err != nil {
+			errs[mockId] = err.Error()
+			continue
+		}
+		if m.findPredefined(mockId) > -1 {
+			errs[mockId] = fmt.Sprintf("mocked request {%s} is predefined and cannot be deleted", mockId)
+			continue
+		}
+
+		if err := m.remove(mockId); err != nil {
+			if !errors.Is(err, fs.ErrNotExist) {
+				m.logger.Error(err, "error to delete data", "mockId", mockId, "workingDirectory", m.workingDirectory)
+			}
+			errs[mockId] = fmt.Sprintf("mocked request {%s} does not exist", mockId)
+			continue
+		}
+		nb = nb + 1
+	}
+	return nb, errs
+}
+
+// DeleteAll removes all the mocked requests from the storage (the predefined requests cannot be deleted),
+// the namespaces are not removed, it returns the number of deleted requests.
+func (m Mock) DeleteAll() (int, error) {
+	mu := lock(m.workingDirectory)
+	mu.Lock()
+	defer mu.Unlock()
+
+	fileEntries, err := os.ReadDir(m.workingDirectory + "/")
+	if err != nil {
+		m.logger.Error(err, "error to read directory", "workingDirectory", m.workingDirectory)
+		return 0, err
+	}
+
+	m.cache.clear()
+
+	nb := 0
+	for _, e := range fileEntries {
+		if _, err := toMockId(e); err != nil {
+			continue
+		}
+		if err := os.Remove(filepath.Join(m.workingDirectory, e.Name())); err != nil {
+			if !errors.Is(err, fs.ErrNotExist) {
+				m.logger.Error(err, "error to delete data", "file", e.Name(), "workingDirectory", m.workingDirectory)
+			}
+			continue
+		}
+		nb = nb + 1
+	}
+	return nb, nil
+}
+
+// MalformedIdError is returned when a mocked request id is not a valid UUID
+type MalformedIdError struct {
+	Id string
+}
+
+func (e MalformedIdError) Error() string {
+	return fmt.Sprintf("mocked request id {%s} is malformed", e.Id)
+}
+
+// MethodNotAllowedError is returned when a mocked request is called with another method than its own
+type MethodNotAllowedError struct {
+	Id      string
+	Allowed string
+}
+
+func (e MethodNotAllowedError) Error() string {
+	return fmt.Sprintf("mocked request {%s} only allows the method {%s}", e.Id, e.Allowed)
+}
+
+// AllowMethod returns a {MethodNotAllowedError} if the {method} is not the method of the mocked request ("GET" by default),
+// the "HEAD" requests are allowed as the "GET" requests.
+func (m MockedRequest) AllowMethod(method string) error {
+	allowed := stringsutil.OrElse(m.Method, http.MethodGet)
+	if method == allowed || (allowed == http.MethodGet && method == http.MethodHead) {
+		return nil
+	}
+	return MethodNotAllowedError{Id: m.Id, Allowed: allowed}
+}
+
+// DuplicateIdError is returned when a new mocked request id (provided by the caller) already exists
+type DuplicateIdError struct {
+	Id string
+}
+
+func (e DuplicateIdError) Error() string {
+	return fmt.Sprintf("mocked request {%s} already exists", e.Id)
+}
+
+// validateId returns an error if the {mockId} is not a valid UUID.
+func validateId(mockId string) error {
+	if _, err := uuid.Parse(mockId); err != nil || strings.ContainsAny(mockId, `/\`) || strings.Contains(mockId, "..") {
+		return MalformedIdError{Id: mockId}
+	}
+	return nil
+}
+
+// safeMockPath returns the path of the mocked request {mockId} in the {workingDirectory},
+// the {mockId} is rejected before any disk access if it's not a valid UUID (path traversal).
+func safeMockPath(workingDirectory, mockId string) (string, error) {
+	if err := validateId(mockId); err != nil {
+		return "", err
+	}
+	return workingDirectory + "/" + mockId + ".json", nil
+}
+
+// toMockId returns the identifier of the mocked request stored in the {e} entry,
+// the namespaces (directories) and the other files (not ".json" or ".json.gz" regular files) are not mocked requests.
+func toMockId(e fs.DirEntry) (string, error) {
+	if e.IsDir() {
+		return "", fmt.Errorf("{%s} is a namespace", e.Name())
+	}
+	mockId, is := strings.CutSuffix(e.Name(), ".json")
+	if !is {
+		mockId, is = strings.CutSuffix(e.Name(), compressedExtension)
+	}
+	if !e.Type().IsRegular() || !is || mockId == "" {
+		return "", fmt.Errorf("file {%s} is not a mocked request", e.Name())
+	}
+	return mockId, nil
+}
+
+func (m Mock) findPredefined(mockId string) int {
+	return slices.IndexFunc(m.predefinedMockedRequests, func(mr PredefinedMockedRequest) bool { return mr.Id == mockId })
+}
+
+// get loads the mocked request {mockId} ("{mockId}.json" or "{mockId}.json.gz" file) from the {fsys} file system,
+// the {mockId} is rejected before any access if it's not a valid UUID (path traversal).
+func get[T any](fsys fs.FS, mockId string, logger logsutil.Logger) (*T, error) {
+	if err := validateId(mockId); err != nil {
+		return nil, err
+	}
+
+	bytes, err := readMockFile(fsys, mockId)
 	if err != nil {
-		logger.Error(err, "error to load data", "mockId", mockId, "workingDirectory", workingDirectory)
+		// a missing file is not an error worth logging (unknown id or removed in the meantime)
+		if !errors.Is(err, fs.ErrNotExist) {
+			logger.Error(err, "error to load data", "mockId", mockId, "fs", fsys)
+		}
 		return nil, err
 	}
 
 	mock, err := jsonsutil.Unmarshal[T](bytes)
 	if err != nil {
-		logger.Error(err, "error to unmarshal data", "mockId", mockId, "workingDirectory", workingDirectory)
+		logger.Error(err, "error to unmarshal data", "mockId", mockId, "fs", fsys)
 		return nil, err
 	}
 	return &mock, nil
 }
 
-// List gets all mocked requests on the storage and the predefined requests.
+// List gets all mocked requests on the storage and the predefined requests,
+// the files which cannot be read or unmarshaled (logged by {get}) or removed in the meantime are skipped.
 func (m Mock) List() ([]MockedRequestLight, error) {
+	mu := lock(m.workingDirectory)
+	mu.RLock()
+	defer mu.RUnlock()
+
+	return m.list()
+}
+
+// list gets all mocked requests (see {List}), the caller must hold the lock of the working directory.
+func (m Mock) list() ([]MockedRequestLight, error) {
 	fileEntries, err := os.ReadDir(m.workingDirectory + "/")
 	if err != nil {
 		m.logger.Error(err, "error to read directory", "workingDirectory", m.workingDirectory)
@@ -122,11 +568,11 @@ func (m Mock) List() ([]MockedRequestLight, error) {
 	}
 
 	mockedRequestsLight := slicesutil.TransformT[fs.DirEntry, MockedRequestLight](fileEntries, func(e fs.DirEntry) (*MockedRequestLight, error) {
-		var mockId string = ""
-		if len(e.Name()) > 5 {
-			mockId = e.Name()[:len(e.Name())-5]
+		mockId, err := toMockId(e)
+		if err != nil {
+			return nil, err
 		}
-		return get[MockedRequestLight](m.workingDirectory, mockId, m.logger)
+		return get[MockedRequestLight](os.DirFS(m.workingDirectory), mockId, m.logger)
 	})
 
 	if len(m.predefinedMockedRequests) > 0 {
@@ -138,16 +584,141 @@ func (m Mock) List() ([]MockedRequestLight, error) {
 
 	return slicesutil.SortT[MockedRequestLight, string](
 		mockedRequestsLight, func(mrl1, mrl2 MockedRequestLight) (string, string) {
-			return mrl2.CreatedAt, mrl1.CreatedAt
+			return sortableTime(mrl2.CreatedAt), sortableTime(mrl1.CreatedAt)
 		}), nil
 }
 
-// New creates a new mocked request and returns the new identifier.
+// Search gets the mocked requests (on the storage and the predefined requests) which match the {filter}.
+func (m Mock) Search(filter SearchFilter) ([]MockedRequestLight, error) {
+	mockedRequestsLight, err := m.List()
+	if err != nil {
+		return nil, err
+	}
+	return search(mockedRequestsLight, filter), nil
+}
+
+// Count counts the mocked requests on the storage (without loading them) and the predefined requests.
+func (m Mock) Count() (int, error) {
+	mu := lock(m.workingDirectory)
+	mu.RLock()
+	defer mu.RUnlock()
+
+	fileEntries, err := os.ReadDir(m.workingDirectory + "/")
+	if err != nil {
+		m.logger.Error(err, "error to read directory", "workingDirectory", m.workingDirectory)
+		return 0, err
+	}
+
+	nb := len(slicesutil.FilterT[fs.DirEntry](fileEntries, func(e fs.DirEntry) bool {
+		_, err := toMockId(e)
+		return err == nil
+	}))
+	return nb + len(m.predefinedMockedRequests), nil
+}
+
+// Match finds the mocked request which matches the incoming {req} on the storage or in the predefined requests,
+// if several mocked requests match, the most recently created one is returned.
+func (m Mock) Match(req *http.Request) (*MockedRequest, error) {
+	mockedRequests, err := m.all()
+	if err != nil {
+		return nil, err
+	}
+
+	return match(mockedRequests, req)
+}
+
+// match returns the most recently created mocked request of {mockedRequests} which matches the {req},
+// the mocked requests matching an exact path take precedence over those matching only a path regex.
+func match(mockedRequests []MockedRequest, req *http.Request) (*MockedRequest, error) {
+	now := time.Now()
+	matched := slicesutil.FilterT[MockedRequest](mockedRequests, func(mr MockedRequest) bool {
+		return mr.When != nil && !mr.isExpired(now) && mr.When.Match(req)
+	})
+	if len(matched) == 0 {
+		return nil, fmt.Errorf("no mocked request matches {%s %s}", req.Method, req.URL.Path)
+	}
+
+	if slices.ContainsFunc(matched, func(mr MockedRequest) bool { return mr.When.Path != "" }) {
+		matched = slicesutil.FilterT[MockedRequest](matched, func(mr MockedRequest) bool {
+			return mr.When.Path != "" || mr.When.PathRegex == ""
+		})
+	}
+
+	matched = slicesutil.SortT[MockedRequest, string](matched, func(mr1, mr2 MockedRequest) (string, string) {
+		return sortableKey(mr2.CreatedAt, mr2.Id), sortableKey(mr1.CreatedAt, mr1.Id)
+	})
+	return &matched[0], nil
+}
+
+// all gets all the full mocked requests on the storage and the predefined requests.
+func (m Mock) all() ([]MockedRequest, error) {
+	mu := lock(m.workingDirectory)
+	mu.RLock()
+	defer mu.RUnlock()
+
+	fileEntries, err := os.ReadDir(m.workingDirectory + "/")
+	if err != nil {
+		m.logger.Error(err, "error to read directory", "workingDirectory", m.workingDirectory)
+		return nil, err
+	}
+
+	mockedRequests := slicesutil.TransformT[fs.DirEntry, MockedRequest](fileEntries, func(e fs.DirEntry) (*MockedRequest, error) {
+		mockId, err := toMockId(e)
+		if err != nil {
+			return nil, err
+		}
+		return get[MockedRequest](os.DirFS(m.workingDirectory), mockId, m.logger)
+	})
+
+	for _, predefinedMockedRequest := range m.predefinedMockedRequests {
+		mockedRequests = append(mockedRequests, *predefinedMockedRequest.toMockedRequest())
+	}
+
+	return mockedRequests, nil
+}
+
+// New creates a new mocked request and returns the new identifier (provided by the "uuid" parameter or random),
+// a {DuplicateIdError} is returned if the identifier already exists.
 func (m Mock) New(reqParams map[string][]string, reqBody []byte) (*string, error) {
+	mock, err := newMockedRequest(reqParams, reqBody, m.defaults)
+	if err != nil {
+		return nil, err
+	}
+	if mock.hasIgnoredBody() {
+		m.logger.Info("the body of the mocked request is ignored by its status", "mockId", mock.Id, "status", mock.Status)
+	}
+
+	mu := lock(m.workingDirectory)
+	mu.Lock()
+	defer mu.Unlock()
+
+	if err := validateId(mock.Id); err != nil {
+		return nil, err
+	}
+	if m.exists(mock.Id) || m.findPredefined(mock.Id) > -1 {
+		return nil, DuplicateIdError{Id: mock.Id}
+	}
+
+	if err := m.write(mock); err != nil {
+		return nil, err
+	}
+
+	return &mock.Id, nil
+}
+
+// Validate runs the same checks as {Mock.New} without storing the mocked request.
+func (m Mock) Validate(reqParams map[string][]string, reqBody []byte) error {
+	_, err := newMockedRequest(reqParams, reqBody, m.defaults)
+	return err
+}
+
+// newMockedRequest builds and validates a new mocked request from the request parameters and body,
+// the content type and the charset fall back to the {defaults} if they are not defined.
+func newMockedRequest(reqParams map[string][]string, reqBody []byte, defaults mockDefaults) (*MockedRequest, error) {
 	mock := &MockedRequest{
 		MockedRequestLight: MockedRequestLight{
 			Id:                  uuid.NewString(),
-			CreatedAt:           time.Now().Format("2006-01-02 15:04:05"),
+			CreatedAt:           timestamp(),
 			MockedRequestHeader: MockedRequestHeader{Headers: map[string]string{}},
 		},
 		Body64: reqBody,
@@ -160,46 +731,591 @@ func (m Mock) New(reqParams map[string][]string, reqBody []byte) (*string, error
 		return values[0]
 	}
 
+	when := func() *Matcher {
+		if mock.When == nil {
+			mock.When = &Matcher{}
+		}
+		return mock.When
+	}
+
+	isSequence := false
+	isPool := false
+	isBodyValidated := false
+	ttl := ""
+	bodyEncoding := ""
+	conditions := ""
+	variants := ""
+	statusWeights := ""
+	frames := ""
 	for name, values := range reqParams {
 		switch name {
+		case "uuid":
+			mock.Id = getReqParam(values)
+			if err := validateId(mock.Id); err != nil {
+				return nil, err
+			}
 		case "contentType":
 			mock.ContentType = getReqParam(values)
 		case "charset":
 			mock.Charset = getReqParam(values)
 		case "status":
 			mock.Status = stringsutil.Int(getReqParam(values), -1)
+		case "statusText":
+			mock.StatusText = getReqParam(values)
+		case "method":
+			mock.Method = strings.ToUpper(getReqParam(values))
+		case "frames":
+			frames = getReqParam(values)
+		case "frameDelay":
+			mock.FrameDelay = getReqParam(values)
+		case "delay":
+			mock.Delay = getReqParam(values)
+		case "delayPerKB":
+			mock.DelayPerKB = getReqParam(values)
+		case "chunkSize":
+			mock.ChunkSize = stringsutil.Int(getReqParam(values), -1)
+		case "chunkDelay":
+			mock.ChunkDelay = getReqParam(values)
+		case "bandwidthKbps":
+			mock.BandwidthKbps = stringsutil.Int(getReqParam(values), -1)
+		case "forceContentLength":
+			forceContentLength := stringsutil.Int(getReqParam(values), -1)
+			mock.ForceContentLength = &forceContentLength
+		case "location":
+			mock.Location = getReqParam(values)
+		case "bodyURL":
+			mock.BodyURL = getReqParam(values)
+		case "createdBy":
+			mock.CreatedBy = getReqParam(values)
+		case "tags":
+			mock.Tags = parseTags(values)
+		case "collection":
+			mock.Collection = strings.TrimSpace(getReqParam(values))
+		case "templated":
+			mock.Templated = stringsutil.Bool(getReqParam(values))
+		case "sequence":
+			isSequence = stringsutil.Bool(getReqParam(values))
+		case "pool":
+			isPool = stringsutil.Bool(getReqParam(values))
+		case "failureRate":
+			mock.FailureRate = parseFloat(getReqParam(values), -1)
+		case "failureStatus":
+			mock.FailureStatus = stringsutil.Int(getReqParam(values), -1)
+		case "statusWeights":
+			statusWeights = getReqParam(values)
+		case "ttl":
+			ttl = getReqParam(values)
+		case "validateBody":
+			isBodyValidated = stringsutil.Bool(getReqParam(values))
+		case "bodyEncoding":
+			bodyEncoding = getReqParam(values)
+		case "conditions":
+			conditions = getReqParam(values)
+		case "variants":
+			variants = getReqParam(values)
+		case "when.method":
+			when().Method = strings.ToUpper(getReqParam(values))
+		case "when.path":
+			when().Path = getReqParam(values)
+		case "when.pathRegex":
+			when().PathRegex = getReqParam(values)
+		case "when.bodyContains":
+			when().BodyContains = getReqParam(values)
 		default:
-			if len(values) > 0 {
+			if key, is := strings.CutPrefix(name, "when.query."); is {
+				if when().QueryParams == nil {
+					when().QueryParams = map[string]string{}
+				}
+				when().QueryParams[key] = getReqParam(values)
+			} else if key, is := strings.CutPrefix(name, "statusBody."); is {
+				if mock.StatusBodies == nil {
+					mock.StatusBodies = map[int]string{}
+				}
+				mock.StatusBodies[stringsutil.Int(key, -1)] = getReqParam(values)
+			} else if key, is := strings.CutPrefix(name, "trailer."); is {
+				if mock.Trailers == nil {
+					mock.Trailers = map[string]string{}
+				}
+				mock.Trailers[key] = getReqParam(values)
+			} else if len(values) > 1 {
+				if mock.MultiHeaders == nil {
+					mock.MultiHeaders = map[string][]string{}
+				}
+				mock.MultiHeaders[name] = values
+			} else if len(values) > 0 {
 				mock.Headers[name] = values[0]
 			}
 		}
 	}
 
+	if ttl != "" {
+		expiresAt, err := newExpiresAt(ttl)
+		if err != nil {
+			return nil, err
+		}
+		mock.ExpiresAt = expiresAt
+	}
+
+	if mock.FailureRate > 0 && mock.FailureStatus == 0 {
+		mock.FailureStatus = defaultFailureStatus
+	}
+
+	mock.canonicalize()
+	mock.ContentType = stringsutil.OrElse(mock.ContentType, defaults.contentType)
+	if slicesutil.Exist(pkg.IS_DISPLAY_CONTENT, mock.ContentType) {
+		mock.Charset = stringsutil.OrElse(mock.Charset, defaults.charset)
+	}
+
+	reqBody, err := decodeBody(reqBody, bodyEncoding)
+	if err != nil {
+		return nil, err
+	}
+	mock.Body64 = reqBody
+
+	if _, is := reqParams["status"]; !is {
+		mock.Status = getStatusFromBody(reqBody, 200)
+	}
+
+	if conditions != "" {
+		mock.Conditions, err = newConditions(*mock, conditions)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	if variants != "" {
+		mock.Variants, err = newVariants(*mock, variants)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	if statusWeights != "" {
+		mock.StatusWeights, err = newStatusWeights(statusWeights)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	if frames != "" {
+		mock.Frames, err = jsonsutil.Unmarshal[[]string]([]byte(frames))
+		if err != nil || len(mock.Frames) == 0 {
+			return nil, errors.New("frames must be a non-empty JSON array of strings")
+		}
+	}
+
+	if isSequence && isPool {
+		return nil, errors.New("sequence and pool cannot be combined")
+	}
+
+	if isSequence {
+		sequence, err := newResponses("sequence", *mock, reqBody)
+		if err != nil {
+			return nil, err
+		}
+		mock.Sequence = sequence
+		mock.Body64 = nil
+	}
+
+	if isPool {
+		pool, err := newResponses("pool", *mock, reqBody)
+		if err != nil {
+			return nil, err
+		}
+		mock.Pool = pool
+		mock.Body64 = nil
+	}
+
+	stripHopByHopHeaders(mock)
+	if err := validate(*mock); err != nil {
+		return nil, err
+	}
+
+	if isBodyValidated {
+		if err := validateBodies(*mock); err != nil {
+			return nil, err
+		}
+	}
+
+	return mock, nil
+}
+
+// InvalidBodyError is returned when the body of a mocked request does not match its content type
+type InvalidBodyError struct {
+	ContentType string
+	Err         error
+}
+
+func (e InvalidBodyError) Error() string {
+	return fmt.Sprintf("body is not a valid {%s} content: %s", e.ContentType, e.Err.Error())
+}
+
+func (e InvalidBodyError) Unwrap() error {
+	return e.Err
+}
+
+// validateBodies returns an error if the body of the {mock} (or of each element of its sequence or its pool)
+// and the bodies of its variants cannot be parsed according to their JSON or XML content type.
+func validateBodies(mock MockedRequest) error {
+	for i, variant := range mock.Variants {
+		if err := validateBody(variant.ContentType, variant.Body64); err != nil {
+			return fmt.Errorf("variants[%d]: %w", i, err)
+		}
+	}
+
+	for i, element := range mock.Pool {
+		if err := validateBody(element.ContentType, element.Body64); err != nil {
+			return fmt.Errorf("pool[%d]: %w", i, err)
+		}
+	}
+
+	if len(mock.Sequence) == 0 && len(mock.Pool) == 0 {
+		return validateBody(mock.ContentType, mock.Body64)
+	}
+
+	for i, element := range mock.Sequence {
+		if err := validateBody(element.ContentType, element.Body64); err != nil {
+			return fmt.Errorf("sequence[%d]: %w", i, err)
+		}
+	}
+	return nil
+}
+
+// validateBody returns an {InvalidBodyError} if the {body} is not a valid JSON or XML content,
+// the other content types are not validated.
+func validateBody(contentType string, body []byte) error {
+	switch contentType {
+	case "application/json", "text/json":
+		var value any
+		if err := json.Unmarshal(body, &value); err != nil {
+			if syntaxError, is := newJSONSyntaxError(body, err); is {
+				return InvalidBodyError{ContentType: contentType, Err: syntaxError}
+			}
+			return InvalidBodyError{ContentType: contentType, Err: err}
+		}
+	case "application/xml", "text/xml":
+		if err := validateXML(body); err != nil {
+			return InvalidBodyError{ContentType: contentType, Err: err}
+		}
+	}
+	return nil
+}
+
+// validateXML returns an error if the {body} is not a well-formed XML document.
+func validateXML(body []byte) error {
+	decoder := xml.NewDecoder(bytes.NewReader(body))
+	hasElement := false
+	for {
+		token, err := decoder.Token()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return err
+		}
+		if _, is := token.(xml.StartElement); is {
+			hasElement = true
+		}
+	}
+
+	if !hasElement {
+		return errors.New("no root element")
+	}
+	return nil
+}
+
+// parseFloat parses the {value} as a float or returns the {or} value if it's malformed.
+func parseFloat(value string, or float64) float64 {
+	if f, err := strconv.ParseFloat(value, 64); err == nil {
+		return f
+	}
+	return or
+}
+
+// parseTags splits the comma-separated {values} into a list of unique tags.
+func parseTags(values []string) []string {
+	tags := []string{}
+	for _, value := range values {
+		for _, tag := range strings.Split(value, ",") {
+			if tag = strings.TrimSpace(tag); tag != "" && !slices.Contains(tags, tag) {
+				tags = append(tags, tag)
+			}
+		}
+	}
+	return tags
+}
+
+// decodeBody decodes the {body} according to the {encoding} ("base64" or none).
+func decodeBody(body []byte, encoding string) ([]byte, error) {
+	switch encoding {
+	case "":
+		return body, nil
+	case "base64":
+		decoded, err := base64.StdEncoding.DecodeString(strings.TrimSpace(string(body)))
+		if err != nil {
+			return nil, errors.New("body cannot be decoded from base64")
+		}
+		return decoded, nil
+	default:
+		return nil, fmt.Errorf("body encoding {%s} does not exist", encoding)
+	}
+}
+
+// newConditions builds the conditions of the mocked request from the JSON array {value},
+// each condition inherits the status of the {parent} if it's not defined.
+func newConditions(parent MockedRequest, value string) ([]Condition, error) {
+	conditions, err := jsonsutil.Unmarshal[[]Condition]([]byte(value))
+	if err != nil || len(conditions) == 0 {
+		return nil, errors.New("conditions must be a non-empty JSON array of conditions")
+	}
+
+	for i, condition := range conditions {
+		conditions[i].Status = genericsutil.OrElse(condition.Status, func() bool { return condition.Status != 0 }, parent.Status)
+		if len(condition.Body) > 0 {
+			conditions[i].Body64 = []byte(condition.Body)
+			conditions[i].Body = ""
+		}
+	}
+	return conditions, nil
+}
+
+// newResponses builds the {name} (sequence or pool) of mocked requests from the JSON array {reqBody},
+// each element inherits the content type and the charset of the {parent} if they are not defined.
+func newResponses(name string, parent MockedRequest, reqBody []byte) ([]MockedRequest, error) {
+	responses, err := jsonsutil.Unmarshal[[]MockedRequest](reqBody)
+	if syntaxError, is := newJSONSyntaxError(reqBody, err); is {
+		return nil, InvalidBodyError{ContentType: "application/json", Err: syntaxError}
+	}
+	if err != nil || len(responses) == 0 {
+		return nil, fmt.Errorf("%s must be a non-empty JSON array of responses", name)
+	}
+
+	for i, element := range responses {
+		element.Status = genericsutil.OrElse(element.Status, func() bool { return element.Status != 0 }, 200)
+		element.ContentType = stringsutil.OrElse(element.ContentType, parent.ContentType)
+		element.Charset = stringsutil.OrElse(element.Charset, parent.Charset)
+		element.canonicalize()
+		if len(element.Body) > 0 {
+			element.Body64 = []byte(element.Body)
+			element.Body = ""
+		}
+
+		if err := validate(element); err != nil {
+			return nil, fmt.Errorf("%s[%d]: %w", name, i, err)
+		}
+		responses[i] = MockedRequest{
+			MockedRequestLight: MockedRequestLight{MockedRequestHeader: element.MockedRequestHeader},
+			Body64:             element.Body64,
+		}
+	}
+	return responses, nil
+}
+
+// validate returns an error if the {mock} is not a valid mocked request.
+func validate(mock MockedRequest) error {
 	if _, is := pkg.HTTP_CODES[mock.Status]; !is {
-		return nil, fmt.Errorf("status {%d} does not exist", mock.Status)
+		return fmt.Errorf("status {%d} does not exist (nearest supported: %s)",
+			mock.Status, strings.Join(findNearestStatus(mock.Status, 3), ", "))
+	}
+
+	if err := validateHeaderNames(mock); err != nil {
+		return err
+	}
+
+	if strings.ContainsAny(mock.StatusText, "\r\n") {
+		return fmt.Errorf("status text {%s} is malformed", mock.StatusText)
+	}
+
+	if mock.Method != "" && !isValidHeaderName(mock.Method) {
+		return fmt.Errorf("method {%s} is malformed", mock.Method)
+	}
+
+	if slices.Contains(redirectStatus, mock.Status) && mock.Location == "" {
+		return fmt.Errorf("location must be defined for the redirect status {%d}", mock.Status)
 	}
 
 	if !slicesutil.Exist(pkg.CONTENT_TYPES, mock.ContentType) {
-		return nil, fmt.Errorf("content type {%s} does not exist", mock.ContentType)
+		return fmt.Errorf("content type {%s} does not exist", mock.ContentType)
 	}
 
-	if !slicesutil.Exist(pkg.CHARSET, mock.Charset) {
-		return nil, fmt.Errorf("charset {%s} does not exist", mock.Charset)
+	isCharsetRequired := slicesutil.Exist(pkg.IS_DISPLAY_CONTENT, mock.ContentType)
+	if (isCharsetRequired || mock.Charset != "") && !slicesutil.Exist(pkg.CHARSET, mock.Charset) {
+		return fmt.Errorf("charset {%s} does not exist", mock.Charset)
+	}
+
+	if mock.Templated && !slicesutil.Exist(pkg.IS_DISPLAY_CONTENT, mock.ContentType) {
+		return fmt.Errorf("content type {%s} cannot be templated", mock.ContentType)
+	}
+
+	if err := validateFailure(mock); err != nil {
+		return err
+	}
+
+	if err := validateStatusWeights(mock); err != nil {
+		return err
+	}
+
+	if mock.FrameDelay != "" {
+		if _, err := time.ParseDuration(mock.FrameDelay); err != nil {
+			return fmt.Errorf("frame delay {%s} is malformed", mock.FrameDelay)
+		}
+	}
+
+	if mock.Delay != "" {
+		if _, _, err := ParseDelay(mock.Delay); err != nil {
+			return err
+		}
+	}
+
+	if mock.DelayPerKB != "" {
+		if delayPerKB, err := time.ParseDuration(mock.DelayPerKB); err != nil || delayPerKB < 0 {
+			return fmt.Errorf("delay per KB {%s} is malformed", mock.DelayPerKB)
+		}
+	}
+
+	if mock.BodyURL != "" {
+		if bodyURL, err := url.Parse(mock.BodyURL); err != nil || (bodyURL.Scheme != "http" && bodyURL.Scheme != "https") || bodyURL.Host == "" {
+			return fmt.Errorf("body url {%s} must be an absolute http(s) URL", mock.BodyURL)
+		}
+	}
+
+	if mock.ChunkSize < 0 {
+		return fmt.Errorf("chunk size {%d} must be positive", mock.ChunkSize)
+	}
+	if mock.ChunkDelay != "" {
+		if _, err := time.ParseDuration(mock.ChunkDelay); err != nil {
+			return fmt.Errorf("chunk delay {%s} is malformed", mock.ChunkDelay)
+		}
+	}
+
+	if mock.BandwidthKbps < 0 {
+		return fmt.Errorf("bandwidth {%d} must be positive", mock.BandwidthKbps)
+	}
+
+	if mock.ForceContentLength != nil && *mock.ForceContentLength < 0 {
+		return fmt.Errorf("forced content length {%d} must be positive", *mock.ForceContentLength)
+	}
+
+	if mock.When != nil {
+		if err := mock.When.validate(); err != nil {
+			return err
+		}
+	}
+
+	for i, condition := range mock.Conditions {
+		if err := condition.validate(); err != nil {
+			return fmt.Errorf("conditions[%d]: %w", i, err)
+		}
+		if _, is := pkg.HTTP_CODES[condition.Status]; !is {
+			return fmt.Errorf("conditions[%d]: status {%d} does not exist", i, condition.Status)
+		}
+	}
+
+	return nil
+}
+
+// getStatusFromBody returns the "status" field of the {body} if it's a JSON object, otherwise the {or} value.
+func getStatusFromBody(body []byte, or int) int {
+	data, err := jsonsutil.Unmarshal[map[string]any](body)
+	if err != nil {
+		return or
+	}
+
+	if status, is := data["status"].(float64); is && status == float64(int(status)) {
+		return int(status)
+	}
+	return or
+}
+
+// findNearestStatus returns the {nb} supported status codes nearest to the {status}.
+func findNearestStatus(status int, nb int) []string {
+	codes := make([]int, 0, len(pkg.HTTP_CODES))
+	for code := range pkg.HTTP_CODES {
+		codes = append(codes, code)
+	}
+
+	distance := func(code int) int {
+		if code > status {
+			return code - status
+		}
+		return status - code
+	}
+	slices.SortFunc(codes, func(c1, c2 int) int {
+		if d := distance(c1) - distance(c2); d != 0 {
+			return d
+		}
+		return c1 - c2
+	})
+
+	nearest := codes[:min(nb, len(codes))]
+	slices.Sort(nearest)
+
+	return slicesutil.TransformT[int, string](nearest, func(code int) (*string, error) {
+		value := strconv.Itoa(code)
+		return &value, nil
+	})
+}
+
+// write persists the {mock} on the storage ("{id}.json" or "{id}.json.gz" file if compressed),
+// the file of the other format is removed so a request is never stored twice.
+func (m Mock) write(mock *MockedRequest) error {
+	path, err := safeMockPath(m.workingDirectory, mock.Id)
+	if err != nil {
+		return err
 	}
 
 	bytes, err := jsonsutil.Marshal(mock)
 	if err != nil {
-		m.logger.Error(err, "error to nmarshal data", "mock", mock)
-		return nil, err
+		m.logger.Error(err, "error to marshal data", "mock", mock)
+		return err
+	}
+
+	stalePath := path + ".gz"
+	if m.defaults.compress {
+		if bytes, err = compress(bytes); err != nil {
+			m.logger.Error(err, "error to compress data", "mock", mock)
+			return err
+		}
+		path, stalePath = stalePath, path
 	}
 
-	err = iosutil.Write(bytes, m.workingDirectory+"/"+mock.Id+".json")
+	err = writeAtomic(bytes, path)
 	if err != nil {
+		m.cache.remove(mock.Id)
 		m.logger.Error(err, "error to write data", "mock", mock, "workingDirectory", m.workingDirectory)
-		return nil, err
+		return err
+	}
+	if err := os.Remove(stalePath); err != nil && !errors.Is(err, fs.ErrNotExist) {
+		m.logger.Error(err, "error to delete data", "file", stalePath, "workingDirectory", m.workingDirectory)
 	}
+	m.cache.put(*mock)
+	return nil
+}
 
-	return &mock.Id, nil
+// writeAtomic writes the {data} to a temporary file renamed to {path} once complete,
+// so an interrupted write never leaves a partially written file at {path}.
+func writeAtomic(data []byte, path string) error {
+	file, err := os.CreateTemp(filepath.Dir(path), "."+filepath.Base(path)+"-*.tmp")
+	if err != nil {
+		return err
+	}
+	defer os.Remove(file.Name())
+
+	if _, err := file.Write(data); err != nil {
+		file.Close()
+		return err
+	}
+	if err := file.Sync(); err != nil {
+		file.Close()
+		return err
+	}
+	if err := file.Close(); err != nil {
+		return err
+	}
+	if err := os.Chmod(file.Name(), 0644); err != nil {
+		return err
+	}
+	return os.Rename(file.Name(), path)
 }
 
 // Clean removes the x (nb mocked request - max limit) last requests.
@@ -209,7 +1325,11 @@ func (m Mock) Clean(maxLimit int) (int, error) {
 		return nb, nil
 	}
 
-	mockedRequests, err := m.List()
+	mu := lock(m.workingDirectory)
+	mu.Lock()
+	defer mu.Unlock()
+
+	mockedRequests, err := m.list()
 	if err != nil {
 		m.logger.Error(err, "error to list requests", "workingDirectory", m.workingDirectory)
 		return nb, err
@@ -221,7 +1341,83 @@ func (m Mock) Clean(maxLimit int) (int, error) {
 	}
 
 	for _, mockedRequest := range mockedRequests[len(mockedRequests)-nbToDelete:] {
-		if err := os.Remove(m.workingDirectory + "/" + mockedRequest.Id + ".json"); err == nil {
+		if m.remove(mockedRequest.Id) == nil {
+			nb = nb + 1
+		}
+	}
+	return nb, nil
+}
+
+// storedFile represents the file of a mocked request stored in the working directory of the {mocker}
+type storedFile struct {
+	mocker    Mock
+	mockId    string
+	name      string
+	createdAt string
+	size      int64
+}
+
+// storedFiles returns the files of the mocked requests stored in the working directory.
+func (m Mock) storedFiles() ([]storedFile, error) {
+	fileEntries, err := os.ReadDir(m.workingDirectory + "/")
+	if err != nil {
+		m.logger.Error(err, "error to read directory", "workingDirectory", m.workingDirectory)
+		return nil, err
+	}
+
+	return slicesutil.TransformT[fs.DirEntry, storedFile](fileEntries, func(e fs.DirEntry) (*storedFile, error) {
+		mockId, err := toMockId(e)
+		if err != nil {
+			return nil, err
+		}
+		info, err := e.Info()
+		if err != nil {
+			return nil, err
+		}
+		mrl, err := get[MockedRequestLight](os.DirFS(m.workingDirectory), mockId, m.logger)
+		if err != nil {
+			return nil, err
+		}
+		return &storedFile{mocker: m, mockId: mockId, name: e.Name(), createdAt: sortableTime(mrl.CreatedAt), size: info.Size()}, nil
+	}), nil
+}
+
+// CleanBySize removes the oldest requests until the total size of the storage files (namespaces included)
+// is under the {maxBytes} limit.
+func (m Mock) CleanBySize(maxBytes int64) (int, error) {
+	nb := 0
+	if maxBytes < 1 {
+		return nb, nil
+	}
+
+	var totalSize int64
+	storedFiles := []storedFile{}
+	for _, mocker := range append([]Mock{m}, m.namespaces()...) {
+		mu := lock(mocker.workingDirectory)
+		mu.Lock()
+		defer mu.Unlock()
+
+		files, err := mocker.storedFiles()
+		if err != nil {
+			return nb, err
+		}
+		for _, file := range files {
+			totalSize += file.size
+		}
+		storedFiles = append(storedFiles, files...)
+	}
+
+	storedFiles = slicesutil.SortT[storedFile, string](storedFiles, func(sf1, sf2 storedFile) (string, string) {
+		return sf1.createdAt, sf2.createdAt
+	})
+
+	for _, storedFile := range storedFiles {
+		if totalSize <= maxBytes {
+			break
+		}
+		storedFile.mocker.cache.remove(storedFile.mockId)
+		if err := os.Remove(storedFile.mocker.workingDirectory + "/" + storedFile.name); err == nil {
+			totalSize -= storedFile.size
 			nb = nb + 1
 		}
 	}
@@ -1,10 +1,19 @@
 package internal
 
 import (
+	"bytes"
+	"encoding/base64"
+	"errors"
+	"fmt"
 	"log"
+	"net/http"
+	"net/http/httptest"
 	"os"
 	"path/filepath"
+	"reflect"
+	"slices"
 	"strings"
+	"sync"
 	"testing"
 	"time"
 
@@ -62,6 +71,34 @@ func TestGetWithBadRequest(t *testing.T) {
 	}
 }
 
+// TestGetWithPathTraversal calls Mocker.Get,
+// checking for a valid return value.
+func TestGetWithPathTraversal(t *testing.T) {
+	dir := t.TempDir()
+	secret := dir + "/secret.json"
+	if err := iosutil.Write([]byte(`{"id": "secret"}`), secret); err != nil {
+		t.Fatalf(err.Error())
+	}
+
+	mocker := NewMock(dir+"/requests", nil, *logger)
+	for _, mockId := range []string{"../secret", "../../etc/passwd", "..", "a/b"} {
+		var malformedIdError MalformedIdError
+		if r, err := mocker.Get(mockId); r != nil || !errors.As(err, &malformedIdError) {
+			t.Fatalf(`result: {%v} but expected {%v}`, err, "mocked request id is malformed")
+		}
+		if _, err := mocker.Hit(mockId); err == nil || err.Error() != "mocked request id {"+mockId+"} is malformed" {
+			t.Fatalf(`result: {%v} but expected {%v}`, err, "mocked request id is malformed")
+		}
+		if nb, errs := mocker.DeleteMany([]string{mockId}); nb != 0 || len(errs) != 1 {
+			t.Fatalf(`result: {%v} but expected {%v}`, errs, "mocked request id is malformed")
+		}
+	}
+
+	if _, err := os.Stat(secret); err != nil {
+		t.Fatalf(`result: {%v} but expected {%v}`, err, "secret file")
+	}
+}
+
 // TestGet calls Mocker.Get,
 // checking for a valid return value.
 func TestGet(t *testing.T) {
@@ -108,6 +145,275 @@ func TestGetFromLoadedMockedRequest(t *testing.T) {
 	}
 }
 
+// TestHit calls Mocker.Hit,
+// checking for a valid return value.
+func TestHit(t *testing.T) {
+	mockedRequest := createMockedRequest()
+	defer os.Remove(workingDirectory + "/" + mockedRequest.Id + ".json")
+
+	mocker := NewMock(workingDirectory, nil, *logger)
+	for i := 0; i < 3; i++ {
+		if _, err := mocker.Hit(mockedRequest.Id); err != nil {
+			t.Fatalf(err.Error())
+		}
+	}
+
+	r, err := mocker.Get(mockedRequest.Id)
+	if err != nil || r.Hits != 3 || r.LastAccessedAt == "" {
+		t.Fatalf(`result: {%v} but expected {%v}`, r, 3)
+	}
+}
+
+// TestHitConcurrently calls Mocker.Hit,
+// checking for a valid return value.
+func TestHitConcurrently(t *testing.T) {
+	mockedRequest := createMockedRequest()
+	defer os.Remove(workingDirectory + "/" + mockedRequest.Id + ".json")
+
+	var wg sync.WaitGroup
+	for i := 0; i < 20; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			NewMock(workingDirectory, nil, *logger).Hit(mockedRequest.Id)
+		}()
+	}
+	wg.Wait()
+
+	r, err := NewMock(workingDirectory, nil, *logger).Get(mockedRequest.Id)
+	if err != nil || r.Hits != 20 {
+		t.Fatalf(`result: {%v} but expected {%v}`, r, 20)
+	}
+}
+
+// TestHitWithPredefinedMockedRequests calls Mocker.Hit,
+// checking for a valid return value.
+func TestHitWithPredefinedMockedRequests(t *testing.T) {
+	mockedRequest := MockedRequest{
+		MockedRequestLight: MockedRequestLight{
+			Id: "my-own-mocked-request",
+			MockedRequestHeader: MockedRequestHeader{
+				Status:      200,
+				ContentType: "text/plain",
+				Charset:     "UTF-8",
+			},
+		},
+		Body: "Hello World",
+	}
+
+	mocker := NewMock(workingDirectory, []PredefinedMockedRequest{{MockedRequest: mockedRequest}}, *logger)
+	mocker.Hit(mockedRequest.Id)
+	mocker.Hit(mockedRequest.Id)
+
+	r, err := mocker.Get(mockedRequest.Id)
+	if err != nil || r.Hits != 2 {
+		t.Fatalf(`result: {%v} but expected {%v}`, r, 2)
+	}
+
+	// test if the mocked request does not exist
+	if r, err := mocker.Hit("id-does-not-exist"); err == nil {
+		t.Fatalf(`result: {%v} but expected error`, r)
+	}
+}
+
+// TestHitWithSequence calls Mocker.Hit and Mocker.Reset,
+// checking for a valid return value.
+func TestHitWithSequence(t *testing.T) {
+	mocker := NewMock(t.TempDir(), nil, *logger)
+	id, err := mocker.New(map[string][]string{
+		"status":      {"200"},
+		"contentType": {"text/plain"},
+		"charset":     {"UTF-8"},
+		"sequence":    {"true"},
+	}, []byte(`[{"status":503,"body":"unavailable"},{"status":200,"contentType":"application/json","body":"{}"}]`))
+	if err != nil {
+		t.Fatalf(err.Error())
+	}
+
+	expected := []string{"503:text/plain:unavailable", "200:application/json:{}", "503:text/plain:unavailable"}
+	for _, e := range expected {
+		r, err := mocker.Hit(*id)
+		if err != nil || fmt.Sprintf("%d:%s:%s", r.Status, r.ContentType, r.Body64) != e || r.Id != *id {
+			t.Fatalf(`result: {%v} but expected {%v}`, r, e)
+		}
+	}
+
+	if err := mocker.Reset(*id); err != nil {
+		t.Fatalf(err.Error())
+	}
+	if r, _ := mocker.Hit(*id); r.Status != 503 || r.Hits != 4 {
+		t.Fatalf(`result: {%v} but expected {%v}`, r, 503)
+	}
+
+	// test if the mocked request does not exist
+	if err := mocker.Reset("id-does-not-exist"); err == nil {
+		t.Fatalf(`result: {%v} but expected error`, err)
+	}
+}
+
+// TestHitWithPredefinedSequence calls Mocker.Hit and Mocker.Reset,
+// checking for a valid return value.
+func TestHitWithPredefinedSequence(t *testing.T) {
+	mockedRequest := MockedRequest{
+		MockedRequestLight: MockedRequestLight{
+			Id:                  "my-own-mocked-request",
+			MockedRequestHeader: MockedRequestHeader{Status: 200, ContentType: "text/plain", Charset: "UTF-8"},
+		},
+		Sequence: []MockedRequest{
+			{MockedRequestLight: MockedRequestLight{MockedRequestHeader: MockedRequestHeader{Status: 503}}},
+			{MockedRequestLight: MockedRequestLight{MockedRequestHeader: MockedRequestHeader{Status: 200}}, Body: "OK"},
+		},
+	}
+
+	mocker := NewMock(t.TempDir(), []PredefinedMockedRequest{{MockedRequest: mockedRequest}}, *logger)
+	if r, _ := mocker.Hit(mockedRequest.Id); r.Status != 503 {
+		t.Fatalf(`result: {%v} but expected {%v}`, r, 503)
+	}
+	if r, _ := mocker.Hit(mockedRequest.Id); r.Status != 200 || string(r.Body64) != "OK" {
+		t.Fatalf(`result: {%v} but expected {%v}`, r, 200)
+	}
+
+	mocker.Reset(mockedRequest.Id)
+	if r, _ := mocker.Hit(mockedRequest.Id); r.Status != 503 {
+		t.Fatalf(`result: {%v} but expected {%v}`, r, 503)
+	}
+}
+
+// TestNewWithBadSequence calls Mocker.New,
+// checking for a valid return value.
+func TestNewWithBadSequence(t *testing.T) {
+	reqParams := map[string][]string{
+		"status":      {"200"},
+		"contentType": {"text/plain"},
+		"charset":     {"UTF-8"},
+		"sequence":    {"true"},
+	}
+
+	_, err := NewMock(workingDirectory, nil, *logger).New(reqParams, []byte(`{"status":503}`))
+	if err == nil || err.Error() != "sequence must be a non-empty JSON array of responses" {
+		t.Fatalf(`result: {%v} but expected {%v}`, err, "sequence must be a JSON array")
+	}
+
+	_, err = NewMock(workingDirectory, nil, *logger).New(reqParams, []byte(`[{"status":503},{"contentType":"wrong"}]`))
+	if err == nil || err.Error() != "sequence[1]: content type {wrong} does not exist" {
+		t.Fatalf(`result: {%v} but expected {%v}`, err, "content type does not exist")
+	}
+}
+
+// TestNewWithOptionalCharset calls Mocker.New,
+// checking for a valid return value.
+func TestNewWithOptionalCharset(t *testing.T) {
+	mocker := NewMock(t.TempDir(), nil, *logger)
+
+	// test if the charset is not defined for a binary content
+	id, err := mocker.New(map[string][]string{"contentType": {"image/png"}}, []byte{0x89})
+	if err != nil {
+		t.Fatalf(err.Error())
+	}
+	if r, err := mocker.Get(*id); err != nil || r.Charset != "" {
+		t.Fatalf(`result: {%v} but expected {%v}`, r, "no charset")
+	}
+
+	tests := []struct {
+		contentType string
+		charset     string
+		expected    string
+	}{
+		{"text/plain", "", "charset {} does not exist"},
+		{"application/json", "", "charset {} does not exist"},
+		{"image/png", "wrong", "charset {wrong} does not exist"},
+	}
+	for _, test := range tests {
+		reqParams := map[string][]string{"contentType": {test.contentType}}
+		if test.charset != "" {
+			reqParams["charset"] = []string{test.charset}
+		}
+		if _, err := mocker.New(reqParams, nil); err == nil || err.Error() != test.expected {
+			t.Fatalf(`result: {%v} but expected {%v}`, err, test.expected)
+		}
+	}
+}
+
+// TestNewWithConditions calls Mocker.New and Mocker.Get,
+// checking for a valid return value.
+func TestNewWithConditions(t *testing.T) {
+	mocker := NewMock(t.TempDir(), nil, *logger)
+
+	reqParams := map[string][]string{
+		"status":      {"401"},
+		"contentType": {"text/plain"},
+		"charset":     {"UTF-8"},
+		"conditions":  {`[{"header": "Authorization", "status": 200, "body": "ok"}, {"header": "X-Api-Key"}]`},
+	}
+	id, err := mocker.New(reqParams, []byte("unauthorized"))
+	if err != nil {
+		t.Fatalf(err.Error())
+	}
+
+	expected := []Condition{
+		{Header: "Authorization", Status: 200, Body64: []byte("ok")},
+		{Header: "X-Api-Key", Status: 401},
+	}
+	if r, err := mocker.Get(*id); err != nil || !reflect.DeepEqual(r.Conditions, expected) {
+		t.Fatalf(`result: {%v} but expected {%v}`, r, expected)
+	}
+
+	tests := map[string]string{
+		`{"header": "Authorization"}`:                  "conditions must be a non-empty JSON array of conditions",
+		`[{"status": 200}]`:                            "conditions[0]: condition header or bodyContains must be defined",
+		`[{"header": "Authorization", "status": 999}]`: "conditions[0]: status {999} does not exist",
+	}
+	for conditions, expected := range tests {
+		reqParams["conditions"] = []string{conditions}
+		if _, err := mocker.New(reqParams, nil); err == nil || err.Error() != expected {
+			t.Fatalf(`result: {%v} but expected {%v}`, err, expected)
+		}
+	}
+}
+
+// TestNewWithBase64Body calls Mocker.New and Mocker.Get,
+// checking for a valid return value.
+func TestNewWithBase64Body(t *testing.T) {
+	png := "iVBORw0KGgoAAAANSUhEUgAAAAEAAAABCAQAAAC1HAwCAAAAC0lEQVR42mNkYAAAAAYAAjCB0C8AAAAASUVORK5CYII="
+	expected, _ := base64.StdEncoding.DecodeString(png)
+
+	mocker := NewMock(t.TempDir(), nil, *logger)
+	id, err := mocker.New(map[string][]string{
+		"status":       {"200"},
+		"contentType":  {"image/png"},
+		"charset":      {"UTF-8"},
+		"bodyEncoding": {"base64"},
+	}, []byte(png))
+	if err != nil {
+		t.Fatalf(err.Error())
+	}
+
+	r, err := mocker.Get(*id)
+	if err != nil || !bytes.Equal(r.Body64, expected) || !bytes.HasPrefix(r.Body64, []byte("\x89PNG")) {
+		t.Fatalf(`result: {%v} but expected {%v}`, r.Body64, expected)
+	}
+
+	// test if the body is not base64 encoded
+	_, err = mocker.New(map[string][]string{
+		"contentType":  {"image/png"},
+		"charset":      {"UTF-8"},
+		"bodyEncoding": {"base64"},
+	}, []byte("not base64..."))
+	if err == nil || err.Error() != "body cannot be decoded from base64" {
+		t.Fatalf(`result: {%v} but expected {%v}`, err, "body cannot be decoded from base64")
+	}
+
+	// test if the encoding does not exist
+	_, err = mocker.New(map[string][]string{
+		"contentType":  {"image/png"},
+		"charset":      {"UTF-8"},
+		"bodyEncoding": {"hex"},
+	}, []byte(png))
+	if err == nil || err.Error() != "body encoding {hex} does not exist" {
+		t.Fatalf(`result: {%v} but expected {%v}`, err, "body encoding {hex} does not exist")
+	}
+}
+
 // TestListWithBadWorkingDir calls Mocker.List,
 // checking for a valid return value.
 func TestListWithBadWorkingDir(t *testing.T) {
@@ -135,6 +441,62 @@ func TestList(t *testing.T) {
 	}
 }
 
+// TestListConcurrently calls Mocker.List,
+// checking for a valid return value.
+func TestListConcurrently(t *testing.T) {
+	mocker := NewMock(t.TempDir(), nil, *logger)
+
+	done := make(chan struct{})
+	var writers, readers sync.WaitGroup
+	for range 4 {
+		writers.Add(1)
+		go func() {
+			defer writers.Done()
+			for range 25 {
+				if _, err := mocker.New(map[string][]string{"contentType": {"text/plain"}, "charset": {"UTF-8"}}, nil); err != nil {
+					t.Errorf(`result: {%v} but expected {%v}`, err, nil)
+				}
+				mocker.Clean(10)
+			}
+		}()
+	}
+
+	errs := make(chan error, 4)
+	for range 4 {
+		readers.Add(1)
+		go func() {
+			defer readers.Done()
+			for {
+				select {
+				case <-done:
+					return
+				default:
+				}
+				r, err := mocker.List()
+				if err == nil && slicesutil.ExistT[MockedRequestLight](r, func(ml MockedRequestLight) bool { return ml.Id == "" }) {
+					err = errors.New("empty mocked request listed")
+				}
+				if err != nil {
+					errs <- err
+					return
+				}
+			}
+		}()
+	}
+
+	writers.Wait()
+	close(done)
+	readers.Wait()
+	close(errs)
+
+	if err := <-errs; err != nil {
+		t.Fatalf(`result: {%v} but expected {%v}`, err, nil)
+	}
+	if nb, err := mocker.Count(); err != nil || nb != 10 {
+		t.Fatalf(`result: {%v} but expected {%v}`, nb, 10)
+	}
+}
+
 // TestListWithPredefinedMockedRequests calls Mocker.List,
 // checking for a valid return value.
 func TestListWithPredefinedMockedRequests(t *testing.T) {
@@ -162,6 +524,79 @@ func TestListWithPredefinedMockedRequests(t *testing.T) {
 	}
 }
 
+// TestSearch calls Mocker.Search,
+// checking for a valid return value.
+func TestSearch(t *testing.T) {
+	dir := t.TempDir()
+
+	mocker := NewMock(dir, nil, *logger)
+	newMock := func(status, contentType string) string {
+		id, err := mocker.New(map[string][]string{
+			"status":      {status},
+			"contentType": {contentType},
+			"charset":     {"UTF-8"},
+		}, nil)
+		if err != nil {
+			t.Fatalf(err.Error())
+		}
+		return *id
+	}
+	id1 := newMock("404", "application/json")
+	id2 := newMock("404", "text/plain")
+	newMock("200", "application/json")
+
+	// test if the filter is empty
+	if r, err := mocker.Search(SearchFilter{}); err != nil || len(r) != 3 {
+		t.Fatalf(`result: {%v} but expected {%v}`, r, 3)
+	}
+
+	if r, err := mocker.Search(SearchFilter{Status: 404}); err != nil || len(r) != 2 {
+		t.Fatalf(`result: {%v} but expected {%v}`, r, []string{id1, id2})
+	}
+
+	r, err := mocker.Search(SearchFilter{Status: 404, ContentType: "application/json", Since: time.Now().Add(-time.Hour)})
+	if err != nil || len(r) != 1 || r[0].Id != id1 {
+		t.Fatalf(`result: {%v} but expected {%v}`, r, []string{id1})
+	}
+
+	if r, err := mocker.Search(SearchFilter{Since: time.Now().Add(time.Hour)}); err != nil || len(r) != 0 {
+		t.Fatalf(`result: {%v} but expected {%v}`, r, []string{})
+	}
+
+	// test if the working directory does not exist
+	if r, err := NewMock("wrong-directory", nil, *logger).Search(SearchFilter{}); err == nil {
+		t.Fatalf(`result: {%v} but expected error`, r)
+	}
+}
+
+// TestCount calls Mocker.Count,
+// checking for a valid return value.
+func TestCount(t *testing.T) {
+	dir := t.TempDir()
+
+	mocker := NewMock(dir, []PredefinedMockedRequest{{}}, *logger)
+	for i := 0; i < 2; i++ {
+		if _, err := mocker.New(map[string][]string{
+			"status":      {"200"},
+			"contentType": {"text/plain"},
+			"charset":     {"UTF-8"},
+		}, []byte("Hello World")); err != nil {
+			t.Fatalf(err.Error())
+		}
+	}
+	iosutil.Write([]byte("not a mock"), dir+"/application.log")
+
+	r, err := mocker.Count()
+	if err != nil || r != 3 {
+		t.Fatalf(`result: {%v} but expected {%v}`, r, 3)
+	}
+
+	// test if the working directory does not exist
+	if r, err := NewMock("wrong-directory", nil, *logger).Count(); err == nil {
+		t.Fatalf(`result: {%v} but expected error`, r)
+	}
+}
+
 // TestClean calls Mocker.Clean(int),
 // checking for a valid return value.
 func TestClean(t *testing.T) {
@@ -195,6 +630,110 @@ func TestClean(t *testing.T) {
 	}
 }
 
+// TestDeleteMany calls Mocker.DeleteMany,
+// checking for a valid return value.
+func TestDeleteMany(t *testing.T) {
+	predefinedId := uuid.NewString()
+	mocker := NewMock(t.TempDir(), []PredefinedMockedRequest{
+		{MockedRequest: MockedRequest{MockedRequestLight: MockedRequestLight{Id: predefinedId}}},
+	}, *logger)
+
+	id1, _ := mocker.New(reqParams, nil)
+	id2, _ := mocker.New(reqParams, nil)
+	id3, _ := mocker.New(reqParams, nil)
+	missingId := uuid.NewString()
+
+	nb, errs := mocker.DeleteMany([]string{*id1, *id2, missingId, "../malformed", predefinedId})
+
+	expected := map[string]string{
+		missingId:      "mocked request {" + missingId + "} does not exist",
+		"../malformed": "mocked request id {../malformed} is malformed",
+		predefinedId:   "mocked request {" + predefinedId + "} is predefined and cannot be deleted",
+	}
+	if nb != 2 || !reflect.DeepEqual(errs, expected) {
+		t.Fatalf(`result: {%v, %v} but expected {%v, %v}`, nb, errs, 2, expected)
+	}
+
+	if r, _ := mocker.List(); len(r) != 2 || !slicesutil.Exist([]string{r[0].Id, r[1].Id}, *id3) {
+		t.Fatalf(`result: {%v} but expected {%v}`, r, []string{*id3, predefinedId})
+	}
+}
+
+// TestDeleteAll calls Mocker.DeleteAll,
+// checking for a valid return value.
+func TestDeleteAll(t *testing.T) {
+	predefinedId := uuid.NewString()
+	dir := t.TempDir()
+	mocker := NewMock(dir, []PredefinedMockedRequest{
+		{MockedRequest: MockedRequest{MockedRequestLight: MockedRequestLight{Id: predefinedId}}},
+	}, *logger)
+
+	for range 3 {
+		mocker.New(reqParams, nil)
+	}
+	namespaced, _ := mocker.ForNamespace("team-a")
+	namespaced.New(reqParams, nil)
+	iosutil.Write([]byte("not a mocked request"), dir+"/notes.txt")
+
+	if nb, err := mocker.DeleteAll(); err != nil || nb != 3 {
+		t.Fatalf(`result: {%v} but expected {%v}`, nb, 3)
+	}
+	if r, _ := mocker.List(); len(r) != 1 || r[0].Id != predefinedId {
+		t.Fatalf(`result: {%v} but expected {%v}`, r, []string{predefinedId})
+	}
+	if nb, _ := namespaced.Count(); nb != 1 {
+		t.Fatalf(`result: {%v} but expected {%v}`, nb, 1)
+	}
+	if _, err := os.Stat(dir + "/notes.txt"); err != nil {
+		t.Fatalf(`result: {%v} but expected {%v}`, err, nil)
+	}
+}
+
+// TestCleanBySize calls Mocker.CleanBySize,
+// checking for a valid return value.
+func TestCleanBySize(t *testing.T) {
+	dir := t.TempDir()
+	mocker := NewMock(dir, nil, *logger)
+
+	var totalSize int64
+	ids := []string{}
+	for _, createdAt := range []string{"1970-01-01 00:00:02", "1970-01-01 00:00:01", "1970-01-01 00:00:03"} {
+		mock, _ := newMockedRequest(reqParams, []byte("Hello World"), mockDefaults{})
+		mock.CreatedAt = createdAt
+		if err := mocker.write(mock); err != nil {
+			t.Fatalf(err.Error())
+		}
+		info, _ := os.Stat(dir + "/" + mock.Id + ".json")
+		totalSize += info.Size()
+		ids = append(ids, mock.Id)
+	}
+
+	// test if the max size is < 0
+	if r, err := mocker.CleanBySize(-1); r != 0 || err != nil {
+		t.Fatalf(`result: {%v} but expected {%v}`, r, 0)
+	}
+
+	// test if the max size is > to the total size
+	if r, err := mocker.CleanBySize(totalSize); r != 0 || err != nil {
+		t.Fatalf(`result: {%v} but expected {%v}`, r, 0)
+	}
+
+	r, err := mocker.CleanBySize(totalSize - 1)
+	if r != 1 || err != nil {
+		t.Fatalf(`result: {%v} but expected {%v}`, r, 1)
+	}
+
+	// test if the oldest mocked request has been removed
+	if r, _ := mocker.List(); len(r) != 2 || r[0].Id != ids[2] || r[1].Id != ids[0] {
+		t.Fatalf(`result: {%v} but expected {%v}`, r, []string{ids[2], ids[0]})
+	}
+
+	// test if the working directory does not exist
+	if r, err := NewMock("wrong-directory", nil, *logger).CleanBySize(1); err == nil {
+		t.Fatalf(`result: {%v} but expected error`, r)
+	}
+}
+
 // TestNewWithBadRequest calls Mocker.New,
 // checking for a valid return value.
 func TestNewWithBadRequest(t *testing.T) {
@@ -273,67 +812,905 @@ func TestNewWithBadStatus(t *testing.T) {
 	reqBody := "Hello World"
 
 	_, err := NewMock(workingDirectory, nil, *logger).New(reqParams, []byte(reqBody))
-	if err.Error() != "status {-1} does not exist" {
+	if err.Error() != "status {-1} does not exist (nearest supported: 100, 101, 102)" {
+		t.Fatalf(`result: {%v} but expected {%v}`, err.Error(), "status does not exist")
+	}
+
+	reqParams["status"] = []string{"299"}
+	_, err = NewMock(workingDirectory, nil, *logger).New(reqParams, []byte(reqBody))
+	if err.Error() != "status {299} does not exist (nearest supported: 300, 301, 302)" {
 		t.Fatalf(`result: {%v} but expected {%v}`, err.Error(), "status does not exist")
 	}
 }
 
-// TestNewWithBadCharset calls Mocker.New,
+// TestNewWithDefaultStatus calls Mocker.New,
 // checking for a valid return value.
-func TestNewWithBadCharset(t *testing.T) {
+func TestNewWithDefaultStatus(t *testing.T) {
 	reqParams := map[string][]string{
-		"status":      {"200"},
 		"contentType": {"text/plain"},
-		"charset":     {"wrong-charset"},
+		"charset":     {"UTF-8"},
 	}
-	reqBody := "Hello World"
 
-	_, err := NewMock(workingDirectory, nil, *logger).New(reqParams, []byte(reqBody))
-	if err.Error() != "charset {wrong-charset} does not exist" {
-		t.Fatalf(`result: {%v} but expected {%v}`, err.Error(), "charset does not exist")
+	mocker := NewMock(workingDirectory, nil, *logger)
+	id, err := mocker.New(reqParams, []byte("Hello World"))
+	if err != nil {
+		t.Fatalf(err.Error())
+	}
+	defer os.Remove(workingDirectory + "/" + *id + ".json")
+
+	if r, err := mocker.Get(*id); err != nil || r.Status != 200 {
+		t.Fatalf(`result: {%v} but expected {%v}`, r, 200)
 	}
 }
 
-// TestNewWithBadContentType calls Mocker.New,
+// TestNewWithStatusInBody calls Mocker.New,
 // checking for a valid return value.
-func TestNewWithBadContentType(t *testing.T) {
+func TestNewWithStatusInBody(t *testing.T) {
 	reqParams := map[string][]string{
-		"status":      {"200"},
-		"contentType": {},
+		"contentType": {"application/json"},
 		"charset":     {"UTF-8"},
 	}
-	reqBody := "Hello World"
 
-	_, err := NewMock(workingDirectory, nil, *logger).New(reqParams, []byte(reqBody))
-	if err.Error() != "content type {} does not exist" {
-		t.Fatalf(`result: {%v} but expected {%v}`, err.Error(), "content type does not exist")
+	mocker := NewMock(workingDirectory, nil, *logger)
+	id, err := mocker.New(reqParams, []byte(`{"status": 404, "message": "not found"}`))
+	if err != nil {
+		t.Fatalf(err.Error())
 	}
-}
+	defer os.Remove(workingDirectory + "/" + *id + ".json")
 
-func createMockedRequest() MockedRequest {
-	mockedRequest := MockedRequest{
-		MockedRequestLight: MockedRequestLight{
-			Id:        uuid.NewString(),
-			CreatedAt: time.Now().Format("2006-01-02 15:04:05"),
-			MockedRequestHeader: MockedRequestHeader{
-				Status:      200,
-				ContentType: "text/plain",
-				Charset:     "UTF-8",
-				Headers:     map[string]string{"x-language": "golang", "x-domain": "github.com"},
-			},
-		},
-		Body64: []byte("Hello World"),
+	if r, err := mocker.Get(*id); err != nil || r.Status != 404 {
+		t.Fatalf(`result: {%v} but expected {%v}`, r, 404)
 	}
 
-	bytes, err := jsonsutil.Marshal(mockedRequest)
+	// test if the query param takes precedence over the body
+	reqParams["status"] = []string{"201"}
+	id, err = mocker.New(reqParams, []byte(`{"status": 404}`))
 	if err != nil {
-		log.Fatal(err)
+		t.Fatalf(err.Error())
 	}
+	defer os.Remove(workingDirectory + "/" + *id + ".json")
 
-	err = iosutil.Write(bytes, workingDirectory+"/"+mockedRequest.Id+".json")
-	if err != nil {
-		log.Fatal(err)
+	if r, err := mocker.Get(*id); err != nil || r.Status != 201 {
+		t.Fatalf(`result: {%v} but expected {%v}`, r, 201)
 	}
 
-	return mockedRequest
+	// test if the status of the body does not exist
+	delete(reqParams, "status")
+	_, err = mocker.New(reqParams, []byte(`{"status": 999}`))
+	if err == nil || err.Error() != "status {999} does not exist (nearest supported: 508, 510, 511)" {
+		t.Fatalf(`result: {%v} but expected {%v}`, err, "status does not exist")
+	}
+}
+
+// TestNewWithDefaults calls Mocker.New,
+// checking for a valid return value.
+func TestNewWithDefaults(t *testing.T) {
+	mocker := NewMock(t.TempDir(), nil, *logger, WithDefaults("application/json", "UTF-8"))
+
+	id, err := mocker.New(map[string][]string{}, []byte(`{"id":1}`))
+	if err != nil {
+		t.Fatalf(`result: {%v} but expected {%v}`, err, nil)
+	}
+	if r, err := mocker.Get(*id); err != nil ||
+		r.Status != 200 || r.ContentType != "application/json" || r.Charset != "UTF-8" || string(r.Body64) != `{"id":1}` {
+		t.Fatalf(`result: {%v} but expected {%v}`, r, "application/json; charset=UTF-8")
+	}
+
+	// test if the defaults do not override the defined values
+	id, _ = mocker.New(map[string][]string{"contentType": {"image/png"}}, []byte{0x89, 0x50})
+	if r, err := mocker.Get(*id); err != nil || r.ContentType != "image/png" || r.Charset != "" {
+		t.Fatalf(`result: {%v} but expected {%v}`, r, "image/png")
+	}
+
+	// test if the unsupported defaults are ignored
+	if _, err := NewMock(t.TempDir(), nil, *logger, WithDefaults("wrong/type", "wrong-charset")).
+		New(map[string][]string{}, []byte("Hello World")); err == nil || err.Error() != "content type {} does not exist" {
+		t.Fatalf(`result: {%v} but expected {%v}`, err, "content type {} does not exist")
+	}
+}
+
+// TestNewWithRedirect calls Mocker.New,
+// checking for a valid return value.
+func TestNewWithRedirect(t *testing.T) {
+	mocker := NewInMemoryMock(*logger)
+	reqParams := map[string][]string{
+		"status":      {"301"},
+		"contentType": {"text/plain"},
+		"charset":     {"UTF-8"},
+	}
+
+	if _, err := mocker.New(reqParams, nil); err == nil || err.Error() != "location must be defined for the redirect status {301}" {
+		t.Fatalf(`result: {%v} but expected {%v}`, err, "location must be defined for the redirect status {301}")
+	}
+
+	reqParams["location"] = []string{"/api/users"}
+	id, err := mocker.New(reqParams, nil)
+	if err != nil {
+		t.Fatalf(`result: {%v} but expected {%v}`, err, nil)
+	}
+	if r, _ := mocker.Get(*id); r.Location != "/api/users" {
+		t.Fatalf(`result: {%v} but expected {%v}`, r.Location, "/api/users")
+	}
+}
+
+// TestNewWithTrailers calls Mocker.New,
+// checking for a valid return value.
+func TestNewWithTrailers(t *testing.T) {
+	mocker := NewInMemoryMock(*logger)
+	id, err := mocker.New(map[string][]string{
+		"contentType":          {"application/json"},
+		"charset":              {"UTF-8"},
+		"trailer.grpc-status":  {"0"},
+		"trailer.grpc-message": {"OK"},
+	}, []byte(`{"id":1}`))
+	if err != nil {
+		t.Fatalf(`result: {%v} but expected {%v}`, err, nil)
+	}
+
+	expected := map[string]string{"grpc-status": "0", "grpc-message": "OK"}
+	if r, _ := mocker.Get(*id); !reflect.DeepEqual(r.Trailers, expected) || len(r.Headers) != 0 {
+		t.Fatalf(`result: {%v} but expected {%v}`, r.Trailers, expected)
+	}
+}
+
+// TestNewWithValidatedBody calls Mocker.New,
+// checking for a valid return value.
+func TestNewWithValidatedBody(t *testing.T) {
+	mocker := NewInMemoryMock(*logger)
+
+	tests := []struct {
+		contentType  string
+		validateBody string
+		body         string
+		valid        bool
+	}{
+		{"application/json", "true", `{"id": 1, "tags": ["a"]}`, true},
+		{"application/json", "true", `{"id": 1,`, false},
+		{"application/json", "false", `{"id": 1,`, true},
+		{"text/json", "true", `not json`, false},
+		{"application/xml", "true", `<user><id>1</id></user>`, true},
+		{"application/xml", "true", `<user><id>1</user>`, false},
+		{"text/xml", "true", `plain text`, false},
+		{"text/plain", "true", `{"id": 1,`, true},
+	}
+
+	for _, test := range tests {
+		_, err := mocker.New(map[string][]string{
+			"status":       {"200"},
+			"contentType":  {test.contentType},
+			"charset":      {"UTF-8"},
+			"validateBody": {test.validateBody},
+		}, []byte(test.body))
+
+		var invalidBodyError InvalidBodyError
+		if (err == nil) != test.valid || (!test.valid && !errors.As(err, &invalidBodyError)) {
+			t.Fatalf(`%s %s - result: {%v} but expected {%v}`, test.contentType, test.body, err, test.valid)
+		}
+	}
+}
+
+// TestNewWithBadCharset calls Mocker.New,
+// checking for a valid return value.
+func TestNewWithBadCharset(t *testing.T) {
+	reqParams := map[string][]string{
+		"status":      {"200"},
+		"contentType": {"text/plain"},
+		"charset":     {"wrong-charset"},
+	}
+	reqBody := "Hello World"
+
+	_, err := NewMock(workingDirectory, nil, *logger).New(reqParams, []byte(reqBody))
+	if err.Error() != "charset {wrong-charset} does not exist" {
+		t.Fatalf(`result: {%v} but expected {%v}`, err.Error(), "charset does not exist")
+	}
+}
+
+// TestNewWithBadStatusText calls Mocker.New,
+// checking for a valid return value.
+func TestNewWithBadStatusText(t *testing.T) {
+	reqParams := map[string][]string{
+		"status":      {"200"},
+		"statusText":  {"Everything\r\nX-Injected: true"},
+		"contentType": {"text/plain"},
+		"charset":     {"UTF-8"},
+	}
+	reqBody := "Hello World"
+
+	_, err := NewMock(workingDirectory, nil, *logger).New(reqParams, []byte(reqBody))
+	if err == nil || !strings.HasPrefix(err.Error(), "status text {") {
+		t.Fatalf(`result: {%v} but expected {%v}`, err, "status text is malformed")
+	}
+}
+
+// TestNewWithMethod calls Mocker.New,
+// checking for a valid return value.
+func TestNewWithMethod(t *testing.T) {
+	mocker := NewInMemoryMock(*logger)
+	reqParams := map[string][]string{
+		"status":      {"200"},
+		"method":      {"post"},
+		"contentType": {"text/plain"},
+		"charset":     {"UTF-8"},
+	}
+
+	id, err := mocker.New(reqParams, []byte("Hello World"))
+	if err != nil {
+		t.Fatalf(err.Error())
+	}
+	if mock, _ := mocker.Get(*id); mock.Method != "POST" {
+		t.Fatalf(`result: {%v} but expected {%v}`, mock.Method, "POST")
+	}
+
+	reqParams["method"] = []string{"GE T"}
+	if _, err := mocker.New(reqParams, []byte("Hello World")); err == nil || err.Error() != "method {GE T} is malformed" {
+		t.Fatalf(`result: {%v} but expected {%v}`, err, "method is malformed")
+	}
+}
+
+// TestNewWithCaseInsensitiveContentType calls Mocker.New,
+// checking for a valid return value.
+func TestNewWithCaseInsensitiveContentType(t *testing.T) {
+	mocker := NewInMemoryMock(*logger)
+	tests := map[string][]string{
+		"Application/JSON":         {"utf-8", "application/json", "UTF-8"},
+		"TEXT/PLAIN":               {"iso-8859-1", "text/plain", "ISO-8859-1"},
+		"application/Json":         {"Utf-8", "application/json", "UTF-8"},
+		"Application/Octet-Stream": {"", "application/octet-stream", ""},
+	}
+
+	for contentType, test := range tests {
+		reqParams := map[string][]string{"status": {"200"}, "contentType": {contentType}}
+		if test[0] != "" {
+			reqParams["charset"] = []string{test[0]}
+		}
+
+		id, err := mocker.New(reqParams, []byte("{}"))
+		if err != nil {
+			t.Fatalf(`%s - result: {%v} but expected {%v}`, contentType, err, "no error")
+		}
+		if mock, _ := mocker.Get(*id); mock.ContentType != test[1] || mock.Charset != test[2] {
+			t.Fatalf(`%s - result: {%v} but expected {%v}`, contentType, mock.MockedRequestHeader, test[1:])
+		}
+	}
+}
+
+// TestNewWithIgnoredBody calls Mocker.New,
+// checking for a valid return value.
+func TestNewWithIgnoredBody(t *testing.T) {
+	reqParams := map[string][]string{
+		"status":      {"204"},
+		"contentType": {"text/plain"},
+		"charset":     {"UTF-8"},
+	}
+
+	mocker := NewMock(t.TempDir(), nil, *logger)
+	id, err := mocker.New(reqParams, []byte("Hello World"))
+	if err != nil {
+		t.Fatalf(err.Error())
+	}
+	if mock, _ := mocker.Get(*id); !mock.hasIgnoredBody() {
+		t.Fatalf(`result: {%v} but expected {%v}`, mock.hasIgnoredBody(), true)
+	}
+}
+
+// TestHitMethod calls Mocker.HitMethod,
+// checking for a valid return value.
+func TestHitMethod(t *testing.T) {
+	reqParams := map[string][]string{
+		"status":      {"200"},
+		"method":      {"POST"},
+		"contentType": {"text/plain"},
+		"charset":     {"UTF-8"},
+	}
+
+	for _, mocker := range []Mocker{NewMock(t.TempDir(), nil, *logger), NewInMemoryMock(*logger)} {
+		id, _ := mocker.New(reqParams, []byte("Hello World"))
+		if _, err := mocker.HitMethod(*id, http.MethodPost); err != nil {
+			t.Fatalf(`result: {%v} but expected {%v}`, err, nil)
+		}
+
+		// test if the other methods are rejected without any hit
+		var methodNotAllowedError MethodNotAllowedError
+		if _, err := mocker.HitMethod(*id, http.MethodGet); !errors.As(err, &methodNotAllowedError) ||
+			methodNotAllowedError.Allowed != http.MethodPost {
+			t.Fatalf(`result: {%v} but expected {%v}`, err, "method is not allowed")
+		}
+		if mock, _ := mocker.Get(*id); mock.Hits != 1 {
+			t.Fatalf(`result: {%v} but expected {%v}`, mock.Hits, 1)
+		}
+	}
+}
+
+// TestHitMethodHeadWithSequence calls Mocker.HitMethod,
+// checking for a valid return value.
+func TestHitMethodHeadWithSequence(t *testing.T) {
+	reqParams := map[string][]string{
+		"status":      {"200"},
+		"contentType": {"text/plain"},
+		"charset":     {"UTF-8"},
+		"sequence":    {"true"},
+	}
+	reqBody := []byte(`[{"status":503,"body":"unavailable"},{"status":201,"body":"created"}]`)
+
+	for _, mocker := range []Mocker{NewMock(t.TempDir(), nil, *logger), NewInMemoryMock(*logger)} {
+		id, err := mocker.New(reqParams, reqBody)
+		if err != nil {
+			t.Fatalf(err.Error())
+		}
+
+		// test if "HEAD" gets the element served by the next "GET" without advancing the sequence
+		steps := []struct{ method, expected string }{
+			{http.MethodHead, "503:unavailable"},
+			{http.MethodHead, "503:unavailable"},
+			{http.MethodGet, "503:unavailable"},
+			{http.MethodHead, "201:created"},
+		}
+		for _, step := range steps {
+			if r, err := mocker.HitMethod(*id, step.method); err != nil || fmt.Sprintf("%d:%s", r.Status, r.Body64) != step.expected {
+				t.Fatalf(`%s result: {%v} but expected {%v}`, step.method, r, step.expected)
+			}
+		}
+		if mock, _ := mocker.Get(*id); mock.Hits != 1 || mock.SequenceIndex != 1 {
+			t.Fatalf(`result: {%v} but expected {%v}`, mock.Hits, 1)
+		}
+	}
+}
+
+// TestNewWithBadContentType calls Mocker.New,
+// checking for a valid return value.
+func TestNewWithBadContentType(t *testing.T) {
+	reqParams := map[string][]string{
+		"status":      {"200"},
+		"contentType": {},
+		"charset":     {"UTF-8"},
+	}
+	reqBody := "Hello World"
+
+	_, err := NewMock(workingDirectory, nil, *logger).New(reqParams, []byte(reqBody))
+	if err.Error() != "content type {} does not exist" {
+		t.Fatalf(`result: {%v} but expected {%v}`, err.Error(), "content type does not exist")
+	}
+}
+
+// TestMatch calls Mocker.Match(*http.Request),
+// checking for a valid return value.
+func TestMatch(t *testing.T) {
+	dir := t.TempDir()
+
+	mocker := NewMock(dir, nil, *logger)
+	oldId, err := mocker.New(map[string][]string{
+		"status":      {"200"},
+		"contentType": {"text/plain"},
+		"charset":     {"UTF-8"},
+		"when.method": {"get"},
+		"when.path":   {"/api/users/*"},
+	}, []byte("old"))
+	if err != nil {
+		t.Fatalf(err.Error())
+	}
+
+	// update the creation date of the first mocked request to be sure that it's the oldest one
+	oldMock, _ := mocker.Get(*oldId)
+	oldMock.CreatedAt = "1970-01-01 00:00:01"
+	bytes, _ := jsonsutil.Marshal(oldMock)
+	iosutil.Write(bytes, dir+"/"+*oldId+".json")
+
+	newId, err := mocker.New(map[string][]string{
+		"status":            {"201"},
+		"contentType":       {"text/plain"},
+		"charset":           {"UTF-8"},
+		"when.path":         {"/api/users/*"},
+		"when.query.active": {"true"},
+	}, []byte("new"))
+	if err != nil {
+		t.Fatalf(err.Error())
+	}
+
+	r, err := mocker.Match(httptest.NewRequest("GET", "http://localhost:3333/api/users/1?active=true", nil))
+	if err != nil || r.Id != *newId {
+		t.Fatalf(`result: {%v} but expected {%v}`, r, *newId)
+	}
+
+	r, err = mocker.Match(httptest.NewRequest("GET", "http://localhost:3333/api/users/1", nil))
+	if err != nil || r.Id != *oldId {
+		t.Fatalf(`result: {%v} but expected {%v}`, r, *oldId)
+	}
+
+	// test if no mocked request matches
+	r, err = mocker.Match(httptest.NewRequest("POST", "http://localhost:3333/api/users/1", nil))
+	if err == nil || err.Error() != "no mocked request matches {POST /api/users/1}" {
+		t.Fatalf(`result: {%v} but expected error`, r)
+	}
+}
+
+// TestMatchWithSameCreationDate calls match([]MockedRequest, *http.Request),
+// checking for a valid return value.
+func TestMatchWithSameCreationDate(t *testing.T) {
+	newMock := func(id, createdAt string) MockedRequest {
+		return MockedRequest{
+			MockedRequestLight: MockedRequestLight{Id: id, CreatedAt: createdAt},
+			When:               &Matcher{Path: "/api/users/*"},
+		}
+	}
+	req := httptest.NewRequest("GET", "http://localhost:3333/api/users/1", nil)
+
+	// test if the nanoseconds order the requests created in the same second
+	mockedRequests := []MockedRequest{
+		newMock("b", "2024-08-01T10:00:00.000000002Z"), newMock("a", "2024-08-01T10:00:00.000000001Z")}
+	if r, _ := match(mockedRequests, req); r.Id != "b" {
+		t.Fatalf(`result: {%v} but expected {%v}`, r.Id, "b")
+	}
+
+	// test if the id breaks the tie whatever the order of the requests
+	mockedRequests = []MockedRequest{newMock("a", "2024-08-01T10:00:00Z"), newMock("b", "2024-08-01T10:00:00Z")}
+	for i := 0; i < 2; i++ {
+		if r, _ := match(mockedRequests, req); r.Id != "b" {
+			t.Fatalf(`result: {%v} but expected {%v}`, r.Id, "b")
+		}
+		slices.Reverse(mockedRequests)
+	}
+}
+
+// TestMatchWithPredefinedMockedRequests calls Mocker.Match(*http.Request),
+// checking for a valid return value.
+func TestMatchWithPredefinedMockedRequests(t *testing.T) {
+	mockedRequest := MockedRequest{
+		MockedRequestLight: MockedRequestLight{
+			Id: "my-own-mocked-request",
+			MockedRequestHeader: MockedRequestHeader{
+				Status:      200,
+				ContentType: "text/plain",
+				Charset:     "UTF-8",
+			},
+		},
+		When: &Matcher{Method: "GET", Path: "/hello"},
+		Body: "Hello World",
+	}
+
+	r, err := NewMock(t.TempDir(), []PredefinedMockedRequest{{MockedRequest: mockedRequest}}, *logger).
+		Match(httptest.NewRequest("GET", "http://localhost:3333/hello", nil))
+	if err != nil || r.Id != mockedRequest.Id || string(r.Body64) != "Hello World" {
+		t.Fatalf(`result: {%v} but expected {%v}`, r, mockedRequest)
+	}
+}
+
+// TestNewWithBadMatcherPath calls Mocker.New,
+// checking for a valid return value.
+func TestNewWithBadMatcherPath(t *testing.T) {
+	reqParams := map[string][]string{
+		"status":      {"200"},
+		"contentType": {"text/plain"},
+		"charset":     {"UTF-8"},
+		"when.path":   {"/api/["},
+	}
+
+	_, err := NewMock(workingDirectory, nil, *logger).New(reqParams, []byte("Hello World"))
+	if err == nil || err.Error() != "path pattern {/api/[} is malformed" {
+		t.Fatalf(`result: {%v} but expected {%v}`, err, "path pattern is malformed")
+	}
+}
+
+// TestNewWithBadMatcherPathRegex calls Mocker.New,
+// checking for a valid return value.
+func TestNewWithBadMatcherPathRegex(t *testing.T) {
+	reqParams := map[string][]string{
+		"status":         {"200"},
+		"contentType":    {"text/plain"},
+		"charset":        {"UTF-8"},
+		"when.pathRegex": {`/users/(\d+`},
+	}
+
+	_, err := NewMock(workingDirectory, nil, *logger).New(reqParams, []byte("Hello World"))
+	if err == nil || err.Error() != `path regex {/users/(\d+} is malformed` {
+		t.Fatalf(`result: {%v} but expected {%v}`, err, "path regex is malformed")
+	}
+}
+
+// TestMatchWithPathRegex calls Mocker.Match(*http.Request),
+// checking for a valid return value.
+func TestMatchWithPathRegex(t *testing.T) {
+	dir := t.TempDir()
+
+	mocker := NewMock(dir, nil, *logger)
+	exactId, err := mocker.New(map[string][]string{
+		"status":      {"200"},
+		"contentType": {"text/plain"},
+		"charset":     {"UTF-8"},
+		"when.path":   {"/users/me"},
+	}, []byte("exact"))
+	if err != nil {
+		t.Fatalf(err.Error())
+	}
+
+	// update the creation date of the exact mocked request to be sure that the regex one is the most recent
+	exactMock, _ := mocker.Get(*exactId)
+	exactMock.CreatedAt = "1970-01-01 00:00:01"
+	bytes, _ := jsonsutil.Marshal(exactMock)
+	iosutil.Write(bytes, dir+"/"+*exactId+".json")
+
+	regexId, err := mocker.New(map[string][]string{
+		"status":         {"200"},
+		"contentType":    {"text/plain"},
+		"charset":        {"UTF-8"},
+		"when.pathRegex": {`/users/(\d+|me)`},
+	}, []byte("regex"))
+	if err != nil {
+		t.Fatalf(err.Error())
+	}
+
+	r, err := mocker.Match(httptest.NewRequest("GET", "http://localhost:3333/users/42", nil))
+	if err != nil || r.Id != *regexId {
+		t.Fatalf(`result: {%v} but expected {%v}`, r, *regexId)
+	}
+
+	// test if the exact path takes precedence over the regex
+	r, err = mocker.Match(httptest.NewRequest("GET", "http://localhost:3333/users/me", nil))
+	if err != nil || r.Id != *exactId {
+		t.Fatalf(`result: {%v} but expected {%v}`, r, *exactId)
+	}
+
+	// test if no mocked request matches
+	r, err = mocker.Match(httptest.NewRequest("GET", "http://localhost:3333/users/abc", nil))
+	if err == nil {
+		t.Fatalf(`result: {%v} but expected error`, r)
+	}
+}
+
+// TestNewWithTemplatedBinaryContent calls Mocker.New,
+// checking for a valid return value.
+func TestNewWithTemplatedBinaryContent(t *testing.T) {
+	reqParams := map[string][]string{
+		"status":      {"200"},
+		"contentType": {"image/png"},
+		"charset":     {"UTF-8"},
+		"templated":   {"true"},
+	}
+
+	_, err := NewMock(workingDirectory, nil, *logger).New(reqParams, []byte("{{query.id}}"))
+	if err == nil || err.Error() != "content type {image/png} cannot be templated" {
+		t.Fatalf(`result: {%v} but expected {%v}`, err, "content type cannot be templated")
+	}
+}
+
+func createMockedRequest() MockedRequest {
+	mockedRequest := MockedRequest{
+		MockedRequestLight: MockedRequestLight{
+			Id:        uuid.NewString(),
+			CreatedAt: time.Now().Format("2006-01-02 15:04:05"),
+			MockedRequestHeader: MockedRequestHeader{
+				Status:      200,
+				ContentType: "text/plain",
+				Charset:     "UTF-8",
+				Headers:     map[string]string{"x-language": "golang", "x-domain": "github.com"},
+			},
+		},
+		Body64: []byte("Hello World"),
+	}
+
+	bytes, err := jsonsutil.Marshal(mockedRequest)
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	err = iosutil.Write(bytes, workingDirectory+"/"+mockedRequest.Id+".json")
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	return mockedRequest
+}
+
+// TestNewWithStoredKeys calls Mocker.New,
+// checking that the keys of the stored file match the parameters of the creation.
+func TestNewWithStoredKeys(t *testing.T) {
+	dir := t.TempDir()
+	mocker := NewMock(dir, nil, *logger)
+
+	params := map[string][]string{
+		"status":      {"201"},
+		"contentType": {"text/plain"},
+		"charset":     {"UTF-8"},
+		"statusText":  {"Created"},
+		"delay":       {"100ms"},
+		"chunkSize":   {"4"},
+		"createdBy":   {"joakim"},
+		"tags":        {"team-a"},
+		"X-Language":  {"golang"},
+	}
+	id, err := mocker.New(params, []byte("Hello World"))
+	if err != nil {
+		t.Fatalf(err.Error())
+	}
+
+	data, _ := iosutil.Load(dir + "/" + *id + ".json")
+	stored, err := jsonsutil.Unmarshal[map[string]any](data)
+	if err != nil {
+		t.Fatalf(err.Error())
+	}
+
+	for key := range stored {
+		if key != strings.ToLower(key[:1])+key[1:] {
+			t.Fatalf(`result: {%v} but expected {%v}`, key, "a camelCase key")
+		}
+	}
+	for param := range params {
+		if _, is := stored[param]; !is && param != "X-Language" {
+			t.Fatalf(`result: {%v} but expected {%v}`, stored, param)
+		}
+	}
+	if stored["id"] != *id || stored["createdAt"] == nil || stored["body64"] == nil ||
+		!reflect.DeepEqual(stored["headers"], map[string]any{"X-Language": "golang"}) {
+		t.Fatalf(`result: {%v} but expected {%v}`, stored, "id, createdAt, body64 and headers")
+	}
+
+	// test if Get and List read the same values
+	mock, _ := mocker.Get(*id)
+	list, _ := mocker.List()
+	if len(list) != 1 || !reflect.DeepEqual(list[0], mock.MockedRequestLight) {
+		t.Fatalf(`result: {%v} but expected {%v}`, list, mock.MockedRequestLight)
+	}
+}
+
+// TestNewWithUUID calls Mocker.New,
+// checking for a valid return value.
+func TestNewWithUUID(t *testing.T) {
+	predefinedId := uuid.NewString()
+	mockers := []Mocker{
+		NewMock(t.TempDir(), []PredefinedMockedRequest{
+			{MockedRequest: MockedRequest{MockedRequestLight: MockedRequestLight{Id: predefinedId}}},
+		}, *logger),
+		NewInMemoryMock(*logger),
+	}
+	for _, mocker := range mockers {
+		id := uuid.NewString()
+		params := func(id string) map[string][]string {
+			return map[string][]string{"contentType": {"text/plain"}, "charset": {"UTF-8"}, "uuid": {id}}
+		}
+
+		if r, err := mocker.New(params(id), nil); err != nil || *r != id {
+			t.Fatalf(`result: {%v} but expected {%v}`, err, id)
+		}
+		if r, err := mocker.Get(id); err != nil || r.Id != id {
+			t.Fatalf(`result: {%v} but expected {%v}`, r, id)
+		}
+
+		var duplicateIdError DuplicateIdError
+		if _, err := mocker.New(params(id), nil); !errors.As(err, &duplicateIdError) ||
+			err.Error() != "mocked request {"+id+"} already exists" {
+			t.Fatalf(`result: {%v} but expected {%v}`, err, "already exists")
+		}
+
+		var malformedIdError MalformedIdError
+		if _, err := mocker.New(params("../wrong"), nil); !errors.As(err, &malformedIdError) {
+			t.Fatalf(`result: {%v} but expected {%v}`, err, "malformed")
+		}
+	}
+
+	// test if a predefined id cannot be reused
+	var duplicateIdError DuplicateIdError
+	if _, err := mockers[0].New(map[string][]string{"contentType": {"text/plain"}, "charset": {"UTF-8"}, "uuid": {predefinedId}}, nil); !errors.As(err, &duplicateIdError) {
+		t.Fatalf(`result: {%v} but expected {%v}`, err, "already exists")
+	}
+}
+
+// TestNewWithChunks calls Mocker.New,
+// checking for a valid return value.
+func TestNewWithChunks(t *testing.T) {
+	mocker := NewInMemoryMock(*logger)
+	params := func(chunkSize, chunkDelay string) map[string][]string {
+		return map[string][]string{
+			"contentType": {"text/plain"},
+			"charset":     {"UTF-8"},
+			"chunkSize":   {chunkSize},
+			"chunkDelay":  {chunkDelay},
+		}
+	}
+
+	id, err := mocker.New(params("4", "100ms"), []byte("Hello World"))
+	if r, _ := mocker.Get(*id); err != nil || r.ChunkSize != 4 || r.ChunkDelay != "100ms" {
+		t.Fatalf(`result: {%v} but expected {%v}`, r, "chunk size 4 and chunk delay 100ms")
+	}
+
+	tests := map[string][]string{
+		"chunk size {-1} must be positive": {"wrong", "100ms"},
+		"chunk delay {wrong} is malformed": {"4", "wrong"},
+	}
+	for expected, values := range tests {
+		if _, err := mocker.New(params(values[0], values[1]), nil); err == nil || err.Error() != expected {
+			t.Fatalf(`result: {%v} but expected {%v}`, err, expected)
+		}
+	}
+}
+
+// TestNewWithDelay calls Mocker.New,
+// checking for a valid return value.
+func TestNewWithDelay(t *testing.T) {
+	mocker := NewInMemoryMock(*logger)
+	params := func(delay string) map[string][]string {
+		return map[string][]string{"contentType": {"text/plain"}, "charset": {"UTF-8"}, "delay": {delay}}
+	}
+
+	for _, delay := range []string{"100ms", "100ms-1s"} {
+		id, err := mocker.New(params(delay), nil)
+		if r, _ := mocker.Get(*id); err != nil || r.Delay != delay || len(r.Headers) != 0 {
+			t.Fatalf(`result: {%v} but expected {%v}`, r, delay)
+		}
+	}
+
+	for _, delay := range []string{"wrong", "1s-100ms", "100ms-wrong"} {
+		expected := fmt.Sprintf("delay {%s} is malformed", delay)
+		if _, err := mocker.New(params(delay), nil); err == nil || err.Error() != expected {
+			t.Fatalf(`result: {%v} but expected {%v}`, err, expected)
+		}
+	}
+}
+
+// TestNewWithDelayPerKB calls Mocker.New,
+// checking for a valid return value.
+func TestNewWithDelayPerKB(t *testing.T) {
+	mocker := NewInMemoryMock(*logger)
+	params := func(delayPerKB string) map[string][]string {
+		return map[string][]string{"contentType": {"text/plain"}, "charset": {"UTF-8"}, "delayPerKB": {delayPerKB}}
+	}
+
+	id, err := mocker.New(params("10ms"), nil)
+	if r, _ := mocker.Get(*id); err != nil || r.DelayPerKB != "10ms" {
+		t.Fatalf(`result: {%v} but expected {%v}`, r, "10ms")
+	}
+
+	for _, delayPerKB := range []string{"wrong", "-10ms"} {
+		expected := fmt.Sprintf("delay per KB {%s} is malformed", delayPerKB)
+		if _, err := mocker.New(params(delayPerKB), nil); err == nil || err.Error() != expected {
+			t.Fatalf(`result: {%v} but expected {%v}`, err, expected)
+		}
+	}
+}
+
+// TestNewWithBodyURL calls Mocker.New,
+// checking for a valid return value.
+func TestNewWithBodyURL(t *testing.T) {
+	mocker := NewInMemoryMock(*logger)
+	params := func(bodyURL string) map[string][]string {
+		return map[string][]string{"contentType": {"application/json"}, "charset": {"UTF-8"}, "bodyURL": {bodyURL}}
+	}
+
+	id, err := mocker.New(params("https://api.example.com/users"), nil)
+	if r, _ := mocker.Get(*id); err != nil || r.BodyURL != "https://api.example.com/users" {
+		t.Fatalf(`result: {%v} but expected {%v}`, r, "https://api.example.com/users")
+	}
+
+	for _, bodyURL := range []string{"wrong", "ftp://api.example.com", "/users"} {
+		expected := fmt.Sprintf("body url {%s} must be an absolute http(s) URL", bodyURL)
+		if _, err := mocker.New(params(bodyURL), nil); err == nil || err.Error() != expected {
+			t.Fatalf(`result: {%v} but expected {%v}`, err, expected)
+		}
+	}
+}
+
+// TestNewWithBandwidth calls Mocker.New,
+// checking for a valid return value.
+func TestNewWithBandwidth(t *testing.T) {
+	mocker := NewInMemoryMock(*logger)
+	params := func(bandwidth string) map[string][]string {
+		return map[string][]string{"contentType": {"text/plain"}, "charset": {"UTF-8"}, "bandwidthKbps": {bandwidth}}
+	}
+
+	id, err := mocker.New(params("64"), []byte("Hello World"))
+	if r, _ := mocker.Get(*id); err != nil || r.BandwidthKbps != 64 {
+		t.Fatalf(`result: {%v} but expected {%v}`, r, "bandwidth 64")
+	}
+
+	for _, value := range []string{"-1", "wrong"} {
+		if _, err := mocker.New(params(value), nil); err == nil || err.Error() != "bandwidth {-1} must be positive" {
+			t.Fatalf(`result: {%v} but expected {%v}`, err, "bandwidth {-1} must be positive")
+		}
+	}
+}
+
+// TestNewWithForceContentLength calls Mocker.New,
+// checking for a valid return value.
+func TestNewWithForceContentLength(t *testing.T) {
+	mocker := NewInMemoryMock(*logger)
+	params := func(length string) map[string][]string {
+		return map[string][]string{"contentType": {"text/plain"}, "charset": {"UTF-8"}, "forceContentLength": {length}}
+	}
+
+	id, err := mocker.New(params("100"), []byte("Hello World"))
+	if r, _ := mocker.Get(*id); err != nil || r.ForceContentLength == nil || *r.ForceContentLength != 100 {
+		t.Fatalf(`result: {%v} but expected {%v}`, r, "forced content length 100")
+	}
+
+	for _, value := range []string{"-1", "wrong"} {
+		if _, err := mocker.New(params(value), nil); err == nil || err.Error() != "forced content length {-1} must be positive" {
+			t.Fatalf(`result: {%v} but expected {%v}`, err, "forced content length {-1} must be positive")
+		}
+	}
+}
+
+// TestNewWithMultiHeaders calls Mocker.New,
+// checking for a valid return value.
+func TestNewWithMultiHeaders(t *testing.T) {
+	mocker := NewInMemoryMock(*logger)
+	id, err := mocker.New(map[string][]string{
+		"contentType": {"text/plain"},
+		"charset":     {"UTF-8"},
+		"X-Language":  {"golang"},
+		"Set-Cookie":  {"session=abc", "theme=dark"},
+	}, nil)
+	if err != nil {
+		t.Fatalf(err.Error())
+	}
+
+	mock, _ := mocker.Get(*id)
+	if mock.Headers["X-Language"] != "golang" || !reflect.DeepEqual(mock.MultiHeaders["Set-Cookie"], []string{"session=abc", "theme=dark"}) {
+		t.Fatalf(`result: {%v, %v} but expected {%v}`, mock.Headers, mock.MultiHeaders, "two Set-Cookie values")
+	}
+}
+
+// TestWriteAtomic calls writeAtomic([]byte, string),
+// checking for a valid return value.
+func TestWriteAtomic(t *testing.T) {
+	workingDirectory := t.TempDir()
+	path := workingDirectory + "/" + uuid.NewString() + ".json"
+
+	if err := writeAtomic([]byte(`{"id": "1"}`), path); err != nil {
+		t.Fatalf(err.Error())
+	}
+	if err := writeAtomic([]byte(`{"id": "2"}`), path); err != nil {
+		t.Fatalf(err.Error())
+	}
+
+	if data, err := os.ReadFile(path); err != nil || string(data) != `{"id": "2"}` {
+		t.Fatalf(`result: {%v} but expected {%v}`, string(data), `{"id": "2"}`)
+	}
+
+	// test if the temporary files are removed
+	if entries, _ := os.ReadDir(workingDirectory); len(entries) != 1 {
+		t.Fatalf(`result: {%v} but expected {%v}`, len(entries), 1)
+	}
+}
+
+// TestListWithCorruptFile calls Mock.List,
+// checking for a valid return value.
+func TestListWithCorruptFile(t *testing.T) {
+	workingDirectory := t.TempDir()
+	mocker := NewMock(workingDirectory, nil, *logger)
+
+	id, err := mocker.New(map[string][]string{"contentType": {"text/plain"}, "charset": {"UTF-8"}}, []byte("Hello World"))
+	if err != nil {
+		t.Fatalf(err.Error())
+	}
+	// a partially written file
+	if err := os.WriteFile(workingDirectory+"/"+uuid.NewString()+".json", []byte(`{"id": "`), 0644); err != nil {
+		t.Fatalf(err.Error())
+	}
+
+	mockedRequests, err := mocker.List()
+	if err != nil || len(mockedRequests) != 1 || mockedRequests[0].Id != *id {
+		t.Fatalf(`result: {%v} but expected {%v}`, mockedRequests, *id)
+	}
+}
+
+// TestListWithNonJSONFiles calls Mock.List,
+// checking for a valid return value.
+func TestListWithNonJSONFiles(t *testing.T) {
+	workingDirectory := t.TempDir()
+	mocker := NewMock(workingDirectory, nil, *logger)
+
+	id, err := mocker.New(map[string][]string{"contentType": {"text/plain"}, "charset": {"UTF-8"}}, []byte("Hello World"))
+	if err != nil {
+		t.Fatalf(err.Error())
+	}
+	os.WriteFile(workingDirectory+"/.DS_Store", []byte("binary"), 0644)
+	os.WriteFile(workingDirectory+"/notes.txt", []byte("Hello World"), 0644)
+	os.Mkdir(workingDirectory+"/"+uuid.NewString()+".json", os.ModePerm)
+
+	mockedRequests, err := mocker.List()
+	if err != nil || len(mockedRequests) != 1 || mockedRequests[0].Id != *id {
+		t.Fatalf(`result: {%v} but expected {%v}`, mockedRequests, *id)
+	}
+
+	if nb, err := mocker.Count(); err != nil || nb != 1 {
+		t.Fatalf(`result: {%v} but expected {%v}`, nb, 1)
+	}
 }
@@ -0,0 +1,86 @@
+package internal
+
+import (
+	"fmt"
+	"os"
+	"regexp"
+	"sync"
+)
+
+// namespacePattern restricts the namespaces to simple directory names (no path traversal)
+var namespacePattern = regexp.MustCompile(`^[a-zA-Z0-9_-]{1,64}$`)
+
+// validateNamespace returns an error if the {namespace} is not a simple directory name.
+func validateNamespace(namespace string) error {
+	if !namespacePattern.MatchString(namespace) {
+		return fmt.Errorf("namespace {%s} is malformed", namespace)
+	}
+	return nil
+}
+
+// ForNamespace returns a {Mocker} whose mocked requests are isolated in the {namespace} sub-directory (created on demand),
+// the predefined requests are not shared with the namespaces.
+func (m Mock) ForNamespace(namespace string) (Mocker, error) {
+	if err := validateNamespace(namespace); err != nil {
+		return nil, err
+	}
+
+	scoped := m.namespace(namespace)
+	if err := os.MkdirAll(scoped.workingDirectory, os.ModePerm); err != nil {
+		m.logger.Error(err, "error to create namespace", "namespace", namespace, "workingDirectory", m.workingDirectory)
+		return nil, err
+	}
+	return scoped, nil
+}
+
+// namespace returns the {Mock} of the {namespace} sub-directory (without creating it).
+func (m Mock) namespace(namespace string) Mock {
+	workingDirectory := m.workingDirectory + "/" + namespace
+	return Mock{
+		workingDirectory: workingDirectory,
+		logger:           m.logger,
+		defaults:         m.defaults,
+		cache:            cacheFor(workingDirectory, m.defaults.cacheSize),
+	}
+}
+
+// namespaces returns the {Mock} of each namespace stored in a sub-directory of the working directory.
+func (m Mock) namespaces() []Mock {
+	fileEntries, err := os.ReadDir(m.workingDirectory + "/")
+	if err != nil {
+		m.logger.Error(err, "error to read directory", "workingDirectory", m.workingDirectory)
+		return nil
+	}
+
+	namespaces := []Mock{}
+	for _, e := range fileEntries {
+		if e.IsDir() && validateNamespace(e.Name()) == nil {
+			namespaces = append(namespaces, m.namespace(e.Name()))
+		}
+	}
+	return namespaces
+}
+
+// ForNamespace returns a {Mocker} whose mocked requests are isolated in the {namespace} (created on demand).
+func (m *InMemoryMock) ForNamespace(namespace string) (Mocker, error) {
+	if err := validateNamespace(namespace); err != nil {
+		return nil, err
+	}
+
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	if scoped, is := m.namespaces[namespace]; is {
+		return scoped, nil
+	}
+
+	scoped := &InMemoryMock{
+		mu:             &sync.RWMutex{},
+		mockedRequests: map[string]MockedRequest{},
+		namespaces:     map[string]*InMemoryMock{},
+		logger:         m.logger,
+		defaults:       m.defaults,
+	}
+	m.namespaces[namespace] = scoped
+	return scoped, nil
+}
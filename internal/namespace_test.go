@@ -0,0 +1,93 @@
+package internal
+
+import (
+	"os"
+	"strings"
+	"testing"
+	"time"
+)
+
+// TestForNamespace calls Mocker.ForNamespace(string),
+// checking for a valid return value.
+func TestForNamespace(t *testing.T) {
+	dir := t.TempDir()
+
+	for _, mocker := range []Mocker{NewMock(dir, nil, *logger), NewInMemoryMock(*logger)} {
+		teamA, err := mocker.ForNamespace("team-a")
+		if err != nil {
+			t.Fatalf(`result: {%v} but expected {%v}`, err, nil)
+		}
+		teamB, _ := mocker.ForNamespace("team-b")
+
+		id, _ := teamA.New(reqParams, []byte("Hello World"))
+		mocker.New(reqParams, []byte("Hello World"))
+
+		if r, _ := teamA.List(); len(r) != 1 || r[0].Id != *id {
+			t.Fatalf(`result: {%v} but expected {%v}`, r, *id)
+		}
+		if r, _ := teamB.List(); len(r) != 0 {
+			t.Fatalf(`result: {%v} but expected {%v}`, r, 0)
+		}
+		if r, _ := mocker.List(); len(r) != 1 || r[0].Id == *id {
+			t.Fatalf(`result: {%v} but expected {%v}`, r, 1)
+		}
+		if _, err := teamB.Get(*id); err == nil {
+			t.Fatalf(`result: {%v} but expected {%v}`, err, "does not exist")
+		}
+
+		// test if the same namespace is returned
+		if again, _ := mocker.ForNamespace("team-a"); again != nil {
+			if _, err := again.Get(*id); err != nil {
+				t.Fatalf(`result: {%v} but expected {%v}`, err, nil)
+			}
+		}
+	}
+
+	if _, err := os.Stat(dir + "/team-a"); err != nil {
+		t.Fatalf(`result: {%v} but expected {%v}`, err, "team-a directory")
+	}
+}
+
+// TestForNamespaceWithBadNamespace calls Mocker.ForNamespace(string),
+// checking for a valid return value.
+func TestForNamespaceWithBadNamespace(t *testing.T) {
+	dir := t.TempDir()
+
+	for _, namespace := range []string{"", "..", "../team", "team/a", "team a"} {
+		if _, err := NewMock(dir, nil, *logger).ForNamespace(namespace); err == nil ||
+			err.Error() != "namespace {"+namespace+"} is malformed" {
+			t.Fatalf(`result: {%v} but expected {%v}`, err, "namespace is malformed")
+		}
+	}
+
+	if entries, _ := os.ReadDir(dir); len(entries) != 0 {
+		t.Fatalf(`result: {%v} but expected {%v}`, entries, "no directory")
+	}
+}
+
+// TestCleanWithNamespaces calls Mocker.CleanExpired and Mocker.CleanBySize,
+// checking for a valid return value.
+func TestCleanWithNamespaces(t *testing.T) {
+	for _, mocker := range []Mocker{NewMock(t.TempDir(), nil, *logger), NewInMemoryMock(*logger)} {
+		teamA, _ := mocker.ForNamespace("team-a")
+		teamA.New(map[string][]string{"contentType": {"text/plain"}, "charset": {"UTF-8"}, "ttl": {"50ms"}}, nil)
+
+		time.Sleep(100 * time.Millisecond)
+
+		if nb, err := mocker.CleanExpired(); err != nil || nb != 1 {
+			t.Fatalf(`result: {%v} but expected {%v}`, nb, 1)
+		}
+		if nb, _ := teamA.Count(); nb != 0 {
+			t.Fatalf(`result: {%v} but expected {%v}`, nb, 0)
+		}
+
+		// test if the storage quota applies to the namespaces
+		teamA.New(reqParams, []byte(strings.Repeat("Hello World", 100)))
+		if nb, err := mocker.CleanBySize(1024); err != nil || nb != 1 {
+			t.Fatalf(`result: {%v} but expected {%v}`, nb, 1)
+		}
+		if nb, _ := teamA.Count(); nb != 0 {
+			t.Fatalf(`result: {%v} but expected {%v}`, nb, 0)
+		}
+	}
+}
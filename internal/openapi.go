@@ -0,0 +1,185 @@
+package internal
+
+import (
+	"encoding/json"
+	"errors"
+	"regexp"
+	"slices"
+	"strconv"
+	"strings"
+
+	"github.com/joakim-ribier/go-utils/pkg/jsonsutil"
+	"github.com/joakim-ribier/go-utils/pkg/slicesutil"
+	"github.com/joakim-ribier/mockapic/pkg"
+)
+
+// ImportedOperation represents the result of the import of an operation of an OpenAPI spec
+type ImportedOperation struct {
+	Method string `json:"method"`
+	Path   string `json:"path"`
+	Status int    `json:"status,omitempty"`
+	Id     string `json:"id,omitempty"`
+	Error  string `json:"error,omitempty"`
+}
+
+type openAPISpec struct {
+	Paths map[string]map[string]json.RawMessage `json:"paths"`
+}
+
+type openAPIOperation struct {
+	Responses map[string]struct {
+		Content map[string]openAPIMediaType `json:"content"`
+	} `json:"responses"`
+}
+
+type openAPIMediaType struct {
+	Example  json.RawMessage `json:"example"`
+	Examples map[string]struct {
+		Value json.RawMessage `json:"value"`
+	} `json:"examples"`
+	Schema struct {
+		Default json.RawMessage `json:"default"`
+	} `json:"schema"`
+}
+
+// openAPIMethods are the operations of a path item which can be imported
+var openAPIMethods = []string{"get", "put", "post", "delete", "options", "head", "patch", "trace"}
+
+// openAPIPathParameter matches the path parameters like {id}
+var openAPIPathParameter = regexp.MustCompile(`\{[^/{}]+\}`)
+
+// ImportOpenAPI creates a mocked request for each operation of the OpenAPI 3 (JSON) {spec} which defines an example response,
+// the operations without any example are skipped and the failure of an operation does not abort the import.
+func ImportOpenAPI(mocker Mocker, spec []byte) ([]ImportedOperation, error) {
+	openAPI, err := jsonsutil.Unmarshal[openAPISpec](spec)
+	if err != nil || openAPI.Paths == nil {
+		return nil, errors.New("spec must be a valid OpenAPI 3 JSON document")
+	}
+
+	paths := make([]string, 0, len(openAPI.Paths))
+	for path := range openAPI.Paths {
+		paths = append(paths, path)
+	}
+	slices.Sort(paths)
+
+	operations := []ImportedOperation{}
+	for _, path := range paths {
+		for _, method := range openAPIMethods {
+			data, is := openAPI.Paths[path][method]
+			if !is {
+				continue
+			}
+
+			operation := ImportedOperation{Method: strings.ToUpper(method), Path: path}
+
+			status, mediaType, body, found, err := findOpenAPIExample(data)
+			if err != nil {
+				operation.Error = err.Error()
+				operations = append(operations, operation)
+				continue
+			}
+			if !found {
+				continue
+			}
+			operation.Status = status
+
+			id, err := mocker.New(map[string][]string{
+				"status":      {strconv.Itoa(status)},
+				"contentType": {toContentType(mediaType)},
+				"charset":     {"UTF-8"},
+				"when.method": {operation.Method},
+				"when.path":   {openAPIPathParameter.ReplaceAllString(path, "*")},
+			}, body)
+			if err != nil {
+				operation.Error = err.Error()
+			} else {
+				operation.Id = *id
+			}
+			operations = append(operations, operation)
+		}
+	}
+
+	return operations, nil
+}
+
+// findOpenAPIExample finds the first response (sorted by status) of the operation which defines an example (or a schema default)
+func findOpenAPIExample(data []byte) (int, string, []byte, bool, error) {
+	operation, err := jsonsutil.Unmarshal[openAPIOperation](data)
+	if err != nil {
+		return 0, "", nil, false, errors.New("operation is malformed")
+	}
+
+	statuses := []int{}
+	for code := range operation.Responses {
+		if status, err := strconv.Atoi(code); err == nil {
+			statuses = append(statuses, status)
+		}
+	}
+	slices.Sort(statuses)
+
+	for _, status := range statuses {
+		content := operation.Responses[strconv.Itoa(status)].Content
+
+		mediaTypes := make([]string, 0, len(content))
+		for mediaType := range content {
+			mediaTypes = append(mediaTypes, mediaType)
+		}
+		slices.Sort(mediaTypes)
+
+		for _, mediaType := range mediaTypes {
+			if example := content[mediaType].example(); example != nil {
+				return status, mediaType, toBody(example), true, nil
+			}
+		}
+	}
+	return 0, "", nil, false, nil
+}
+
+// example returns the example, the first named example or the schema default of the media type
+func (m openAPIMediaType) example() json.RawMessage {
+	if len(m.Example) > 0 {
+		return m.Example
+	}
+
+	names := make([]string, 0, len(m.Examples))
+	for name := range m.Examples {
+		names = append(names, name)
+	}
+	slices.Sort(names)
+	for _, name := range names {
+		if len(m.Examples[name].Value) > 0 {
+			return m.Examples[name].Value
+		}
+	}
+
+	if len(m.Schema.Default) > 0 {
+		return m.Schema.Default
+	}
+	return nil
+}
+
+// toBody returns the raw value of a JSON string example, otherwise the JSON example as is
+func toBody(example json.RawMessage) []byte {
+	var value string
+	if err := json.Unmarshal(example, &value); err == nil {
+		return []byte(value)
+	}
+	return example
+}
+
+// toContentType maps the {mediaType} to a supported content type
+func toContentType(mediaType string) string {
+	mediaType, _, _ = strings.Cut(mediaType, ";")
+	mediaType = strings.ToLower(strings.TrimSpace(mediaType))
+
+	switch {
+	case slicesutil.Exist(pkg.CONTENT_TYPES, mediaType):
+		return mediaType
+	case strings.Contains(mediaType, "json"):
+		return "application/json"
+	case strings.Contains(mediaType, "xml"):
+		return "application/xml"
+	default:
+		return "text/plain"
+	}
+}
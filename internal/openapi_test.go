@@ -0,0 +1,103 @@
+package internal
+
+import (
+	"net/http/httptest"
+	"reflect"
+	"testing"
+)
+
+var openAPISpecTest = []byte(`{
+  "openapi": "3.0.0",
+  "paths": {
+    "/users/{id}": {
+      "parameters": [{"name": "id", "in": "path"}],
+      "get": {
+        "responses": {
+          "404": {"content": {"application/json": {"example": {"message": "not found"}}}},
+          "200": {"content": {"application/json": {"example": {"name": "mockapic"}}}}
+        }
+      },
+      "delete": {
+        "responses": {"204": {"description": "no example"}}
+      }
+    },
+    "/users": {
+      "post": {
+        "responses": {
+          "999": {"content": {"application/json": {"example": {}}}}
+        }
+      },
+      "put": {
+        "responses": {
+          "200": {"content": {"application/vnd.api+json": {"schema": {"default": {"id": 1}}}}}
+        }
+      }
+    },
+    "/ping": {
+      "get": {
+        "responses": {
+          "200": {"content": {"text/plain": {"examples": {"pong": {"value": "pong"}}}}}
+        }
+      }
+    }
+  }
+}`)
+
+// TestImportOpenAPI calls ImportOpenAPI(Mocker, []byte),
+// checking for a valid return value.
+func TestImportOpenAPI(t *testing.T) {
+	mocker := NewInMemoryMock(*logger)
+
+	r, err := ImportOpenAPI(mocker, openAPISpecTest)
+	if err != nil {
+		t.Fatalf(err.Error())
+	}
+
+	ids := []string{}
+	for i, operation := range r {
+		ids = append(ids, operation.Id)
+		r[i].Id = ""
+	}
+
+	expected := []ImportedOperation{
+		{Method: "GET", Path: "/ping", Status: 200},
+		{Method: "PUT", Path: "/users", Status: 200},
+		{Method: "POST", Path: "/users", Status: 999, Error: "status {999} does not exist (nearest supported: 508, 510, 511)"},
+		{Method: "GET", Path: "/users/{id}", Status: 200},
+	}
+	if !reflect.DeepEqual(r, expected) || ids[0] == "" || ids[1] == "" || ids[2] != "" || ids[3] == "" {
+		t.Fatalf(`result: {%v} but expected {%v}`, r, expected)
+	}
+
+	if r, _ := mocker.Count(); r != 3 {
+		t.Fatalf(`result: {%v} but expected {%v}`, r, 3)
+	}
+
+	tests := []struct {
+		method      string
+		url         string
+		contentType string
+		body        string
+	}{
+		{"GET", "/ping", "text/plain", "pong"},
+		{"PUT", "/users", "application/json", `{"id": 1}`},
+		{"GET", "/users/1", "application/json", `{"name": "mockapic"}`},
+	}
+	for _, test := range tests {
+		mock, err := mocker.Match(httptest.NewRequest(test.method, "http://localhost:3333"+test.url, nil))
+		if err != nil || mock.ContentType != test.contentType || string(mock.Body64) != test.body {
+			t.Fatalf(`%s %s - result: {%v} but expected {%v}`, test.method, test.url, mock, test.body)
+		}
+	}
+}
+
+// TestImportOpenAPIWithBadSpec calls ImportOpenAPI(Mocker, []byte),
+// checking for a valid return value.
+func TestImportOpenAPIWithBadSpec(t *testing.T) {
+	for _, spec := range []string{`not a json`, `{"openapi": "3.0.0"}`} {
+		r, err := ImportOpenAPI(NewInMemoryMock(*logger), []byte(spec))
+		if err == nil || err.Error() != "spec must be a valid OpenAPI 3 JSON document" {
+			t.Fatalf(`result: {%v} but expected error`, r)
+		}
+	}
+}
@@ -0,0 +1,15 @@
+package internal
+
+// ApplyPool returns the mocked request to serve: the element {pick(n)} (in [0, n)) of the pool replaces the response
+// if the pool is defined, unlike the sequence the elements are not served in order.
+func (m MockedRequest) ApplyPool(pick func(n int) int) MockedRequest {
+	if len(m.Pool) == 0 {
+		return m
+	}
+
+	element := m.Pool[pick(len(m.Pool))]
+	m.MockedRequestHeader = element.MockedRequestHeader
+	m.Body = ""
+	m.Body64 = element.Body64
+	return m
+}
@@ -0,0 +1,66 @@
+package internal
+
+import (
+	"math/rand/v2"
+	"testing"
+)
+
+// TestApplyPool calls MockedRequest.ApplyPool(func(int) int),
+// checking for a valid return value.
+func TestApplyPool(t *testing.T) {
+	mocker := NewInMemoryMock(*logger)
+	id, err := mocker.New(map[string][]string{
+		"contentType": {"text/plain"},
+		"charset":     {"UTF-8"},
+		"pool":        {"true"},
+	}, []byte(`[{"status": 200, "body": "Hello"}, {"status": 503}]`))
+	if err != nil {
+		t.Fatalf(err.Error())
+	}
+
+	mock, _ := mocker.Get(*id)
+	if len(mock.Pool) != 2 || mock.Body64 != nil || mock.Pool[1].ContentType != "text/plain" {
+		t.Fatalf(`result: {%v} but expected {%v}`, mock, "a pool of 2 responses")
+	}
+
+	served := map[int]int{}
+	for range 100 {
+		r := mock.ApplyPool(rand.IntN)
+		if (r.Status == 200 && string(r.Body64) != "Hello") || (r.Status == 503 && r.Body64 != nil) {
+			t.Fatalf(`result: {%v} but expected {%v}`, r, "an element of the pool")
+		}
+		served[r.Status]++
+	}
+	if served[200] == 0 || served[503] == 0 || served[200]+served[503] != 100 {
+		t.Fatalf(`result: {%v} but expected {%v}`, served, "both elements served")
+	}
+
+	// test if a mocked request without pool is served as is
+	if r := (MockedRequest{Body64: []byte("Hello World")}).ApplyPool(rand.IntN); string(r.Body64) != "Hello World" {
+		t.Fatalf(`result: {%v} but expected {%v}`, string(r.Body64), "Hello World")
+	}
+}
+
+// TestNewWithBadPool calls Mocker.New,
+// checking for a valid return value.
+func TestNewWithBadPool(t *testing.T) {
+	mocker := NewInMemoryMock(*logger)
+	params := func(sequence string) map[string][]string {
+		return map[string][]string{"contentType": {"text/plain"}, "charset": {"UTF-8"}, "pool": {"true"}, "sequence": {sequence}}
+	}
+
+	tests := []struct {
+		sequence string
+		body     string
+		expected string
+	}{
+		{"false", `[]`, "pool must be a non-empty JSON array of responses"},
+		{"false", `[{"status": 200}, {"status": 999}]`, "pool[1]: status {999} does not exist (nearest supported: 508, 510, 511)"},
+		{"true", `[{"status": 200}]`, "sequence and pool cannot be combined"},
+	}
+	for _, test := range tests {
+		if _, err := mocker.New(params(test.sequence), []byte(test.body)); err == nil || err.Error() != test.expected {
+			t.Fatalf(`result: {%v} but expected {%v}`, err, test.expected)
+		}
+	}
+}
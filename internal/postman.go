@@ -0,0 +1,116 @@
+package internal
+
+import (
+	"fmt"
+	"net/http"
+	"slices"
+
+	"github.com/joakim-ribier/go-utils/pkg/jsonsutil"
+	"github.com/joakim-ribier/go-utils/pkg/slicesutil"
+	"github.com/joakim-ribier/mockapic/pkg"
+)
+
+const postmanSchema = "https://schema.getpostman.com/json/collection/v2.1.0/collection.json"
+
+// PostmanCollection represents a Postman collection (v2.1)
+type PostmanCollection struct {
+	Info PostmanInfo   `json:"info"`
+	Item []PostmanItem `json:"item"`
+}
+
+type PostmanInfo struct {
+	Name   string `json:"name"`
+	Schema string `json:"schema"`
+}
+
+type PostmanItem struct {
+	Name     string            `json:"name"`
+	Request  PostmanRequest    `json:"request"`
+	Response []PostmanResponse `json:"response"`
+}
+
+type PostmanRequest struct {
+	Method      string          `json:"method"`
+	Header      []PostmanHeader `json:"header"`
+	URL         PostmanURL      `json:"url"`
+	Description string          `json:"description,omitempty"`
+}
+
+type PostmanURL struct {
+	Raw string `json:"raw"`
+}
+
+type PostmanResponse struct {
+	Name   string          `json:"name"`
+	Status string          `json:"status"`
+	Code   int             `json:"code"`
+	Header []PostmanHeader `json:"header"`
+	Body   string          `json:"body,omitempty"`
+}
+
+type PostmanHeader struct {
+	Key   string `json:"key"`
+	Value string `json:"value"`
+}
+
+// ExportPostman serializes all the mocked requests into a Postman collection (v2.1),
+// each item requests the mocked request on the "{baseURL}/{id}" URL (ex: "http://localhost:3333/v1/{id}").
+func ExportPostman(mocker Mocker, baseURL string) ([]byte, error) {
+	mockedRequestLights, err := mocker.List()
+	if err != nil {
+		return nil, err
+	}
+
+	collection := PostmanCollection{
+		Info: PostmanInfo{Name: "Mockapic", Schema: postmanSchema},
+		Item: []PostmanItem{},
+	}
+
+	for _, mrl := range mockedRequestLights {
+		mock, err := mocker.Get(mrl.Id)
+		if err != nil {
+			return nil, err
+		}
+		collection.Item = append(collection.Item, toPostmanItem(*mock, baseURL))
+	}
+
+	return jsonsutil.Marshal(collection)
+}
+
+func toPostmanItem(mock MockedRequest, baseURL string) PostmanItem {
+	headers := []PostmanHeader{}
+	if mock.ContentType != "" {
+		headers = append(headers, PostmanHeader{Key: "Content-Type", Value: mock.ContentType})
+	}
+	keys := make([]string, 0, len(mock.Headers))
+	for key := range mock.Headers {
+		keys = append(keys, key)
+	}
+	slices.Sort(keys)
+	for _, key := range keys {
+		headers = append(headers, PostmanHeader{Key: key, Value: mock.Headers[key]})
+	}
+
+	body := ""
+	if slicesutil.Exist(pkg.IS_DISPLAY_CONTENT, mock.ContentType) {
+		body = string(mock.Body64)
+	}
+
+	return PostmanItem{
+		Name: mock.Id,
+		Request: PostmanRequest{
+			Method: "GET",
+			Header: []PostmanHeader{},
+			URL:    PostmanURL{Raw: baseURL + "/" + mock.Id},
+			Description: fmt.Sprintf(
+				"Expected status {%d} with content type {%s}", mock.Status, mock.ContentType),
+		},
+		Response: []PostmanResponse{{
+			Name:   fmt.Sprintf("%d %s", mock.Status, http.StatusText(mock.Status)),
+			Status: http.StatusText(mock.Status),
+			Code:   mock.Status,
+			Header: headers,
+			Body:   body,
+		}},
+	}
+}
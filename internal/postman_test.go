@@ -0,0 +1,50 @@
+package internal
+
+import (
+	"testing"
+
+	"github.com/joakim-ribier/go-utils/pkg/jsonsutil"
+)
+
+// TestExportPostman calls ExportPostman(Mocker, string),
+// checking for a valid return value.
+func TestExportPostman(t *testing.T) {
+	mocker := NewInMemoryMock(*logger)
+	id, _ := mocker.New(reqParams, []byte("Hello World"))
+	mocker.New(reqParams, nil)
+	mocker.New(reqParams, nil)
+
+	data, err := ExportPostman(mocker, "http://localhost:3333/v1")
+	if err != nil {
+		t.Fatalf(err.Error())
+	}
+
+	collection, err := jsonsutil.Unmarshal[PostmanCollection](data)
+	if err != nil || collection.Info.Schema != postmanSchema || len(collection.Item) != 3 {
+		t.Fatalf(`result: {%v} but expected {%v}`, collection, 3)
+	}
+
+	for _, item := range collection.Item {
+		if item.Name != *id {
+			continue
+		}
+		response := item.Response[0]
+		if item.Request.URL.Raw != "http://localhost:3333/v1/"+*id ||
+			response.Code != 200 ||
+			response.Body != "Hello World" ||
+			response.Header[0] != (PostmanHeader{Key: "Content-Type", Value: "text/plain"}) {
+			t.Fatalf(`result: {%v} but expected {%v}`, item, *id)
+		}
+		return
+	}
+	t.Fatalf(`result: {%v} but expected {%v}`, collection.Item, *id)
+}
+
+// TestExportPostmanWithoutMockedRequest calls ExportPostman(Mocker, string),
+// checking for a valid return value.
+func TestExportPostmanWithoutMockedRequest(t *testing.T) {
+	data, err := ExportPostman(NewInMemoryMock(*logger), "http://localhost:3333/v1")
+	if err != nil || string(data) != `{"info":{"name":"Mockapic","schema":"`+postmanSchema+`"},"item":[]}` {
+		t.Fatalf(`result: {%v} but expected {%v}`, string(data), "no item")
+	}
+}
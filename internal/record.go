@@ -0,0 +1,56 @@
+package internal
+
+import (
+	"io"
+	"mime"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"github.com/joakim-ribier/go-utils/pkg/slicesutil"
+	"github.com/joakim-ribier/mockapic/pkg"
+)
+
+// recordIgnoredHeaders are the headers of a recorded response which are not kept in the mocked request
+var recordIgnoredHeaders = []string{
+	"Connection",
+	"Content-Encoding",
+	"Content-Length",
+	"Content-Type",
+	"Date",
+	"Keep-Alive",
+	"Set-Cookie",
+	"Trailer",
+	"Transfer-Encoding",
+	"Upgrade",
+}
+
+// RecordParams builds the parameters and the body to create a mocked request (see {Mocker.New}) from the recorded {resp}:
+// the unsupported content type and charset fall back to the nearest supported values.
+func RecordParams(resp *http.Response) (map[string][]string, []byte, error) {
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	contentType, charset := "text/plain", "UTF-8"
+	if mediaType, params, err := mime.ParseMediaType(resp.Header.Get("Content-Type")); err == nil {
+		contentType = toContentType(mediaType)
+		if value := strings.ToUpper(params["charset"]); slicesutil.Exist(pkg.CHARSET, value) {
+			charset = value
+		}
+	}
+
+	reqParams := map[string][]string{
+		"status":      {strconv.Itoa(resp.StatusCode)},
+		"contentType": {contentType},
+		"charset":     {charset},
+	}
+	for key := range resp.Header {
+		if !slicesutil.Exist(recordIgnoredHeaders, key) {
+			reqParams[key] = resp.Header.Values(key)
+		}
+	}
+
+	return reqParams, body, nil
+}
@@ -0,0 +1,62 @@
+package internal
+
+import (
+	"io"
+	"net/http"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+// TestRecordParams calls RecordParams(*http.Response),
+// checking for a valid return value.
+func TestRecordParams(t *testing.T) {
+	resp := &http.Response{
+		StatusCode: 201,
+		Header: http.Header{
+			"Content-Type":   {"application/json; charset=utf-8"},
+			"Content-Length": {"20"},
+			"X-Language":     {"golang"},
+		},
+		Body: io.NopCloser(strings.NewReader(`{"name": "mockapic"}`)),
+	}
+
+	reqParams, body, err := RecordParams(resp)
+	if err != nil {
+		t.Fatalf(err.Error())
+	}
+
+	expected := map[string][]string{
+		"status":      {"201"},
+		"contentType": {"application/json"},
+		"charset":     {"UTF-8"},
+		"X-Language":  {"golang"},
+	}
+	if !reflect.DeepEqual(reqParams, expected) || string(body) != `{"name": "mockapic"}` {
+		t.Fatalf(`result: {%v} but expected {%v}`, reqParams, expected)
+	}
+}
+
+// TestRecordParamsWithUnsupportedContentType calls RecordParams(*http.Response),
+// checking for a valid return value.
+func TestRecordParamsWithUnsupportedContentType(t *testing.T) {
+	tests := map[string][]string{
+		"":                                 {"text/plain", "UTF-8"},
+		"application/problem+json":         {"application/json", "UTF-8"},
+		"text/plain; charset=windows-1252": {"text/plain", "UTF-8"},
+		"text/html; charset=ISO-8859-1":    {"text/html", "ISO-8859-1"},
+	}
+
+	for contentType, expected := range tests {
+		resp := &http.Response{
+			StatusCode: 200,
+			Header:     http.Header{"Content-Type": {contentType}},
+			Body:       io.NopCloser(strings.NewReader("")),
+		}
+
+		reqParams, _, err := RecordParams(resp)
+		if err != nil || reqParams["contentType"][0] != expected[0] || reqParams["charset"][0] != expected[1] {
+			t.Fatalf(`%s - result: {%v} but expected {%v}`, contentType, reqParams, expected)
+		}
+	}
+}
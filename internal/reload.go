@@ -0,0 +1,25 @@
+package internal
+
+// Reload clears the caches (compiled path regexes and parsed mocked requests) and re-scans the storage,
+// so the files updated on disk are served.
+func (m Mock) Reload() error {
+	clearPathRegexes()
+	m.cache.clear()
+
+	mu := lock(m.workingDirectory)
+	mu.RLock()
+	defer mu.RUnlock()
+
+	mockedRequestsLight, err := m.list()
+	if err != nil {
+		return err
+	}
+	m.logger.Info("mocked requests reloaded", "workingDirectory", m.workingDirectory, "nb", len(mockedRequestsLight))
+	return nil
+}
+
+// Reload clears the caches (compiled path regexes), the mocked requests are only kept in memory.
+func (m *InMemoryMock) Reload() error {
+	clearPathRegexes()
+	return nil
+}
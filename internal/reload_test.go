@@ -0,0 +1,46 @@
+package internal
+
+import (
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+// TestReload calls Mock.Reload(),
+// checking for a valid return value.
+func TestReload(t *testing.T) {
+	directory := t.TempDir()
+	mocker := NewMock(directory, nil, *logger)
+	id, err := mocker.New(map[string][]string{
+		"contentType": {"text/plain"}, "charset": {"UTF-8"}, "when.method": {"GET"}, "when.pathRegex": {"/users/[0-9]+"},
+	}, []byte("Hello World"))
+	if err != nil {
+		t.Fatalf(err.Error())
+	}
+	if _, err := mocker.Match(httptest.NewRequest("GET", "/users/1", nil)); err != nil {
+		t.Fatalf(`result: {%v} but expected {%v}`, err, nil)
+	}
+	if _, is := pathRegexes.Load("/users/[0-9]+"); !is {
+		t.Fatalf(`result: {%v} but expected {%v}`, is, true)
+	}
+
+	if err := mocker.Reload(); err != nil {
+		t.Fatalf(`result: {%v} but expected {%v}`, err, nil)
+	}
+	if _, is := pathRegexes.Load("/users/[0-9]+"); is {
+		t.Fatalf(`result: {%v} but expected {%v}`, is, false)
+	}
+
+	// the mocked request removed on disk is not served anymore
+	if err := os.Remove(filepath.Join(directory, *id+".json")); err != nil {
+		t.Fatalf(err.Error())
+	}
+	if _, err := mocker.Match(httptest.NewRequest("GET", "/users/1", nil)); err == nil {
+		t.Fatalf(`result: {%v} but expected {%v}`, err, "no match")
+	}
+
+	if err := NewMock(filepath.Join(directory, "unknown"), nil, *logger).Reload(); err == nil {
+		t.Fatalf(`result: {%v} but expected {%v}`, err, "error")
+	}
+}
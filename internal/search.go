@@ -0,0 +1,59 @@
+package internal
+
+import (
+	"slices"
+	"time"
+
+	"github.com/joakim-ribier/go-utils/pkg/slicesutil"
+)
+
+// SearchFilter represents the criteria to search the mocked requests,
+// a zero value criterion is ignored so an empty filter matches all the requests
+type SearchFilter struct {
+	Status      int
+	ContentType string
+	Since       time.Time
+	Tag         string
+	Collection  string
+}
+
+// IsEmpty returns true if no criterion is defined.
+func (f SearchFilter) IsEmpty() bool {
+	return f.Status == 0 && f.ContentType == "" && f.Since.IsZero() && f.Tag == "" && f.Collection == ""
+}
+
+// Match returns true if the {mrl} fulfils all the defined criteria of the filter.
+func (f SearchFilter) Match(mrl MockedRequestLight) bool {
+	if f.Status != 0 && mrl.Status != f.Status {
+		return false
+	}
+
+	if f.ContentType != "" && mrl.ContentType != f.ContentType {
+		return false
+	}
+
+	if f.Tag != "" && !slices.Contains(mrl.Tags, f.Tag) {
+		return false
+	}
+
+	if f.Collection != "" && mrl.Collection != f.Collection {
+		return false
+	}
+
+	if !f.Since.IsZero() {
+		createdAt, err := ParseTime(mrl.CreatedAt)
+		if err != nil || createdAt.Before(f.Since) {
+			return false
+		}
+	}
+
+	return true
+}
+
+// search filters the {mockedRequestsLight} using the {filter}.
+func search(mockedRequestsLight []MockedRequestLight, filter SearchFilter) []MockedRequestLight {
+	if filter.IsEmpty() {
+		return mockedRequestsLight
+	}
+	return slicesutil.FilterT[MockedRequestLight](mockedRequestsLight, filter.Match)
+}
@@ -0,0 +1,55 @@
+package internal
+
+import (
+	"testing"
+	"time"
+)
+
+// TestSearchFilterMatch calls SearchFilter.Match(MockedRequestLight),
+// checking for a valid return value.
+func TestSearchFilterMatch(t *testing.T) {
+	mrl := MockedRequestLight{
+		CreatedAt: "2024-06-01 10:00:00",
+		Tags:      []string{"team-payments", "v2"},
+		MockedRequestHeader: MockedRequestHeader{
+			Status:      404,
+			ContentType: "application/json",
+		},
+	}
+
+	since := func(value string) time.Time {
+		date, _ := time.ParseInLocation("2006-01-02", value, time.Local)
+		return date
+	}
+
+	tests := []struct {
+		filter   SearchFilter
+		expected bool
+	}{
+		{SearchFilter{}, true},
+		{SearchFilter{Status: 404}, true},
+		{SearchFilter{Status: 200}, false},
+		{SearchFilter{ContentType: "application/json"}, true},
+		{SearchFilter{ContentType: "text/plain"}, false},
+		{SearchFilter{Since: since("2024-01-01")}, true},
+		{SearchFilter{Since: since("2024-07-01")}, false},
+		{SearchFilter{Tag: "team-payments"}, true},
+		{SearchFilter{Tag: "team-orders"}, false},
+		{SearchFilter{Status: 404, ContentType: "application/json", Since: since("2024-01-01")}, true},
+		{SearchFilter{Status: 404, ContentType: "text/plain", Since: since("2024-01-01")}, false},
+	}
+
+	for _, test := range tests {
+		if r := test.filter.Match(mrl); r != test.expected {
+			t.Fatalf(`%v - result: {%v} but expected {%v}`, test.filter, r, test.expected)
+		}
+	}
+}
+
+// TestSearchFilterMatchWithoutCreatedAt calls SearchFilter.Match(MockedRequestLight),
+// checking for a valid return value.
+func TestSearchFilterMatchWithoutCreatedAt(t *testing.T) {
+	if r := (SearchFilter{Since: time.Now()}).Match(MockedRequestLight{}); r {
+		t.Fatalf(`result: {%v} but expected {%v}`, r, false)
+	}
+}
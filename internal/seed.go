@@ -0,0 +1,139 @@
+package internal
+
+import (
+	"encoding/json"
+	"fmt"
+	"path/filepath"
+	"strings"
+
+	"github.com/joakim-ribier/go-utils/pkg/genericsutil"
+	"github.com/joakim-ribier/go-utils/pkg/iosutil"
+	"github.com/joakim-ribier/go-utils/pkg/jsonsutil"
+	"github.com/joakim-ribier/go-utils/pkg/stringsutil"
+	"gopkg.in/yaml.v3"
+)
+
+// loadSeed loads and validates the mocked requests of the JSON (or YAML if its extension is ".yaml" or ".yml") seed file {path},
+// each mocked request must define its own id (UUID) to be created only once.
+func loadSeed(path string) ([]MockedRequest, error) {
+	data, err := iosutil.Load(path)
+	if err != nil {
+		return nil, err
+	}
+
+	format := "JSON"
+	if ext := strings.ToLower(filepath.Ext(path)); ext == ".yaml" || ext == ".yml" {
+		format = "YAML"
+		data, err = yamlToJSON(data)
+		if err != nil {
+			return nil, fmt.Errorf("seed file {%s} must be a YAML array of mocked requests", path)
+		}
+	}
+
+	mockedRequests, err := jsonsutil.Unmarshal[[]MockedRequest](data)
+	if err != nil {
+		return nil, fmt.Errorf("seed file {%s} must be a %s array of mocked requests", path, format)
+	}
+
+	for i, mock := range mockedRequests {
+		mock, err := toImportedMockedRequest(mock)
+		if err != nil {
+			return nil, fmt.Errorf("seed[%d]: %w", i, err)
+		}
+		mockedRequests[i] = mock
+	}
+	return mockedRequests, nil
+}
+
+// yamlToJSON converts the YAML {data} to JSON to be decoded as the JSON seed files (same field names)
+func yamlToJSON(data []byte) ([]byte, error) {
+	var value any
+	if err := yaml.Unmarshal(data, &value); err != nil {
+		return nil, err
+	}
+	return json.Marshal(value)
+}
+
+// toImportedMockedRequest validates the {mock} defined with its own id (UUID),
+// the status and the creation date fall back to the default values if they are not defined.
+func toImportedMockedRequest(mock MockedRequest) (MockedRequest, error) {
+	if err := validateId(mock.Id); err != nil {
+		return mock, err
+	}
+
+	mock.Status = genericsutil.OrElse(mock.Status, func() bool { return mock.Status != 0 }, 200)
+	mock.CreatedAt = stringsutil.OrElse(mock.CreatedAt, timestamp())
+	if mock.Headers == nil {
+		mock.Headers = map[string]string{}
+	}
+	if len(mock.Body) > 0 {
+		mock.Body64 = []byte(mock.Body)
+		mock.Body = ""
+	}
+
+	mock.canonicalize()
+	if err := validate(mock); err != nil {
+		return mock, err
+	}
+	return mock, nil
+}
+
+// Seed creates the mocked requests of the JSON or YAML seed file {path} which do not exist yet on the storage,
+// it returns the number of created requests.
+func (m Mock) Seed(path string) (int, error) {
+	mockedRequests, err := loadSeed(path)
+	if err != nil {
+		return 0, err
+	}
+	return m.Import(mockedRequests)
+}
+
+// Import stores the {mockedRequests} (see {toImportedMockedRequest}) which do not exist yet on the storage
+// or in the predefined requests, it returns the number of created requests.
+func (m Mock) Import(mockedRequests []MockedRequest) (int, error) {
+	mu := lock(m.workingDirectory)
+	mu.Lock()
+	defer mu.Unlock()
+
+	nb := 0
+	for _, mock := range mockedRequests {
+		if err := validateId(mock.Id); err != nil {
+			return nb, err
+		}
+		if m.exists(mock.Id) || m.findPredefined(mock.Id) > -1 {
+			continue
+		}
+		if err := m.write(&mock); err != nil {
+			return nb, err
+		}
+		nb = nb + 1
+	}
+	return nb, nil
+}
+
+// Seed creates the mocked requests of the JSON or YAML seed file {path} which do not exist yet,
+// it returns the number of created requests.
+func (m *InMemoryMock) Seed(path string) (int, error) {
+	mockedRequests, err := loadSeed(path)
+	if err != nil {
+		return 0, err
+	}
+	return m.Import(mockedRequests)
+}
+
+// Import keeps the {mockedRequests} (see {toImportedMockedRequest}) which do not exist yet,
+// it returns the number of created requests.
+func (m *InMemoryMock) Import(mockedRequests []MockedRequest) (int, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	nb := 0
+	for _, mock := range mockedRequests {
+		if _, is := m.mockedRequests[mock.Id]; is {
+			continue
+		}
+		m.mockedRequests[mock.Id] = mock
+		nb = nb + 1
+	}
+	return nb, nil
+}
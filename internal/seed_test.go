@@ -0,0 +1,125 @@
+package internal
+
+import (
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/joakim-ribier/go-utils/pkg/iosutil"
+)
+
+// TestSeed calls Mock.Seed(string),
+// checking for a valid return value.
+func TestSeed(t *testing.T) {
+	dir := t.TempDir()
+	id1, id2 := uuid.NewString(), uuid.NewString()
+
+	seed := `[
+		{"id": "` + id1 + `", "contentType": "text/plain", "charset": "UTF-8", "body": "Hello World"},
+		{"id": "` + id2 + `", "status": 404, "contentType": "Application/JSON", "charset": "utf-8"},
+		{"id": "` + id1 + `", "contentType": "text/plain", "charset": "UTF-8", "body": "Duplicate"}
+	]`
+	if err := iosutil.Write([]byte(seed), dir+"/seed.json"); err != nil {
+		t.Fatalf(err.Error())
+	}
+
+	mocker := NewMock(t.TempDir(), nil, *logger)
+
+	if r, err := mocker.Seed(dir + "/seed.json"); err != nil || r != 2 {
+		t.Fatalf(`result: {%v} but expected {%v}`, r, 2)
+	}
+
+	r, err := mocker.Get(id1)
+	if err != nil || r.Status != 200 || string(r.Body64) != "Hello World" {
+		t.Fatalf(`result: {%v} but expected {%v}`, r, "Hello World")
+	}
+
+	// test if the content type and the charset are canonicalized
+	r, err = mocker.Get(id2)
+	if err != nil || r.ContentType != "application/json" || r.Charset != "UTF-8" {
+		t.Fatalf(`result: {%v} but expected {%v}`, r, "application/json UTF-8")
+	}
+
+	// test if the mocked requests already exist (restart)
+	if r, err := mocker.Seed(dir + "/seed.json"); err != nil || r != 0 {
+		t.Fatalf(`result: {%v} but expected {%v}`, r, 0)
+	}
+
+	if r, _ := mocker.Count(); r != 2 {
+		t.Fatalf(`result: {%v} but expected {%v}`, r, 2)
+	}
+
+	// test with the in-memory implementation
+	if r, err := NewInMemoryMock(*logger).Seed(dir + "/seed.json"); err != nil || r != 2 {
+		t.Fatalf(`result: {%v} but expected {%v}`, r, 2)
+	}
+}
+
+// TestSeedWithYAMLFile calls Mock.Seed(string),
+// checking for a valid return value.
+func TestSeedWithYAMLFile(t *testing.T) {
+	dir := t.TempDir()
+	id1, id2 := uuid.NewString(), uuid.NewString()
+
+	seed := `
+- id: ` + id1 + `
+  contentType: text/plain
+  charset: UTF-8
+  body: Hello World
+- id: ` + id2 + `
+  status: 404
+  contentType: application/json
+  charset: UTF-8
+  headers:
+    x-language: golang
+`
+	for _, name := range []string{"seed.yaml", "seed.yml"} {
+		if err := iosutil.Write([]byte(seed), dir+"/"+name); err != nil {
+			t.Fatalf(err.Error())
+		}
+
+		mocker := NewMock(t.TempDir(), nil, *logger)
+		if r, err := mocker.Seed(dir + "/" + name); err != nil || r != 2 {
+			t.Fatalf(`result: {%v} but expected {%v}`, err, 2)
+		}
+
+		r, err := mocker.Get(id2)
+		if err != nil || r.Status != 404 || r.Headers["x-language"] != "golang" {
+			t.Fatalf(`result: {%v} but expected {%v}`, r, 404)
+		}
+		if r, err := mocker.Get(id1); err != nil || r.Status != 200 || string(r.Body64) != "Hello World" {
+			t.Fatalf(`result: {%v} but expected {%v}`, r, "Hello World")
+		}
+	}
+
+	// test if the YAML is malformed
+	iosutil.Write([]byte("- id: [wrong"), dir+"/seed.yaml")
+	expected := "seed file {" + dir + "/seed.yaml} must be a YAML array of mocked requests"
+	if _, err := NewMock(t.TempDir(), nil, *logger).Seed(dir + "/seed.yaml"); err == nil || err.Error() != expected {
+		t.Fatalf(`result: {%v} but expected {%v}`, err, expected)
+	}
+}
+
+// TestSeedWithBadFile calls Mock.Seed(string),
+// checking for a valid return value.
+func TestSeedWithBadFile(t *testing.T) {
+	dir := t.TempDir()
+
+	tests := map[string]string{
+		`{"id": "wrong"}`: "seed file {" + dir + "/seed.json} must be a JSON array of mocked requests",
+		`[{"id": "wrong", "contentType": "text/plain", "charset": "UTF-8"}]`:               "seed[0]: mocked request id {wrong} is malformed",
+		`[{"id": "` + uuid.NewString() + `", "contentType": "wrong", "charset": "UTF-8"}]`: "seed[0]: content type {wrong} does not exist",
+	}
+
+	for seed, expected := range tests {
+		iosutil.Write([]byte(seed), dir+"/seed.json")
+
+		if _, err := NewMock(t.TempDir(), nil, *logger).Seed(dir + "/seed.json"); err == nil || err.Error() != expected {
+			t.Fatalf(`result: {%v} but expected {%v}`, err, expected)
+		}
+	}
+
+	// test if the file does not exist
+	if r, err := NewMock(t.TempDir(), nil, *logger).Seed(dir + "/does-not-exist.json"); err == nil {
+		t.Fatalf(`result: {%v} but expected error`, r)
+	}
+}
@@ -0,0 +1,78 @@
+package server
+
+import (
+	"net/http"
+	"time"
+)
+
+// accessLogWriter records the status, the size and the served mock of the response written by a handler
+type accessLogWriter struct {
+	http.ResponseWriter
+	status int
+	size   int
+	mockId string
+}
+
+func (w *accessLogWriter) WriteHeader(statusCode int) {
+	if w.status == 0 {
+		w.status = statusCode
+	}
+	w.ResponseWriter.WriteHeader(statusCode)
+}
+
+func (w *accessLogWriter) Write(data []byte) (int, error) {
+	if w.status == 0 {
+		w.status = 200
+	}
+	n, err := w.ResponseWriter.Write(data)
+	w.size += n
+	return n, err
+}
+
+func (w *accessLogWriter) recordStatus(statusCode int) {
+	if w.status == 0 {
+		w.status = statusCode
+	}
+}
+
+// Unwrap returns the underlying {http.ResponseWriter} (used by {http.ResponseController})
+func (w *accessLogWriter) Unwrap() http.ResponseWriter {
+	return w.ResponseWriter
+}
+
+// setServedMockId records the identifier of the mock served by the handler (if the access log or the history is enabled)
+func setServedMockId(w http.ResponseWriter, mockId string) {
+	for {
+		switch rw := w.(type) {
+		case *accessLogWriter:
+			rw.mockId = mockId
+			w = rw.ResponseWriter
+		case interface{ Unwrap() http.ResponseWriter }:
+			w = rw.Unwrap()
+		default:
+			return
+		}
+	}
+}
+
+// logAccess logs the method, the path, the served mock, the status, the body size and the elapsed time of each request
+func (s HTTPServer) logAccess(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		start := time.Now()
+		alw := &accessLogWriter{ResponseWriter: w}
+
+		next.ServeHTTP(alw, r)
+
+		if alw.status == 0 {
+			alw.status = 200
+		}
+		s.logger.Info("access",
+			"method", r.Method,
+			"path", r.URL.Path,
+			"mockId", alw.mockId,
+			"status", alw.status,
+			"size", alw.size,
+			"elapsed", time.Since(start).String(),
+		)
+	})
+}
@@ -0,0 +1,62 @@
+package server
+
+import (
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"testing"
+
+	"github.com/joakim-ribier/go-utils/pkg/logsutil"
+	"github.com/joakim-ribier/mockapic/internal"
+)
+
+// TestLogAccess calls HTTPServer.logAccess(http.Handler),
+// checking for a valid return value.
+func TestLogAccess(t *testing.T) {
+	mocker := &MockerTest{
+		mockResponse: &internal.MockedRequest{
+			MockedRequestLight: internal.MockedRequestLight{
+				Id: "{id}",
+				MockedRequestHeader: internal.MockedRequestHeader{
+					Status:      201,
+					ContentType: "text/plain",
+					Charset:     "UTF-8",
+				},
+			},
+			When:   &internal.Matcher{Path: "/api/*"},
+			Body64: []byte("Hello World"),
+		},
+	}
+
+	fields := map[string]interface{}{}
+	s := NewHTTPServer("{port}", false, "", workingDirectory, mocker, *logger, WithAccessLog(true))
+	s.logger = logsutil.Logger{
+		Info: func(msg string, keysAndValues ...any) {
+			for i := 0; i < len(keysAndValues); i += 2 {
+				fields[fmt.Sprint(keysAndValues[i])] = keysAndValues[i+1]
+			}
+		},
+	}
+
+	req := httptest.NewRequest(http.MethodGet, "http://localhost:3333/api/users", nil)
+	w := httptest.NewRecorder()
+
+	s.logAccess(http.HandlerFunc(s.matchMockedRequest)).ServeHTTP(w, req)
+
+	if _, is := fields["elapsed"]; !is {
+		t.Fatalf(`result: {%v} but expected {%v}`, fields, "elapsed")
+	}
+	delete(fields, "elapsed")
+
+	expected := map[string]interface{}{
+		"method": "GET",
+		"path":   "/api/users",
+		"mockId": "{id}",
+		"status": 201,
+		"size":   11,
+	}
+	if w.Code != 201 || !reflect.DeepEqual(fields, expected) {
+		t.Fatalf(`result: {%v} but expected {%v}`, fields, expected)
+	}
+}
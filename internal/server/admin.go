@@ -0,0 +1,64 @@
+package server
+
+import (
+	"context"
+	"crypto/subtle"
+	"errors"
+	"net/http"
+	"time"
+)
+
+// adminTokenHeader is the header which carries the admin token of the "/admin/*" endpoints
+const adminTokenHeader = "X-Admin-Token"
+
+// adminShutdownTimeout is the max duration to drain the in-flight requests on "/admin/shutdown"
+const adminShutdownTimeout = 30 * time.Second
+
+// WithAdminToken enables the "/admin/*" endpoints for the requests which carry the {token} in the "X-Admin-Token" header,
+// the endpoints are disabled (404) if the {token} is empty
+func WithAdminToken(token string) Option {
+	return func(s *HTTPServer) {
+		s.adminToken = token
+	}
+}
+
+// isAdmin returns true if the request carries the admin token (constant time comparison)
+func (s HTTPServer) isAdmin(r *http.Request) bool {
+	return subtle.ConstantTimeCompare([]byte(r.Header.Get(adminTokenHeader)), []byte(s.adminToken)) == 1
+}
+
+// reload clears the caches of the mocked requests and re-scans the storage (see {internal.Mocker.Reload})
+func (s HTTPServer) reload(w http.ResponseWriter, r *http.Request) {
+	if !s.isAdmin(r) {
+		writeError(w, errors.New("admin token is missing or invalid"), 401)
+		return
+	}
+
+	if err := s.mocker.Reload(); err != nil {
+		s.logger.Error(err, "error to reload mocked requests", "uri", r.RequestURI)
+		writeError(w, err, 500)
+		return
+	}
+
+	s.logger.Info("reload requested", "remoteAddr", r.RemoteAddr)
+	s.writeResponse(w, r, map[string]string{"message": "mocked requests reloaded"})
+}
+
+// shutdown stops the server gracefully (see {HTTPServer.Shutdown}) once the response is sent
+func (s HTTPServer) shutdown(w http.ResponseWriter, r *http.Request) {
+	if !s.isAdmin(r) {
+		writeError(w, errors.New("admin token is missing or invalid"), 401)
+		return
+	}
+
+	s.logger.Info("shutdown requested", "remoteAddr", r.RemoteAddr)
+	s.writeResponse(w, r, map[string]string{"message": "server is shutting down"})
+
+	go func() {
+		ctx, cancel := context.WithTimeout(context.Background(), adminShutdownTimeout)
+		defer cancel()
+		if err := s.Shutdown(ctx); err != nil {
+			s.logger.Error(err, "error to shut down the server")
+		}
+	}()
+}
@@ -0,0 +1,99 @@
+package server
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/joakim-ribier/mockapic/internal"
+)
+
+// TestShutdownEndpoint calls HTTPServer.shutdown(http.ResponseWriter, *http.Request),
+// checking for a valid return value.
+func TestShutdownEndpoint(t *testing.T) {
+	httpServer := NewHTTPServer("3339", false, "", workingDirectory, internal.NewInMemoryMock(*logger), *logger,
+		WithAdminToken("s3cr3t"))
+	done := make(chan error, 1)
+	go func() {
+		done <- httpServer.Listen()
+	}()
+	time.Sleep(100 * time.Millisecond)
+
+	call := func(token string) *http.Response {
+		req, _ := http.NewRequest(http.MethodPost, "http://localhost:3339/admin/shutdown", nil)
+		if token != "" {
+			req.Header.Set("X-Admin-Token", token)
+		}
+		resp, err := http.DefaultClient.Do(req)
+		if err != nil {
+			t.Fatalf(err.Error())
+		}
+		resp.Body.Close()
+		return resp
+	}
+
+	// test if the token is missing or invalid
+	for _, token := range []string{"", "wrong"} {
+		if resp := call(token); resp.StatusCode != 401 {
+			t.Fatalf(`result: {%v} but expected {%v}`, resp.StatusCode, 401)
+		}
+	}
+
+	if resp := call("s3cr3t"); resp.StatusCode != 200 {
+		t.Fatalf(`result: {%v} but expected {%v}`, resp.StatusCode, 200)
+	}
+
+	select {
+	case err := <-done:
+		if err != nil {
+			t.Fatalf(`result: {%v} but expected {%v}`, err, nil)
+		}
+	case <-time.After(time.Second):
+		t.Fatalf(`result: {%v} but expected {%v}`, "running", "stopped")
+	}
+}
+
+// TestShutdownEndpointWithoutToken calls HTTPServer.handler(),
+// checking for a valid return value.
+func TestShutdownEndpointWithoutToken(t *testing.T) {
+	s := NewHTTPServer("{port}", false, "", workingDirectory, internal.NewInMemoryMock(*logger), *logger)
+
+	req := httptest.NewRequest(http.MethodPost, "http://localhost:3333/admin/shutdown", nil)
+	req.Header.Set("X-Admin-Token", "")
+	w := httptest.NewRecorder()
+	s.handler().ServeHTTP(w, req)
+
+	if res, _ := geResultResponse(w, t); res.StatusCode != 404 {
+		t.Fatalf(`result: {%v} but expected {%v}`, res.StatusCode, 404)
+	}
+}
+
+// TestReloadEndpoint calls HTTPServer.reload(http.ResponseWriter, *http.Request),
+// checking for a valid return value.
+func TestReloadEndpoint(t *testing.T) {
+	handler := NewHTTPServer("{port}", false, "", workingDirectory, internal.NewMock(t.TempDir(), nil, *logger), *logger,
+		WithAdminToken("s3cr3t")).handler()
+
+	call := func(token string) *httptest.ResponseRecorder {
+		req := httptest.NewRequest(http.MethodPost, "http://localhost:3333/admin/reload", nil)
+		if token != "" {
+			req.Header.Set("X-Admin-Token", token)
+		}
+		w := httptest.NewRecorder()
+		handler.ServeHTTP(w, req)
+		return w
+	}
+
+	// test if the token is missing or invalid
+	for _, token := range []string{"", "wrong"} {
+		if w := call(token); w.Code != 401 {
+			t.Fatalf(`result: {%v} but expected {%v}`, w.Code, 401)
+		}
+	}
+
+	w := call("s3cr3t")
+	if _, body := geResultResponse(w, t); w.Code != 200 || string(body) != `{"message":"mocked requests reloaded"}` {
+		t.Fatalf(`result: {%v} but expected {%v}`, string(body), 200)
+	}
+}
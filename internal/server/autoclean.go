@@ -0,0 +1,42 @@
+package server
+
+import (
+	"sync/atomic"
+)
+
+// autoClean trims the storage to {maxLimit} mocked requests every {every} created mocked requests
+type autoClean struct {
+	every    int64
+	maxLimit int
+	created  atomic.Int64
+}
+
+// WithAutoClean runs {Mocker.Clean} with the {maxLimit} after every {every} created mocked requests,
+// it is disabled if one of the values is not positive
+func WithAutoClean(every, maxLimit int) Option {
+	return func(s *HTTPServer) {
+		if every > 0 && maxLimit > 0 {
+			s.autoClean = &autoClean{every: int64(every), maxLimit: maxLimit}
+		} else {
+			s.autoClean = nil
+		}
+	}
+}
+
+// countCreated counts a created mocked request and cleans the storage if the auto clean is due
+func (s HTTPServer) countCreated() {
+	if s.autoClean == nil {
+		return
+	}
+
+	if s.autoClean.created.Add(1)%s.autoClean.every != 0 {
+		return
+	}
+
+	nb, err := s.mocker.Clean(s.autoClean.maxLimit)
+	if err != nil {
+		s.logger.Error(err, "error to auto clean the mocked requests")
+	} else if nb > 0 {
+		s.logger.Info("mocked requests auto cleaned", "nb", nb, "maxLimit", s.autoClean.maxLimit)
+	}
+}
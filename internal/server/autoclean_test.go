@@ -0,0 +1,46 @@
+package server
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/joakim-ribier/mockapic/internal"
+)
+
+// TestAutoClean calls HTTPServer.addNewMock(http.ResponseWriter, *http.Request) with the auto clean,
+// checking for a valid return value.
+func TestAutoClean(t *testing.T) {
+	mocker := internal.NewInMemoryMock(*logger)
+	s := NewHTTPServer("{port}", false, "", t.TempDir(), mocker, *logger, WithAutoClean(5, 3))
+
+	create := func(nb int) {
+		for range nb {
+			req := httptest.NewRequest(http.MethodPost, "http://localhost:3333/v1/new?contentType=text/plain&charset=UTF-8", strings.NewReader("Hello World"))
+			s.addNewMock(httptest.NewRecorder(), req)
+		}
+	}
+
+	tests := []struct {
+		created  int
+		expected int
+	}{
+		{4, 4},  // not due yet
+		{1, 3},  // 5th creation: trimmed to the max limit
+		{5, 3},  // 10th creation
+		{2, 5},  // not due yet
+		{28, 3}, // 40th creation
+	}
+	for _, test := range tests {
+		create(test.created)
+		if nb, _ := mocker.Count(); nb != test.expected {
+			t.Fatalf(`result: {%v} but expected {%v}`, nb, test.expected)
+		}
+	}
+
+	// test if the auto clean is disabled with a zero value
+	if s := NewHTTPServer("{port}", false, "", t.TempDir(), mocker, *logger, WithAutoClean(0, 3)); s.autoClean != nil {
+		t.Fatalf(`result: {%v} but expected {%v}`, s.autoClean, nil)
+	}
+}
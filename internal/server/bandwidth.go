@@ -0,0 +1,66 @@
+package server
+
+import (
+	"context"
+	"net/http"
+	"time"
+)
+
+// throttledWriter paces the body written to the response at {bytesPerSecond},
+// the pacing stops (the remaining body is written at once) when it exceeds the {budget} (the max delay)
+type throttledWriter struct {
+	http.ResponseWriter
+	sleep          func(time.Duration) bool
+	bytesPerSecond int
+	budget         time.Duration
+	start          time.Time
+	written        int
+}
+
+// newThrottledWriter wraps the writer of the {r} response to pace it at {kbps} kilobytes per second
+func newThrottledWriter(r Response, kbps int) *throttledWriter {
+	return &throttledWriter{
+		ResponseWriter: r.ResponseWriter,
+		sleep:          r.sleep,
+		bytesPerSecond: kbps << 10,
+		budget:         r.DelayMax,
+	}
+}
+
+// Write writes the {p} bytes by slices (a tenth of a second of throughput) flushed and paced to respect the bandwidth
+func (w *throttledWriter) Write(p []byte) (int, error) {
+	if w.start.IsZero() {
+		w.start = time.Now()
+	}
+
+	controller := http.NewResponseController(w.ResponseWriter)
+	sliceSize := max(w.bytesPerSecond/10, 1)
+	total := 0
+	for len(p) > 0 {
+		elapsed := time.Duration(float64(w.written+min(sliceSize, len(p))) / float64(w.bytesPerSecond) * float64(time.Second))
+		if elapsed > w.budget {
+			n, err := w.ResponseWriter.Write(p)
+			w.written += n
+			return total + n, err
+		}
+
+		n, err := w.ResponseWriter.Write(p[:min(sliceSize, len(p))])
+		w.written += n
+		total += n
+		if err != nil {
+			return total, err
+		}
+		p = p[n:]
+
+		controller.Flush()
+		if wait := time.Until(w.start.Add(elapsed)); wait > 0 && !w.sleep(wait) {
+			return total, context.Canceled
+		}
+	}
+	return total, nil
+}
+
+// Unwrap returns the original writer (used by {http.ResponseController})
+func (w *throttledWriter) Unwrap() http.ResponseWriter {
+	return w.ResponseWriter
+}
@@ -0,0 +1,70 @@
+package server
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/joakim-ribier/mockapic/internal"
+)
+
+// TestWriteWithBandwidth calls Response.Write(internal.Mock, string),
+// checking for a valid return value.
+func TestWriteWithBandwidth(t *testing.T) {
+	body := strings.Repeat("a", 4<<10)
+	mocked := internal.MockedRequest{
+		MockedRequestLight: internal.MockedRequestLight{
+			MockedRequestHeader: internal.MockedRequestHeader{
+				Status:      200,
+				ContentType: "text/plain",
+				Charset:     "UTF-8",
+			},
+		},
+		BandwidthKbps: 8,
+		Body64:        []byte(body),
+	}
+
+	for name, chunkSize := range map[string]int{"streamed body": 0, "chunked body": 1 << 10} {
+		mocked.ChunkSize = chunkSize
+		w := httptest.NewRecorder()
+
+		start := time.Now()
+		NewResponse(w, httptest.NewRequest(http.MethodGet, "http://localhost:3333/v1/{id}", nil), "60s").Write(mocked, "")
+		elapsed := time.Since(start)
+
+		// 4 KB at 8 KB/s
+		if elapsed < 500*time.Millisecond || elapsed > 2*time.Second || w.Body.String() != body {
+			t.Fatalf(`%s result: {%v} but expected {%v}`, name, elapsed, "500ms")
+		}
+	}
+}
+
+// TestWriteWithBandwidthAndMaxDelay calls Response.Write(internal.Mock, string),
+// checking for a valid return value.
+func TestWriteWithBandwidthAndMaxDelay(t *testing.T) {
+	body := strings.Repeat("a", 8<<10)
+	mocked := internal.MockedRequest{
+		MockedRequestLight: internal.MockedRequestLight{
+			MockedRequestHeader: internal.MockedRequestHeader{
+				Status:      200,
+				ContentType: "text/plain",
+				Charset:     "UTF-8",
+			},
+		},
+		BandwidthKbps: 1,
+		Body64:        []byte(body),
+	}
+
+	w := httptest.NewRecorder()
+
+	start := time.Now()
+	NewResponse(w, httptest.NewRequest(http.MethodGet, "http://localhost:3333/v1/{id}", nil), "200ms").Write(mocked, "")
+	elapsed := time.Since(start)
+
+	// the throttling (8s) is capped by the max delay
+	if elapsed > time.Second || w.Body.String() != body {
+		t.Fatalf(`result: {%v} but expected {%v}`, elapsed, "200ms")
+	}
+}
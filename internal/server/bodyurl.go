@@ -0,0 +1,218 @@
+package server
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"net/netip"
+	"sync"
+	"syscall"
+	"time"
+
+	"github.com/joakim-ribier/mockapic/internal"
+)
+
+// defaultBodyURLTTL is the duration a body fetched from the body URL of a mocked request is cached
+const defaultBodyURLTTL = time.Minute
+
+// bodyURLTimeout is the max duration to fetch the body URL of a mocked request
+const bodyURLTimeout = 10 * time.Second
+
+// maxBodyCacheEntries is the max number of bodies cached, the entry which expires first is evicted beyond
+const maxBodyCacheEntries = 128
+
+// WithBodyURLTTL overrides the duration the bodies fetched from the body URL of the mocked requests are cached,
+// a zero {ttl} fetches the body URL on each request
+func WithBodyURLTTL(ttl time.Duration) Option {
+	return func(s *HTTPServer) {
+		if ttl >= 0 {
+			s.bodies.setTTL(ttl)
+		}
+	}
+}
+
+// WithPrivateBodyURL allows the body URLs of the mocked requests and the recorded URLs to target the loopback, private
+// and link-local addresses (e.g. a local backend), they are rejected by default
+func WithPrivateBodyURL(allow bool) Option {
+	return func(s *HTTPServer) {
+		s.bodies.setPrivate(allow)
+	}
+}
+
+// bodyCache keeps the bodies fetched from the body URLs of the mocked requests for a TTL
+type bodyCache struct {
+	mu      sync.Mutex
+	ttl     time.Duration
+	private bool
+	entries map[string]bodyCacheEntry
+	client  *http.Client
+	now     func() time.Time
+}
+
+type bodyCacheEntry struct {
+	body      []byte
+	expiresAt time.Time
+}
+
+// newBodyCache creates a {bodyCache} which keeps the fetched bodies for the {ttl}
+func newBodyCache(ttl time.Duration) *bodyCache {
+	c := &bodyCache{
+		ttl:     ttl,
+		entries: map[string]bodyCacheEntry{},
+		now:     time.Now,
+	}
+	c.client = c.newClient(bodyURLTimeout)
+	return c
+}
+
+// newClient creates a client which rejects the private addresses (see {bodyCache.control}),
+// the address is checked once resolved (redirects included) and the environment proxy is ignored
+func (c *bodyCache) newClient(timeout time.Duration) *http.Client {
+	dialer := &net.Dialer{Timeout: timeout, Control: c.control}
+	return &http.Client{
+		Timeout:   timeout,
+		Transport: &http.Transport{DialContext: dialer.DialContext},
+	}
+}
+
+func (c *bodyCache) setTTL(ttl time.Duration) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	c.ttl = ttl
+}
+
+func (c *bodyCache) setPrivate(private bool) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	c.private = private
+}
+
+// control rejects the connections to the loopback, private, link-local and unspecified addresses (if not allowed)
+func (c *bodyCache) control(network, address string, conn syscall.RawConn) error {
+	c.mu.Lock()
+	private := c.private
+	c.mu.Unlock()
+	if private {
+		return nil
+	}
+
+	addrPort, err := netip.ParseAddrPort(address)
+	if err != nil {
+		return err
+	}
+	addr := addrPort.Addr().Unmap()
+	if addr.IsLoopback() || addr.IsPrivate() || addr.IsLinkLocalUnicast() || addr.IsLinkLocalMulticast() || addr.IsUnspecified() {
+		return fmt.Errorf("address {%s} is not allowed", addr)
+	}
+	return nil
+}
+
+// get returns the cached body of the {bodyURL} or fetches it (limited to {maxSize} bytes) if it's not cached (or expired)
+func (c *bodyCache) get(ctx context.Context, bodyURL string, maxSize int64) ([]byte, error) {
+	c.mu.Lock()
+	entry, is := c.entries[bodyURL]
+	c.mu.Unlock()
+	if is && c.now().Before(entry.expiresAt) {
+		return entry.body, nil
+	}
+
+	body, err := c.fetch(ctx, bodyURL, maxSize)
+	if err != nil {
+		return nil, err
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if c.ttl > 0 {
+		c.put(bodyURL, bodyCacheEntry{body: body, expiresAt: c.now().Add(c.ttl)})
+	} else {
+		delete(c.entries, bodyURL)
+	}
+	return body, nil
+}
+
+// put caches the {entry} of the {bodyURL} once the expired entries are removed,
+// the entry which expires first is evicted if the cache is full (the lock must be held)
+func (c *bodyCache) put(bodyURL string, entry bodyCacheEntry) {
+	now := c.now()
+	for key, value := range c.entries {
+		if !now.Before(value.expiresAt) {
+			delete(c.entries, key)
+		}
+	}
+
+	if _, is := c.entries[bodyURL]; !is && len(c.entries) >= maxBodyCacheEntries {
+		first := ""
+		for key, value := range c.entries {
+			if first == "" || value.expiresAt.Before(c.entries[first].expiresAt) {
+				first = key
+			}
+		}
+		delete(c.entries, first)
+	}
+	c.entries[bodyURL] = entry
+}
+
+// fetch gets the body of the {bodyURL}, the non successful (2xx) responses and the bodies over {maxSize} bytes are errors
+func (c *bodyCache) fetch(ctx context.Context, bodyURL string, maxSize int64) ([]byte, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, bodyURL, nil)
+	if err != nil {
+		return nil, fmt.Errorf("body url {%s} cannot be fetched", bodyURL)
+	}
+
+	res, err := c.client.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("body url {%s} cannot be fetched", bodyURL)
+	}
+	defer res.Body.Close()
+
+	if res.StatusCode < 200 || res.StatusCode > 299 {
+		return nil, fmt.Errorf("body url {%s} returned the status {%d}", bodyURL, res.StatusCode)
+	}
+
+	body, err := io.ReadAll(io.LimitReader(res.Body, maxSize+1))
+	if err != nil {
+		return nil, fmt.Errorf("body url {%s} cannot be read", bodyURL)
+	}
+	if int64(len(body)) > maxSize {
+		return nil, fmt.Errorf("body url {%s} exceeds the max size of {%d} bytes", bodyURL, maxSize)
+	}
+	return body, nil
+}
+
+// writeBodyURL replaces the body of the {mock} by the body fetched from its body URL (if defined),
+// a 502 error is written if the body URL cannot be fetched and false is returned
+func (r Response) writeBodyURL(mock *internal.MockedRequest) bool {
+	if mock.BodyURL == "" {
+		return true
+	}
+
+	bodies := r.Bodies
+	if bodies == nil {
+		bodies = newBodyCache(0)
+	}
+
+	ctx := context.Background()
+	if r.Request != nil {
+		ctx = r.Request.Context()
+	}
+
+	maxSize := r.MaxBodySize
+	if maxSize < 1 {
+		maxSize = defaultMaxBodySize
+	}
+
+	body, err := bodies.get(ctx, mock.BodyURL, maxSize)
+	if err != nil {
+		writeError(r.ResponseWriter, err, 502)
+		return false
+	}
+
+	mock.Body = ""
+	mock.Body64 = body
+	return true
+}
@@ -0,0 +1,172 @@
+package server
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/joakim-ribier/mockapic/internal"
+)
+
+// TestWriteWithBodyURL calls Response.Write(internal.Mock, string),
+// checking for a valid return value.
+func TestWriteWithBodyURL(t *testing.T) {
+	var calls atomic.Int32
+	backend := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		calls.Add(1)
+		if r.URL.Path == "/error" {
+			w.WriteHeader(500)
+			return
+		}
+		w.Write([]byte(`{"name": "mockapic"}`))
+	}))
+	defer backend.Close()
+
+	mock := func(path string) *internal.MockedRequest {
+		return &internal.MockedRequest{
+			MockedRequestLight: internal.MockedRequestLight{
+				MockedRequestHeader: internal.MockedRequestHeader{
+					Status:      201,
+					ContentType: "application/json",
+					Charset:     "UTF-8",
+				},
+			},
+			BodyURL: backend.URL + path,
+		}
+	}
+
+	s := NewHTTPServer("{port}", false, "", workingDirectory, &MockerTest{mockResponse: mock("/body")}, *logger, WithPrivateBodyURL(true))
+	handler := s.handler()
+	for i := 0; i < 3; i++ {
+		w := httptest.NewRecorder()
+		handler.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "http://localhost:3333/v1/{id}", nil))
+
+		res, body := geResultResponse(w, t)
+		if res.StatusCode != 201 || res.Header.Get("Content-Type") != "application/json; charset=UTF-8" || string(body) != `{"name": "mockapic"}` {
+			t.Fatalf(`result: {%v} but expected {%v}`, string(body), `{"name": "mockapic"}`)
+		}
+	}
+	if calls.Load() != 1 {
+		t.Fatalf(`result: {%v} but expected {%v}`, calls.Load(), 1)
+	}
+
+	// the cached body expires after the TTL
+	s.bodies.now = func() time.Time { return time.Now().Add(2 * defaultBodyURLTTL) }
+	handler.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(http.MethodGet, "http://localhost:3333/v1/{id}", nil))
+	if calls.Load() != 2 {
+		t.Fatalf(`result: {%v} but expected {%v}`, calls.Load(), 2)
+	}
+
+	w := httptest.NewRecorder()
+	NewHTTPServer("{port}", false, "", workingDirectory, &MockerTest{mockResponse: mock("/error")}, *logger, WithPrivateBodyURL(true)).handler().
+		ServeHTTP(w, httptest.NewRequest(http.MethodGet, "http://localhost:3333/v1/{id}", nil))
+
+	expected := `{"message": "body url {` + backend.URL + `/error} returned the status {500}"}`
+	if res, body := geResultResponse(w, t); res.StatusCode != 502 || string(body) != expected {
+		t.Fatalf(`result: {%v} but expected {%v}`, string(body), expected)
+	}
+}
+
+// TestWriteWithUnreachableBodyURL calls Response.Write(internal.Mock, string),
+// checking for a valid return value.
+func TestWriteWithUnreachableBodyURL(t *testing.T) {
+	backend := httptest.NewServer(http.NotFoundHandler())
+	backend.Close()
+
+	mocked := internal.MockedRequest{
+		MockedRequestLight: internal.MockedRequestLight{
+			MockedRequestHeader: internal.MockedRequestHeader{Status: 200, ContentType: "text/plain"},
+		},
+		BodyURL: backend.URL,
+	}
+
+	w := httptest.NewRecorder()
+	response := NewResponse(w, httptest.NewRequest(http.MethodGet, "http://localhost:3333/v1/{id}", nil), "60s")
+	response.Bodies = newBodyCache(0)
+	response.Bodies.setPrivate(true)
+	err := response.Write(mocked, "")
+
+	expected := `{"message": "body url {` + backend.URL + `} cannot be fetched"}`
+	if res, body := geResultResponse(w, t); err != nil || res.StatusCode != 502 || string(body) != expected {
+		t.Fatalf(`result: {%v} but expected {%v}`, string(body), expected)
+	}
+}
+
+// TestWriteWithPrivateBodyURL calls Response.Write(internal.Mock, string),
+// checking for a valid return value.
+func TestWriteWithPrivateBodyURL(t *testing.T) {
+	var calls atomic.Int32
+	backend := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		calls.Add(1)
+		w.Write([]byte("Hello World"))
+	}))
+	defer backend.Close()
+
+	mocked := internal.MockedRequest{
+		MockedRequestLight: internal.MockedRequestLight{
+			MockedRequestHeader: internal.MockedRequestHeader{Status: 200, ContentType: "text/plain"},
+		},
+		BodyURL: backend.URL,
+	}
+
+	for _, bodyURL := range []string{backend.URL, "http://169.254.169.254/latest/meta-data", "http://10.0.0.1"} {
+		mocked.BodyURL = bodyURL
+		w := httptest.NewRecorder()
+		NewResponse(w, httptest.NewRequest(http.MethodGet, "http://localhost:3333/v1/{id}", nil), "60s").Write(mocked, "")
+
+		expected := `{"message": "body url {` + bodyURL + `} cannot be fetched"}`
+		if res, body := geResultResponse(w, t); res.StatusCode != 502 || string(body) != expected {
+			t.Fatalf(`result: {%v} but expected {%v}`, string(body), expected)
+		}
+	}
+	if calls.Load() != 0 {
+		t.Fatalf(`result: {%v} but expected {%v}`, calls.Load(), 0)
+	}
+}
+
+// TestWriteWithOversizedBodyURL calls Response.Write(internal.Mock, string),
+// checking for a valid return value.
+func TestWriteWithOversizedBodyURL(t *testing.T) {
+	backend := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte("Hello World"))
+	}))
+	defer backend.Close()
+
+	s := NewHTTPServer("{port}", false, "", workingDirectory, &MockerTest{mockResponse: &internal.MockedRequest{
+		MockedRequestLight: internal.MockedRequestLight{
+			MockedRequestHeader: internal.MockedRequestHeader{Status: 200, ContentType: "text/plain"},
+		},
+		BodyURL: backend.URL,
+	}}, *logger, WithPrivateBodyURL(true), WithMaxBodySize(5))
+
+	w := httptest.NewRecorder()
+	s.handler().ServeHTTP(w, httptest.NewRequest(http.MethodGet, "http://localhost:3333/v1/{id}", nil))
+
+	expected := `{"message": "body url {` + backend.URL + `} exceeds the max size of {5} bytes"}`
+	if res, body := geResultResponse(w, t); res.StatusCode != 502 || string(body) != expected {
+		t.Fatalf(`result: {%v} but expected {%v}`, string(body), expected)
+	}
+}
+
+// TestBodyCachePut calls bodyCache.put(string, bodyCacheEntry),
+// checking for a valid return value.
+func TestBodyCachePut(t *testing.T) {
+	c := newBodyCache(time.Minute)
+	now := time.Now()
+
+	c.put("expired", bodyCacheEntry{expiresAt: now.Add(-time.Second)})
+	for i := 0; i < maxBodyCacheEntries+10; i++ {
+		c.put(strconv.Itoa(i), bodyCacheEntry{expiresAt: now.Add(time.Duration(i+1) * time.Second)})
+	}
+
+	if _, is := c.entries["expired"]; is || len(c.entries) != maxBodyCacheEntries {
+		t.Fatalf(`result: {%v} but expected {%v}`, len(c.entries), maxBodyCacheEntries)
+	}
+	// test if the entries which expire first are evicted
+	if _, is := c.entries["0"]; is {
+		t.Fatalf(`result: {%v} but expected {%v}`, "0", "evicted")
+	}
+}
@@ -0,0 +1,114 @@
+package server
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"sync"
+	"time"
+
+	"github.com/joakim-ribier/go-utils/pkg/jsonsutil"
+)
+
+// defaultMaxDelay is the max delay of a served mocked request
+const defaultMaxDelay = 60 * time.Second
+
+// serverConfig contains the settings of the server which can be updated at runtime
+type serverConfig struct {
+	mu          sync.RWMutex
+	maxDelay    time.Duration
+	failureRate float64
+}
+
+func (c *serverConfig) getMaxDelay() time.Duration {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+
+	return c.maxDelay
+}
+
+func (c *serverConfig) setMaxDelay(maxDelay time.Duration) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	c.maxDelay = maxDelay
+}
+
+func (c *serverConfig) getFailureRate() float64 {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+
+	return c.failureRate
+}
+
+func (c *serverConfig) setFailureRate(failureRate float64) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	c.failureRate = failureRate
+}
+
+// getConfig writes the current config of the server
+func (s HTTPServer) getConfig(w http.ResponseWriter, r *http.Request) {
+	s.writeResponse(w, r, map[string]interface{}{
+		"maxDelay":       s.config.getMaxDelay().String(),
+		"failureRate":    s.config.getFailureRate(),
+		"maxBodySize":    s.maxBodySize,
+		"storageMaxSize": s.storageMaxSize,
+		"accessLog":      s.accessLog,
+		"disableDelay":   s.disableDelay,
+		"rateLimit":      s.rateLimiter != nil,
+		"metrics":        s.metrics != nil,
+		"history":        s.history != nil,
+	})
+}
+
+// updateMaxDelay updates the max delay of the next served mocked requests from the {"maxDelay": "{duration}"} body
+func (s HTTPServer) updateMaxDelay(w http.ResponseWriter, r *http.Request) {
+	body, ok := s.readBody(w, r)
+	if !ok {
+		return
+	}
+
+	data, err := jsonsutil.Unmarshal[map[string]string](body)
+	if err != nil {
+		writeError(w, errors.New("body must be a JSON object with a {maxDelay} duration"), 400)
+		return
+	}
+
+	maxDelay, err := time.ParseDuration(data["maxDelay"])
+	if err != nil || maxDelay < 0 {
+		writeError(w, fmt.Errorf("max delay {%s} is malformed", data["maxDelay"]), 400)
+		return
+	}
+
+	s.config.setMaxDelay(maxDelay)
+	s.logger.Info("max delay updated", "maxDelay", maxDelay.String())
+
+	s.getConfig(w, r)
+}
+
+// updateFailureRate updates the global failure rate of the next served mocked requests from the {"failureRate": {rate}} body
+func (s HTTPServer) updateFailureRate(w http.ResponseWriter, r *http.Request) {
+	body, ok := s.readBody(w, r)
+	if !ok {
+		return
+	}
+
+	data, err := jsonsutil.Unmarshal[map[string]float64](body)
+	if err != nil {
+		writeError(w, errors.New("body must be a JSON object with a {failureRate} number"), 400)
+		return
+	}
+
+	failureRate, is := data["failureRate"]
+	if !is || failureRate < 0 || failureRate > 1 {
+		writeError(w, fmt.Errorf("failure rate {%v} must be between 0 and 1", failureRate), 400)
+		return
+	}
+
+	s.config.setFailureRate(failureRate)
+	s.logger.Info("failure rate updated", "failureRate", failureRate)
+
+	s.getConfig(w, r)
+}
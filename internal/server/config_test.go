@@ -0,0 +1,128 @@
+package server
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/joakim-ribier/go-utils/pkg/jsonsutil"
+	"github.com/joakim-ribier/mockapic/internal"
+)
+
+// TestUpdateMaxDelay calls HTTPServer.updateMaxDelay(http.ResponseWriter, *http.Request),
+// checking for a valid return value.
+func TestUpdateMaxDelay(t *testing.T) {
+	mocker := &MockerTest{
+		mockResponse: &internal.MockedRequest{
+			MockedRequestLight: internal.MockedRequestLight{
+				MockedRequestHeader: internal.MockedRequestHeader{
+					Status:      200,
+					ContentType: "text/plain",
+					Charset:     "UTF-8",
+				},
+			},
+			Body64: []byte("Hello World"),
+		},
+	}
+	handler := NewHTTPServer("{port}", false, "", workingDirectory, mocker, *logger).handler()
+
+	updateMaxDelay := func(maxDelay string) {
+		w := httptest.NewRecorder()
+		handler.ServeHTTP(w, httptest.NewRequest(http.MethodPut, "http://localhost:3333/v1/config/max-delay",
+			strings.NewReader(`{"maxDelay": "`+maxDelay+`"}`)))
+
+		_, body := geResultResponse(w, t)
+		if config, _ := jsonsutil.Unmarshal[map[string]any](body); w.Code != 200 || config["maxDelay"] != maxDelay {
+			t.Fatalf(`result: {%v} but expected {%v}`, string(body), maxDelay)
+		}
+	}
+	serve := func() time.Duration {
+		start := time.Now()
+		handler.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(http.MethodGet, "http://localhost:3333/v1/{id}?delay=300ms", nil))
+		return time.Since(start)
+	}
+
+	updateMaxDelay("50ms")
+	if elapsed := serve(); elapsed >= 250*time.Millisecond {
+		t.Fatalf(`result: {%v} but expected {%v}`, elapsed, "50ms max")
+	}
+
+	updateMaxDelay("1s")
+	if elapsed := serve(); elapsed < 300*time.Millisecond {
+		t.Fatalf(`result: {%v} but expected {%v}`, elapsed, "300ms min")
+	}
+}
+
+// TestWithoutDelay calls HTTPServer.getMockedRequest(http.ResponseWriter, *http.Request),
+// checking for a valid return value.
+func TestWithoutDelay(t *testing.T) {
+	mocker := &MockerTest{
+		mockResponse: &internal.MockedRequest{
+			MockedRequestLight: internal.MockedRequestLight{
+				MockedRequestHeader: internal.MockedRequestHeader{
+					Status:      200,
+					ContentType: "text/plain",
+					Charset:     "UTF-8",
+				},
+			},
+			Delay:  "10s",
+			Body64: []byte("Hello World"),
+		},
+	}
+	serve := func(handler http.Handler, target string) time.Duration {
+		start := time.Now()
+		handler.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(http.MethodGet, target, nil))
+		return time.Since(start)
+	}
+
+	handler := NewHTTPServer("{port}", false, "", workingDirectory, mocker, *logger, WithoutDelay(true)).handler()
+	if elapsed := serve(handler, "http://localhost:3333/v1/{id}"); elapsed >= 10*time.Millisecond {
+		t.Fatalf(`result: {%v} but expected {%v}`, elapsed, "less than 10ms")
+	}
+
+	handler = NewHTTPServer("{port}", false, "", workingDirectory, mocker, *logger).handler()
+	if elapsed := serve(handler, "http://localhost:3333/v1/{id}?nodelay=true"); elapsed >= 10*time.Millisecond {
+		t.Fatalf(`result: {%v} but expected {%v}`, elapsed, "less than 10ms")
+	}
+}
+
+// TestUpdateMaxDelayWithBadRequest calls HTTPServer.updateMaxDelay(http.ResponseWriter, *http.Request),
+// checking for a valid return value.
+func TestUpdateMaxDelayWithBadRequest(t *testing.T) {
+	s := NewHTTPServer("{port}", false, "", workingDirectory, &MockerTest{}, *logger)
+
+	tests := map[string]string{
+		`["1s"]`:                `{"message": "body must be a JSON object with a {maxDelay} duration"}`,
+		`{"maxDelay": "wrong"}`: `{"message": "max delay {wrong} is malformed"}`,
+		`{"maxDelay": "-1s"}`:   `{"message": "max delay {-1s} is malformed"}`,
+	}
+	for reqBody, expected := range tests {
+		w := httptest.NewRecorder()
+		s.updateMaxDelay(w, httptest.NewRequest(http.MethodPut, "http://localhost:3333/v1/config/max-delay", strings.NewReader(reqBody)))
+
+		res, body := geResultResponse(w, t)
+		if res.StatusCode != 400 || string(body) != expected {
+			t.Fatalf(`result: {%v} but expected {%v}`, string(body), expected)
+		}
+	}
+
+	if maxDelay := s.config.getMaxDelay(); maxDelay != defaultMaxDelay {
+		t.Fatalf(`result: {%v} but expected {%v}`, maxDelay, defaultMaxDelay)
+	}
+}
+
+// TestGetConfig calls HTTPServer.getConfig(http.ResponseWriter, *http.Request),
+// checking for a valid return value.
+func TestGetConfig(t *testing.T) {
+	w := httptest.NewRecorder()
+	NewHTTPServer("{port}", false, "", workingDirectory, &MockerTest{}, *logger, WithMaxBodySize(1024)).
+		getConfig(w, httptest.NewRequest(http.MethodGet, "http://localhost:3333/v1/config", nil))
+
+	_, body := geResultResponse(w, t)
+	config, _ := jsonsutil.Unmarshal[map[string]any](body)
+	if config["maxDelay"] != "1m0s" || config["maxBodySize"] != float64(1024) || config["metrics"] != false {
+		t.Fatalf(`result: {%v} but expected {%v}`, config, "maxDelay: 1m0s")
+	}
+}
@@ -0,0 +1,21 @@
+package server
+
+import (
+	"net/http"
+)
+
+// echo writes the incoming request (method, path, query, headers and body) as a JSON object, whatever its method
+func (s HTTPServer) echo(w http.ResponseWriter, r *http.Request) {
+	body, ok := s.readBody(w, r)
+	if !ok {
+		return
+	}
+
+	s.writeResponse(w, r, map[string]any{
+		"method":  r.Method,
+		"path":    r.URL.Path,
+		"query":   r.URL.Query(),
+		"headers": r.Header,
+		"body":    string(body),
+	})
+}
@@ -0,0 +1,42 @@
+package server
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"slices"
+	"strings"
+	"testing"
+
+	"github.com/joakim-ribier/go-utils/pkg/jsonsutil"
+)
+
+// TestEchoEndpoint calls HTTPServer.echo(http.ResponseWriter, *http.Request),
+// checking for a valid return value.
+func TestEchoEndpoint(t *testing.T) {
+	handler := NewHTTPServer("{port}", false, "", workingDirectory, &MockerTest{}, *logger).handler()
+
+	for _, method := range []string{http.MethodPost, http.MethodPatch} {
+		req := httptest.NewRequest(method, "http://localhost:3333/v1/echo?debug=true", strings.NewReader(`{"name": "mockapic"}`))
+		req.Header.Set("X-Custom", "value")
+		req.Header.Add("X-Multi", "a")
+		req.Header.Add("X-Multi", "b")
+		w := httptest.NewRecorder()
+		handler.ServeHTTP(w, req)
+
+		res, body := geResultResponse(w, t)
+		echo, err := jsonsutil.Unmarshal[struct {
+			Method  string              `json:"method"`
+			Path    string              `json:"path"`
+			Query   map[string][]string `json:"query"`
+			Headers map[string][]string `json:"headers"`
+			Body    string              `json:"body"`
+		}](body)
+		if err != nil || res.StatusCode != 200 || echo.Method != method || echo.Path != "/v1/echo" ||
+			!slices.Equal(echo.Query["debug"], []string{"true"}) ||
+			!slices.Equal(echo.Headers["X-Custom"], []string{"value"}) ||
+			!slices.Equal(echo.Headers["X-Multi"], []string{"a", "b"}) ||
+			echo.Body != `{"name": "mockapic"}` {
+			t.Fatalf(`result: {%v} but expected {%v}`, string(body), method+" /v1/echo")
+		}
+	}
+}
@@ -0,0 +1,31 @@
+package server
+
+import (
+	"errors"
+	"math/rand/v2"
+	"net/http"
+)
+
+// globalFailureStatus is the status of the failures injected by the global failure rate
+const globalFailureStatus = 503
+
+// WithGlobalFailureRate injects a 503 failure in the {failureRate} fraction (between 0 and 1) of the served mocked requests,
+// independently of the failure rate of each mocked request (it can be updated on "/v1/config/failure-rate")
+func WithGlobalFailureRate(failureRate float64) Option {
+	return func(s *HTTPServer) {
+		if failureRate > 0 && failureRate <= 1 {
+			s.config.setFailureRate(failureRate)
+		}
+	}
+}
+
+// injectFailure responds 503 for the global failure rate fraction of the requests before serving any mocked request
+func (s HTTPServer) injectFailure(handle func(w http.ResponseWriter, r *http.Request)) func(w http.ResponseWriter, r *http.Request) {
+	return func(w http.ResponseWriter, r *http.Request) {
+		if failureRate := s.config.getFailureRate(); failureRate > 0 && rand.Float64() < failureRate {
+			writeError(w, errors.New("failure injected by the server"), globalFailureStatus)
+			return
+		}
+		handle(w, r)
+	}
+}
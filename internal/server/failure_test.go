@@ -0,0 +1,82 @@
+package server
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/joakim-ribier/mockapic/internal"
+)
+
+// TestInjectFailure calls HTTPServer.injectFailure(func(w http.ResponseWriter, r *http.Request)),
+// checking for a valid return value.
+func TestInjectFailure(t *testing.T) {
+	mocker := &MockerTest{
+		mockResponse: &internal.MockedRequest{
+			MockedRequestLight: internal.MockedRequestLight{
+				MockedRequestHeader: internal.MockedRequestHeader{
+					Status:      200,
+					ContentType: "text/plain",
+					Charset:     "UTF-8",
+				},
+			},
+			Body64: []byte("Hello World"),
+		},
+	}
+	handler := NewHTTPServer("{port}", false, "", workingDirectory, mocker, *logger, WithGlobalFailureRate(1.0)).handler()
+
+	for i := 0; i < 20; i++ {
+		w := httptest.NewRecorder()
+		handler.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "http://localhost:3333/v1/{id}", nil))
+
+		_, body := geResultResponse(w, t)
+		if w.Code != 503 || string(body) != `{"message": "failure injected by the server"}` {
+			t.Fatalf(`result: {%v} but expected {%v}`, w.Code, 503)
+		}
+	}
+
+	w := httptest.NewRecorder()
+	handler.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "http://localhost:3333/healthz", nil))
+	if w.Code != 200 {
+		t.Fatalf(`result: {%v} but expected {%v}`, w.Code, 200)
+	}
+
+	w = httptest.NewRecorder()
+	handler.ServeHTTP(w, httptest.NewRequest(http.MethodPut, "http://localhost:3333/v1/config/failure-rate",
+		strings.NewReader(`{"failureRate": 0}`)))
+	if w.Code != 200 {
+		t.Fatalf(`result: {%v} but expected {%v}`, w.Code, 200)
+	}
+
+	w = httptest.NewRecorder()
+	handler.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "http://localhost:3333/v1/{id}", nil))
+	if _, body := geResultResponse(w, t); w.Code != 200 || string(body) != "Hello World" {
+		t.Fatalf(`result: {%v} but expected {%v}`, string(body), "Hello World")
+	}
+}
+
+// TestUpdateFailureRateWithBadRequest calls HTTPServer.updateFailureRate(http.ResponseWriter, *http.Request),
+// checking for a valid return value.
+func TestUpdateFailureRateWithBadRequest(t *testing.T) {
+	s := NewHTTPServer("{port}", false, "", workingDirectory, &MockerTest{}, *logger)
+
+	tests := map[string]string{
+		`[0.1]`:                 `{"message": "body must be a JSON object with a {failureRate} number"}`,
+		`{"failureRate": 1.5}`:  `{"message": "failure rate {1.5} must be between 0 and 1"}`,
+		`{"failureRate": -0.1}`: `{"message": "failure rate {-0.1} must be between 0 and 1"}`,
+	}
+	for reqBody, expected := range tests {
+		w := httptest.NewRecorder()
+		s.updateFailureRate(w, httptest.NewRequest(http.MethodPut, "http://localhost:3333/v1/config/failure-rate", strings.NewReader(reqBody)))
+
+		res, body := geResultResponse(w, t)
+		if res.StatusCode != 400 || string(body) != expected {
+			t.Fatalf(`result: {%v} but expected {%v}`, string(body), expected)
+		}
+	}
+
+	if failureRate := s.config.getFailureRate(); failureRate != 0 {
+		t.Fatalf(`result: {%v} but expected {%v}`, failureRate, 0)
+	}
+}
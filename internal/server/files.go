@@ -0,0 +1,81 @@
+package server
+
+import (
+	"errors"
+	"fmt"
+	"mime"
+	"net/http"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+// errFileOutside is returned when a file resolves (symbolic links included) outside the files directory
+var errFileOutside = errors.New("file is outside the files directory")
+
+// WithFilesDirectory serves the files of the {directory} (fixtures) on "/v1/files/{name}",
+// the endpoint is disabled (404) if the {directory} is empty
+func WithFilesDirectory(directory string) Option {
+	return func(s *HTTPServer) {
+		s.filesDirectory = directory
+	}
+}
+
+// serveFile streams the file "/v1/files/{name}" of the files directory with the content type of its extension,
+// the {name} must be a local path of the directory (no path traversal, even through a symbolic link)
+func (s HTTPServer) serveFile(w http.ResponseWriter, r *http.Request) {
+	name := strings.TrimPrefix(r.URL.Path, "/v1/files/")
+	if name == "" || !filepath.IsLocal(filepath.FromSlash(name)) {
+		writeError(w, fmt.Errorf("file name {%s} is malformed", name), 400)
+		return
+	}
+
+	path, err := s.resolveFile(name)
+	if errors.Is(err, errFileOutside) {
+		writeError(w, fmt.Errorf("file name {%s} is malformed", name), 400)
+		return
+	}
+	if err != nil {
+		writeError(w, fmt.Errorf("file {%s} does not exist", name), 404)
+		return
+	}
+
+	file, err := os.Open(path)
+	if err != nil {
+		writeError(w, fmt.Errorf("file {%s} does not exist", name), 404)
+		return
+	}
+	defer file.Close()
+
+	info, err := file.Stat()
+	if err != nil || info.IsDir() {
+		writeError(w, fmt.Errorf("file {%s} does not exist", name), 404)
+		return
+	}
+
+	contentType := mime.TypeByExtension(filepath.Ext(name))
+	if contentType == "" {
+		contentType = "application/octet-stream"
+	}
+	w.Header().Set("Content-Type", contentType)
+
+	http.ServeContent(w, r, name, info.ModTime(), file)
+}
+
+// resolveFile returns the path of the file {name} with the symbolic links resolved,
+// an {errFileOutside} error is returned if it is not under the (resolved) files directory
+func (s HTTPServer) resolveFile(name string) (string, error) {
+	directory, err := filepath.EvalSymlinks(s.filesDirectory)
+	if err != nil {
+		return "", err
+	}
+	path, err := filepath.EvalSymlinks(filepath.Join(directory, filepath.FromSlash(name)))
+	if err != nil {
+		return "", err
+	}
+
+	if rel, err := filepath.Rel(directory, path); err != nil || !filepath.IsLocal(rel) {
+		return "", errFileOutside
+	}
+	return path, nil
+}
@@ -0,0 +1,106 @@
+package server
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+// TestServeFile calls HTTPServer.serveFile(http.ResponseWriter, *http.Request),
+// checking for a valid return value.
+func TestServeFile(t *testing.T) {
+	directory := t.TempDir()
+	if err := os.MkdirAll(filepath.Join(directory, "users"), os.ModePerm); err != nil {
+		t.Fatalf(err.Error())
+	}
+	if err := os.WriteFile(filepath.Join(directory, "users", "list.json"), []byte(`[{"name": "mockapic"}]`), 0644); err != nil {
+		t.Fatalf(err.Error())
+	}
+
+	handler := NewHTTPServer("{port}", false, "", workingDirectory, &MockerTest{}, *logger, WithFilesDirectory(directory)).handler()
+
+	w := httptest.NewRecorder()
+	handler.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "http://localhost:3333/v1/files/users/list.json", nil))
+
+	res, body := geResultResponse(w, t)
+	if res.StatusCode != 200 || res.Header.Get("Content-Type") != "application/json" || string(body) != `[{"name": "mockapic"}]` {
+		t.Fatalf(`result: {%v} but expected {%v}`, string(body), `[{"name": "mockapic"}]`)
+	}
+
+	w = httptest.NewRecorder()
+	handler.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "http://localhost:3333/v1/files/users", nil))
+	if w.Code != 404 {
+		t.Fatalf(`result: {%v} but expected {%v}`, w.Code, 404)
+	}
+
+	// the files endpoint is disabled without directory
+	w = httptest.NewRecorder()
+	NewHTTPServer("{port}", false, "", workingDirectory, &MockerTest{}, *logger).handler().
+		ServeHTTP(w, httptest.NewRequest(http.MethodGet, "http://localhost:3333/v1/files/users/list.json", nil))
+	if w.Code != 404 {
+		t.Fatalf(`result: {%v} but expected {%v}`, w.Code, 404)
+	}
+}
+
+// TestServeFileWithPathTraversal calls HTTPServer.serveFile(http.ResponseWriter, *http.Request),
+// checking for a valid return value.
+func TestServeFileWithPathTraversal(t *testing.T) {
+	directory := t.TempDir()
+	s := NewHTTPServer("{port}", false, "", workingDirectory, &MockerTest{}, *logger, WithFilesDirectory(filepath.Join(directory, "files")))
+	if err := os.WriteFile(filepath.Join(directory, "secret.txt"), []byte("secret"), 0644); err != nil {
+		t.Fatalf(err.Error())
+	}
+
+	for _, name := range []string{"../secret.txt", "users/../../secret.txt", "/etc/passwd", ""} {
+		req := httptest.NewRequest(http.MethodGet, "http://localhost:3333/v1/files/", nil)
+		req.URL.Path = "/v1/files/" + name
+		w := httptest.NewRecorder()
+		s.serveFile(w, req)
+
+		expected := `{"message": "file name {` + name + `} is malformed"}`
+		if res, body := geResultResponse(w, t); res.StatusCode != 400 || string(body) != expected {
+			t.Fatalf(`result: {%v} but expected {%v}`, string(body), expected)
+		}
+	}
+}
+
+// TestServeFileWithSymlink calls HTTPServer.serveFile(http.ResponseWriter, *http.Request),
+// checking for a valid return value.
+func TestServeFileWithSymlink(t *testing.T) {
+	directory := t.TempDir()
+	files := filepath.Join(directory, "files")
+	if err := os.MkdirAll(files, os.ModePerm); err != nil {
+		t.Fatalf(err.Error())
+	}
+	for name, content := range map[string]string{"secret.txt": "secret", "files/users.json": "[]"} {
+		if err := os.WriteFile(filepath.Join(directory, name), []byte(content), 0644); err != nil {
+			t.Fatalf(err.Error())
+		}
+	}
+	links := map[string]string{
+		"secret.txt": filepath.Join(directory, "secret.txt"),
+		"outside":    directory,
+		"users.json": filepath.Join(files, "users.json"),
+	}
+	for name, target := range links {
+		if err := os.Symlink(target, filepath.Join(files, "link-"+name)); err != nil {
+			t.Skip("symbolic links are not supported")
+		}
+	}
+
+	s := NewHTTPServer("{port}", false, "", workingDirectory, &MockerTest{}, *logger, WithFilesDirectory(files))
+	tests := map[string]int{
+		"link-secret.txt":         400,
+		"link-outside/secret.txt": 400,
+		"link-users.json":         200,
+	}
+	for name, expected := range tests {
+		w := httptest.NewRecorder()
+		s.serveFile(w, httptest.NewRequest(http.MethodGet, "http://localhost:3333/v1/files/"+name, nil))
+		if w.Code != expected {
+			t.Fatalf(`%s - result: {%v} but expected {%v}`, name, w.Code, expected)
+		}
+	}
+}
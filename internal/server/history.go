@@ -0,0 +1,107 @@
+package server
+
+import (
+	"fmt"
+	"net/http"
+	"strconv"
+	"sync"
+	"time"
+)
+
+// historyEntry represents a request handled by the server
+type historyEntry struct {
+	Method    string `json:"method"`
+	Path      string `json:"path"`
+	Timestamp string `json:"timestamp"`
+	MockId    string `json:"mockId,omitempty"`
+	Status    int    `json:"status"`
+}
+
+// history is a ring buffer of the last handled requests exposed on "/v1/history"
+type history struct {
+	mu      sync.Mutex
+	entries []historyEntry
+	next    int
+	full    bool
+}
+
+func newHistory(size int) *history {
+	return &history{entries: make([]historyEntry, size)}
+}
+
+// add records the {entry}, the oldest entry is overwritten if the buffer is full
+func (h *history) add(entry historyEntry) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	h.entries[h.next] = entry
+	h.next = (h.next + 1) % len(h.entries)
+	h.full = h.full || h.next == 0
+}
+
+// last returns the {limit} most recent entries (all of them if {limit} is negative), from the most recent
+func (h *history) last(limit int) []historyEntry {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	nb := h.next
+	if h.full {
+		nb = len(h.entries)
+	}
+	if limit >= 0 && limit < nb {
+		nb = limit
+	}
+
+	entries := make([]historyEntry, 0, nb)
+	for i := 1; i <= nb; i++ {
+		entries = append(entries, h.entries[(h.next-i+len(h.entries))%len(h.entries)])
+	}
+	return entries
+}
+
+// WithHistory keeps the last {size} handled requests (and the mock which served them) exposed on "/v1/history"
+func WithHistory(size int) Option {
+	return func(s *HTTPServer) {
+		if size > 0 {
+			s.history = newHistory(size)
+		} else {
+			s.history = nil
+		}
+	}
+}
+
+// recordHistory records the method, the path, the served mock and the status of each request in the history
+func (s HTTPServer) recordHistory(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		timestamp := time.Now().Format(time.RFC3339)
+		alw := &accessLogWriter{ResponseWriter: w}
+
+		next.ServeHTTP(alw, r)
+
+		if alw.status == 0 {
+			alw.status = 200
+		}
+		s.history.add(historyEntry{
+			Method:    r.Method,
+			Path:      r.URL.Path,
+			Timestamp: timestamp,
+			MockId:    alw.mockId,
+			Status:    alw.status,
+		})
+	})
+}
+
+// getHistory writes the last handled requests (from the most recent) limited by the "limit" parameter
+func (s HTTPServer) getHistory(w http.ResponseWriter, r *http.Request) {
+	limit := -1
+	if value := r.URL.Query().Get("limit"); value != "" {
+		nb, err := strconv.Atoi(value)
+		if err != nil || nb < 0 {
+			writeError(w, fmt.Errorf("limit {%s} must be a positive number", value), 400)
+			return
+		}
+		limit = nb
+	}
+
+	s.writeResponse(w, r, s.history.last(limit))
+}
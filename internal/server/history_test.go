@@ -0,0 +1,112 @@
+package server
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/joakim-ribier/go-utils/pkg/jsonsutil"
+	"github.com/joakim-ribier/mockapic/internal"
+)
+
+// TestHistory calls history.add(historyEntry) and history.last(int),
+// checking for a valid return value.
+func TestHistory(t *testing.T) {
+	h := newHistory(3)
+	for _, path := range []string{"/1", "/2", "/3", "/4"} {
+		h.add(historyEntry{Method: "GET", Path: path, Status: 200})
+	}
+
+	paths := []string{}
+	for _, entry := range h.last(-1) {
+		paths = append(paths, entry.Path)
+	}
+	if len(paths) != 3 || paths[0] != "/4" || paths[2] != "/2" {
+		t.Fatalf(`result: {%v} but expected {%v}`, paths, []string{"/4", "/3", "/2"})
+	}
+
+	if entries := h.last(1); len(entries) != 1 || entries[0].Path != "/4" {
+		t.Fatalf(`result: {%v} but expected {%v}`, entries, "/4")
+	}
+	if entries := newHistory(3).last(-1); len(entries) != 0 {
+		t.Fatalf(`result: {%v} but expected {%v}`, entries, "empty")
+	}
+}
+
+// TestGetHistoryEndpoint calls HTTPServer.handler(),
+// checking for a valid return value.
+func TestGetHistoryEndpoint(t *testing.T) {
+	mocker := internal.NewInMemoryMock(*logger)
+	id, err := mocker.New(map[string][]string{"contentType": {"text/plain"}, "charset": {"UTF-8"}}, []byte("Hello World"))
+	if err != nil {
+		t.Fatalf(err.Error())
+	}
+
+	handler := NewHTTPServer("{port}", false, "", workingDirectory, mocker, *logger, WithHistory(10), WithAccessLog(true)).handler()
+	call := func(uri string) *httptest.ResponseRecorder {
+		w := httptest.NewRecorder()
+		handler.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "http://localhost:3333"+uri, nil))
+		return w
+	}
+
+	call("/v1/" + *id)
+	call("/api/unknown")
+
+	w := call("/v1/history?limit=2")
+	entries, err := jsonsutil.Unmarshal[[]historyEntry](w.Body.Bytes())
+	if err != nil {
+		t.Fatalf(err.Error())
+	}
+	if len(entries) != 2 ||
+		entries[0].Path != "/api/unknown" || entries[0].Status != 404 || entries[0].MockId != "" ||
+		entries[1].Path != "/v1/"+*id || entries[1].Status != 200 || entries[1].MockId != *id {
+		t.Fatalf(`result: {%v} but expected {%v}`, entries, "the two last requests")
+	}
+	if _, err := time.Parse(time.RFC3339, entries[0].Timestamp); err != nil {
+		t.Fatalf(`result: {%v} but expected {%v}`, entries[0].Timestamp, "a RFC3339 timestamp")
+	}
+
+	if w := call("/v1/history?limit=wrong"); w.Code != 400 {
+		t.Fatalf(`result: {%v} but expected {%v}`, w.Code, 400)
+	}
+
+	// test if the history is disabled
+	handler = NewHTTPServer("{port}", false, "", workingDirectory, mocker, *logger).handler()
+	if w := call("/v1/history"); w.Code != 404 {
+		t.Fatalf(`result: {%v} but expected {%v}`, w.Code, 404)
+	}
+}
+
+// TestGetHistoryEndpointWithStatusText calls HTTPServer.handler(),
+// checking for a valid return value.
+func TestGetHistoryEndpointWithStatusText(t *testing.T) {
+	mocker := internal.NewInMemoryMock(*logger)
+	id, err := mocker.New(map[string][]string{
+		"status":      {"418"},
+		"statusText":  {"I Am A Teapot"},
+		"contentType": {"text/plain"},
+		"charset":     {"UTF-8"},
+	}, []byte("Hello World"))
+	if err != nil {
+		t.Fatalf(err.Error())
+	}
+	handler := NewHTTPServer("{port}", false, "", workingDirectory, mocker, *logger, WithHistory(10)).handler()
+
+	// the response is hijacked to write the status text
+	server := httptest.NewServer(handler)
+	defer server.Close()
+
+	resp, err := http.Get(server.URL + "/v1/" + *id)
+	if err != nil {
+		t.Fatal(err)
+	}
+	resp.Body.Close()
+
+	w := httptest.NewRecorder()
+	handler.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "http://localhost:3333/v1/history", nil))
+	entries, err := jsonsutil.Unmarshal[[]historyEntry](w.Body.Bytes())
+	if err != nil || resp.Status != "418 I Am A Teapot" || len(entries) != 1 || entries[0].Status != 418 || entries[0].MockId != *id {
+		t.Fatalf(`result: {%v} but expected {%v}`, entries, 418)
+	}
+}
@@ -1,14 +1,25 @@
 package server
 
 import (
+	"context"
+	"crypto/tls"
+	"encoding/json"
+	"errors"
 	"fmt"
 	"io"
+	"net"
 	"net/http"
 	"net/url"
+	"os"
 	"path"
+	"slices"
 	"strconv"
+	"strings"
+	"sync"
+	"time"
 
 	"github.com/jedib0t/go-pretty/v6/table"
+	"github.com/joakim-ribier/go-utils/pkg/httpsutil"
 	"github.com/joakim-ribier/go-utils/pkg/iosutil"
 	"github.com/joakim-ribier/go-utils/pkg/jsonsutil"
 	"github.com/joakim-ribier/go-utils/pkg/logsutil"
@@ -23,69 +34,437 @@ type HTTPServer struct {
 	Port             string
 	SSLEnabled       bool
 	certDirectory    string
+	selfSignedCert   bool
 	workingDirectory string
 	mocker           internal.Mocker
+	maxBodySize      int64
+	accessLog        bool
+	storageMaxSize   int64
+	storageInterval  time.Duration
+	reaperInterval   time.Duration
+	rateLimiter      *rateLimiter
+	metrics          *metrics
+	history          *history
+	autoClean        *autoClean
+	namespacePath    string
+	namespaces       *sync.Map
+	proxyTarget      *url.URL
+	notFoundMockId   string
+	disableDelay     bool
+	bodies           *bodyCache
+	filesDirectory   string
+	adminToken       string
+	signingSecret    []byte
+	config           *serverConfig
+	lifecycle        *lifecycle
 
 	logger logsutil.Logger
 }
 
+// lifecycle keeps the running {http.Server} and the cancel function of its base context to shut it down
+type lifecycle struct {
+	mu     sync.Mutex
+	server *http.Server
+	cancel context.CancelFunc
+}
+
+func (l *lifecycle) set(server *http.Server, cancel context.CancelFunc) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	l.server = server
+	l.cancel = cancel
+}
+
+func (l *lifecycle) get() (*http.Server, context.CancelFunc) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	return l.server, l.cancel
+}
+
+// defaultMaxBodySize is the max size (in bytes) of the body accepted to create a new mocked request
+const defaultMaxBodySize int64 = 10 << 20
+
+// maxImportFactor is the max size of a JSON lines import in number of max body sizes (100MB by default)
+const maxImportFactor = 10
+
+// defaultReaperInterval is the interval between two removals of the expired mocked requests
+const defaultReaperInterval = time.Minute
+
+// recordTimeout is the max duration of the outbound request to record a response
+const recordTimeout = 10 * time.Second
+
+// Option configures the {HTTPServer} struct
+type Option func(*HTTPServer)
+
+// WithMaxBodySize overrides the max size (in bytes) of the body accepted to create a new mocked request
+func WithMaxBodySize(maxBodySize int64) Option {
+	return func(s *HTTPServer) {
+		if maxBodySize > 0 {
+			s.maxBodySize = maxBodySize
+		}
+	}
+}
+
+// WithMaxDelay overrides the max delay of the served mocked requests (see "delay" parameter)
+func WithMaxDelay(maxDelay time.Duration) Option {
+	return func(s *HTTPServer) {
+		if maxDelay > 0 {
+			s.config.setMaxDelay(maxDelay)
+		}
+	}
+}
+
+// WithoutDelay disables all the delays of the served mocked requests (delay, delay per KB, chunk delay, bandwidth and frame delay)
+// if {disable} is true, e.g. to speed up the unit tests which use mocked requests with stored delays
+func WithoutDelay(disable bool) Option {
+	return func(s *HTTPServer) {
+		s.disableDelay = disable
+	}
+}
+
+// route represents a handler of a specific method
+type route struct {
+	method string
+	handle func(w http.ResponseWriter, r *http.Request)
+}
+
 type MockedRequestLightWithLinks struct {
 	internal.MockedRequestLight
 	Links map[string]string `json:"_links,omitempty"`
 }
 
+// WithAccessLog enables (or disables) the access log of each handled request
+func WithAccessLog(enabled bool) Option {
+	return func(s *HTTPServer) {
+		s.accessLog = enabled
+	}
+}
+
+// WithStorageQuota removes periodically (every {interval}) the oldest mocked requests
+// while the total size of the storage exceeds {maxBytes}
+func WithStorageQuota(maxBytes int64, interval time.Duration) Option {
+	return func(s *HTTPServer) {
+		if maxBytes > 0 && interval > 0 {
+			s.storageMaxSize = maxBytes
+			s.storageInterval = interval
+		}
+	}
+}
+
+// WithReaperInterval overrides the interval between two removals of the expired mocked requests (see "ttl" parameter)
+func WithReaperInterval(interval time.Duration) Option {
+	return func(s *HTTPServer) {
+		if interval > 0 {
+			s.reaperInterval = interval
+		}
+	}
+}
+
+// WithNotFoundMock serves the mocked request {mockId} (with a 404 status) instead of an empty 404
+// when the requested (or matched) mocked request does not exist
+func WithNotFoundMock(mockId string) Option {
+	return func(s *HTTPServer) {
+		s.notFoundMockId = mockId
+	}
+}
+
+// WithRateLimit limits the served mocked requests to {rate} requests per second (with a {burst}) by client address
+func WithRateLimit(rate float64, burst int) Option {
+	return func(s *HTTPServer) {
+		if rate > 0 {
+			s.rateLimiter = newRateLimiter(rate, burst)
+		}
+	}
+}
+
+// WithMetrics exposes the metrics of the served mocked requests on "/metrics" (Prometheus text format)
+func WithMetrics(enabled bool) Option {
+	return func(s *HTTPServer) {
+		if enabled {
+			s.metrics = newMetrics()
+		} else {
+			s.metrics = nil
+		}
+	}
+}
+
 // NewHTTPServer creates and initializes a {HTTPServer} struct
 func NewHTTPServer(
-	port string, ssl bool, certDirectory, workingDirectory string, mocker internal.Mocker, logger logsutil.Logger) *HTTPServer {
+	port string, ssl bool, certDirectory, workingDirectory string, mocker internal.Mocker, logger logsutil.Logger, opts ...Option) *HTTPServer {
 
-	return &HTTPServer{
+	s := &HTTPServer{
 		Port:             port,
 		mocker:           mocker,
 		SSLEnabled:       ssl,
 		certDirectory:    certDirectory,
 		workingDirectory: workingDirectory,
+		maxBodySize:      defaultMaxBodySize,
+		reaperInterval:   defaultReaperInterval,
+		config:           &serverConfig{maxDelay: defaultMaxDelay},
+		bodies:           newBodyCache(defaultBodyURLTTL),
+		lifecycle:        &lifecycle{},
+		namespaces:       &sync.Map{},
 		logger:           logger.Namespace("server"),
 	}
+	for _, opt := range opts {
+		opt(s)
+	}
+	return s
 }
 
-// Listen creates the http server and dispatches the incoming requests
+// Listen creates the http server and dispatches the incoming requests,
+// it returns nil once the server is stopped by {HTTPServer.Shutdown}
 func (s HTTPServer) Listen() error {
+	if s.storageMaxSize > 0 {
+		done := make(chan struct{})
+		defer close(done)
+		go s.cleanBySize(done)
+	}
+
+	reaperDone := make(chan struct{})
+	defer close(reaperDone)
+	go s.cleanExpired(reaperDone)
+
+	// the requests context is canceled to abort the delayed responses if the shutdown deadline is exceeded
+	ctx, cancel := context.WithCancel(context.Background())
+	server := &http.Server{
+		Addr:        ":" + s.Port,
+		Handler:     s.handler(),
+		BaseContext: func(net.Listener) context.Context { return ctx },
+	}
+	s.lifecycle.set(server, cancel)
+
+	var err error
+	if s.SSLEnabled && s.selfSignedCert {
+		cert, certErr := newSelfSignedCert()
+		if certErr != nil {
+			cancel()
+			return certErr
+		}
+		server.TLSConfig = &tls.Config{Certificates: []tls.Certificate{cert}}
+		err = server.ListenAndServeTLS("", "")
+	} else if s.SSLEnabled {
+		err = server.ListenAndServeTLS(
+			s.certDirectory+"/"+internal.MOCKAPIC_CERT_FILENAME,
+			s.certDirectory+"/"+internal.MOCKAPIC_PEM_FILENAME,
+		)
+	} else {
+		err = server.ListenAndServe()
+	}
+
+	if errors.Is(err, http.ErrServerClosed) {
+		return nil
+	}
+	cancel()
+	return err
+}
+
+// Shutdown stops accepting new connections and waits for the in-flight requests (including the delayed responses)
+// until the {ctx} is done, the remaining requests are then aborted and the {ctx} error is returned
+func (s HTTPServer) Shutdown(ctx context.Context) error {
+	server, cancel := s.lifecycle.get()
+	if server == nil {
+		return nil
+	}
+	defer cancel()
+
+	if err := server.Shutdown(ctx); err != nil {
+		cancel()
+		server.Close()
+		return err
+	}
+	return nil
+}
+
+// Handler returns the router of the server to mount it in another http server,
+// the periodic cleanings of the storage are only run by {HTTPServer.Listen}
+func (s HTTPServer) Handler() http.Handler {
+	return s.handler()
+}
+
+// handler creates the router which dispatches the incoming requests to the endpoints
+func (s HTTPServer) handler() http.Handler {
+	var handler http.Handler = s.routes()
+	if s.history != nil {
+		handler = s.recordHistory(handler)
+	}
+	if s.accessLog {
+		handler = s.logAccess(handler)
+	}
+	return handler
+}
+
+// routes registers the endpoints on a new router
+func (s HTTPServer) routes() *http.ServeMux {
 	server := http.NewServeMux()
 
 	handleFunc := func(method, pattern string, handle func(w http.ResponseWriter, r *http.Request)) {
 		server.HandleFunc(pattern, func(w http.ResponseWriter, r *http.Request) {
-			remoteAddr := s.findRemoteAddr(r.RemoteAddr)
-			s.logger.Info("request", "uri", r.RequestURI, "method", r.Method, "remoteAddr", remoteAddr)
+			s.logRequest(r)
 
 			if r.Method != method {
-				w.WriteHeader(404)
+				writeMethodNotAllowed(w, r, method)
 				return
 			}
 			handle(w, r)
 		})
 	}
 
-	handleFunc("GET", "/", s.home)
+	server.HandleFunc("/", func(w http.ResponseWriter, r *http.Request) {
+		s.logRequest(r)
+
+		s.root(w, r)
+	})
+
+	handleFunc("GET", "/healthz", s.healthz)
+	handleFunc("GET", "/version", s.version)
+	handleFunc("GET", "/readyz", s.readyz)
+	if s.metrics != nil {
+		handleFunc("GET", "/metrics", s.getMetrics)
+	}
 
 	handleFunc("GET", "/static/content-types", s.getContentTypes)
 	handleFunc("GET", "/static/charsets", s.getCharsets)
 	handleFunc("GET", "/static/status-codes", s.getStatusCodes)
+	handleFunc("GET", "/static/all", s.getStatics)
+
+	server.HandleFunc("/v1/", func(w http.ResponseWriter, r *http.Request) {
+		s.logRequest(r)
 
-	handleFunc("GET", "/v1/", s.getMockedRequest)
-	handleFunc("GET", "/v1/raw/", s.getMockedRequestRaw)
+		s.dispatchMockedRequest(w, r)
+	})
+	server.HandleFunc("/v1/echo", func(w http.ResponseWriter, r *http.Request) {
+		s.logRequest(r)
+
+		s.echo(w, r)
+	})
+	handleFunc("GET", "/v1/raw/", s.requireSignature(s.getMockedRequestRaw))
+	handleFunc("GET", "/v1/ws/", s.requireSignature(s.serveWebSocket))
 	handleFunc("GET", "/v1/list", s.list)
+	handleFunc("GET", "/v1/count", s.count)
+	handleFunc("GET", "/v1/facets", s.facets)
+	handleFunc("GET", "/v1/collections", s.collections)
+	if s.filesDirectory != "" {
+		handleFunc("GET", "/v1/files/", s.serveFile)
+	}
+	handleFunc("DELETE", "/v1/collections/", s.deleteCollection)
+	handleFunc("GET", "/v1/search", s.search)
 	handleFunc("POST", "/v1/new", s.addNewMock)
+	handleFunc("POST", "/v1/validate", s.validateMock)
+	handleFunc("POST", "/v1/delete-batch", s.deleteBatch)
+	handleFunc("DELETE", "/v1/all", s.deleteAll)
+	handleFunc("POST", "/v1/record", s.record)
+	handleFunc("POST", "/v1/import/openapi", s.importOpenAPI)
+	handleFunc("GET", "/v1/export/postman", s.requireAdminIfSigned(s.exportPostman))
+	handleFunc("GET", "/v1/export/jsonl", s.requireAdminIfSigned(s.exportJSONL))
+	handleFunc("POST", "/v1/import/jsonl", s.importJSONL)
+	handleFunc("GET", "/v1/config", s.getConfig)
+	if s.history != nil {
+		handleFunc("GET", "/v1/history", s.getHistory)
+	}
+	handleFunc("PUT", "/v1/config/max-delay", s.updateMaxDelay)
+	handleFunc("PUT", "/v1/config/failure-rate", s.updateFailureRate)
 
-	if s.SSLEnabled {
-		return http.ListenAndServeTLS(
-			":"+s.Port,
-			s.certDirectory+"/"+internal.MOCKAPIC_CERT_FILENAME,
-			s.certDirectory+"/"+internal.MOCKAPIC_PEM_FILENAME,
-			server,
-		)
-	} else {
-		return http.ListenAndServe(":"+s.Port, server)
+	// the namespaces cannot be nested
+	if s.namespacePath == "" {
+		server.HandleFunc("/v1/ns/", s.dispatchNamespace)
+	}
+
+	if s.adminToken != "" {
+		handleFunc("POST", "/admin/shutdown", s.shutdown)
+		handleFunc("POST", "/admin/reload", s.reload)
+	}
+
+	return server
+}
+
+// dispatchNamespace serves the "/v1/ns/{namespace}/..." requests as the "/v1/..." requests
+// against the mocked requests of the {namespace} (created on demand), the routes of a namespace are built once
+func (s HTTPServer) dispatchNamespace(w http.ResponseWriter, r *http.Request) {
+	namespace, path, _ := strings.Cut(strings.TrimPrefix(r.URL.Path, "/v1/ns/"), "/")
+
+	handler, is := s.namespaces.Load(namespace)
+	if !is {
+		mocker, err := s.mocker.ForNamespace(namespace)
+		if err != nil {
+			s.logRequest(r)
+			writeError(w, err, 400)
+			return
+		}
+
+		scoped := s
+		scoped.mocker = mocker
+		scoped.namespacePath = "/ns/" + namespace
+		handler, _ = s.namespaces.LoadOrStore(namespace, scoped.routes())
+	}
+
+	req := r.Clone(r.Context())
+	req.URL.Path = "/v1/" + path
+	req.URL.RawPath = ""
+	req.RequestURI = req.URL.RequestURI()
+
+	handler.(http.Handler).ServeHTTP(w, req)
+}
+
+// cleanExpired removes periodically the expired mocked requests until {done} is closed
+func (s HTTPServer) cleanExpired(done chan struct{}) {
+	ticker := time.NewTicker(s.reaperInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-done:
+			return
+		case <-ticker.C:
+			nb, err := s.mocker.CleanExpired()
+			if err != nil {
+				s.logger.Error(err, "error to clean the expired mocked requests")
+			} else if nb > 0 {
+				s.logger.Info("expired mocked requests cleaned", "nb", nb)
+			}
+		}
+	}
+}
+
+// cleanBySize applies the storage quota on each tick until {done} is closed
+func (s HTTPServer) cleanBySize(done chan struct{}) {
+	ticker := time.NewTicker(s.storageInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-done:
+			return
+		case <-ticker.C:
+			nb, err := s.mocker.CleanBySize(s.storageMaxSize)
+			if err != nil {
+				s.logger.Error(err, "error to clean the storage", "maxBytes", s.storageMaxSize)
+			} else if nb > 0 {
+				s.logger.Info("storage cleaned", "maxBytes", s.storageMaxSize, "nb", nb)
+			}
+		}
+	}
+}
+
+func (s HTTPServer) logRequest(r *http.Request) {
+	remoteAddr := s.findRemoteAddr(r.RemoteAddr)
+	s.logger.Info("request", "uri", r.RequestURI, "method", r.Method, "remoteAddr", remoteAddr)
+}
+
+// root serves the home page on "/" and falls through to the request matching for any other unknown path
+func (s HTTPServer) root(w http.ResponseWriter, r *http.Request) {
+	if r.URL.Path != "/" {
+		s.measure(s.limitRate(s.injectFailure(s.matchMockedRequest)))(w, r)
+		return
+	}
+
+	if r.Method != "GET" {
+		writeMethodNotAllowed(w, r, "GET")
+		return
 	}
+	s.home(w, r)
 }
 
 func (s HTTPServer) home(w http.ResponseWriter, r *http.Request) {
@@ -114,19 +493,60 @@ func (s HTTPServer) home(w http.ResponseWriter, r *http.Request) {
 		t.AppendSeparator()
 		t.AppendRows([]table.Row{
 			{"GET", "/", "Get info"},
+			{"GET", "/healthz", "Check the server is alive"},
+			{"GET", "/version", "Get the version of the server"},
+			{"GET", "/readyz", "Check the server is ready"},
+			{"GET", "/metrics", "Get the metrics of the served mocked requests (if enabled)"},
 		})
 		t.AppendSeparator()
 		t.AppendRows([]table.Row{
 			{"GET", "/static/content-types", "Get allowed content types"},
 			{"GET", "/static/charsets", "Get allowed charsets"},
 			{"GET", "/static/status-codes", "Get allowed status codes"},
+			{"GET", "/static/all", "Get allowed content types, charsets and status codes"},
 		})
 		t.AppendSeparator()
 		t.AppendRows([]table.Row{
 			{"GET", "/v1/{id}", "Get a mocked request"},
+			{"GET", "/v1/{id}/stats", "Get the stats of a mocked request"},
+			{"GET", "/v1/{id}/curl", "Get the curl command to call a mocked request"},
+			{"GET", "/v1/{id}/sign", "Get a signed URL of a mocked request (if enabled)"},
+			{"POST", "/v1/{id}/reset", "Reset the sequence of a mocked request"},
+			{"POST", "/v1/{id}/clone", "Clone a mocked request"},
+			{"GET", "/v1/{id}/raw", "Get the stored file of a mocked request"},
 			{"GET", "/v1/raw/{id}", "Get a raw mocked request"},
+			{"GET", "/v1/ws/{id}", "Replay the frames of a mocked request on a websocket"},
 			{"GET", "/v1/list", "Get the list of all mocked requests"},
+			{"GET", "/v1/count", "Get the number of mocked requests"},
+			{"GET", "/v1/facets", "Get the content types and statuses in use"},
+			{"GET", "/v1/collections", "Get the collections with their number of mocked requests"},
+			{"*", "/v1/echo", "Get the incoming request (method, path, query, headers and body) as JSON"},
+			{"GET", "/v1/files/{name}", "Get a file of the files directory (if enabled)"},
+			{"DELETE", "/v1/collections/{name}", "Delete the mocked requests of a collection"},
+			{"GET", "/v1/search", "Search the mocked requests"},
 			{"POST", "/v1/add", "Create a new mocked request"},
+			{"POST", "/v1/validate", "Validate a new mocked request without creating it"},
+			{"POST", "/v1/delete-batch", "Delete a list of mocked requests"},
+			{"DELETE", "/v1/all?confirm=true", "Delete all the mocked requests"},
+			{"POST", "/v1/record?url={url}", "Create a new mocked request from a live response"},
+			{"POST", "/v1/import/openapi", "Create the mocked requests from an OpenAPI spec"},
+			{"*", "/v1/ns/{namespace}/*", "Call the /v1/* APIs on the mocked requests of a namespace"},
+			{"GET", "/v1/export/postman", "Export the mocked requests as a Postman collection"},
+			{"GET", "/v1/export/jsonl", "Export the mocked requests as JSON lines"},
+			{"POST", "/v1/import/jsonl", "Create the mocked requests from JSON lines"},
+			{"GET", "/v1/config", "Get the config of the server"},
+			{"PUT", "/v1/config/max-delay", "Update the max delay of the served mocked requests"},
+			{"PUT", "/v1/config/failure-rate", "Update the global failure rate of the served mocked requests"},
+			{"GET", "/v1/history?limit={limit}", "Get the last handled requests (if enabled)"},
+		})
+		t.AppendSeparator()
+		t.AppendRows([]table.Row{
+			{"POST", "/admin/shutdown", "Shut down the server gracefully (if an admin token is defined)"},
+			{"POST", "/admin/reload", "Reload the mocked requests from the storage (if an admin token is defined)"},
+		})
+		t.AppendSeparator()
+		t.AppendRows([]table.Row{
+			{"*", "/*", "Get the mocked request matching the request"},
 		})
 
 		return t.Render()
@@ -183,7 +603,52 @@ func (s HTTPServer) home(w http.ResponseWriter, r *http.Request) {
 		buildAPITable())))
 }
 
+func (s HTTPServer) healthz(w http.ResponseWriter, r *http.Request) {
+	s.writeResponse(w, r, map[string]string{"status": "ok"})
+}
+
+// version writes the build info of the server (see {internal.BuildInfo})
+func (s HTTPServer) version(w http.ResponseWriter, r *http.Request) {
+	s.writeResponse(w, r, internal.BuildInfo())
+}
+
+func (s HTTPServer) readyz(w http.ResponseWriter, r *http.Request) {
+	if err := checkWritable(s.workingDirectory); err != nil {
+		s.logger.Error(err, "error to check the working directory", "workingDirectory", s.workingDirectory)
+		writeError(w, fmt.Errorf("working directory {%s} is not ready", s.workingDirectory), 503)
+		return
+	}
+	s.writeResponse(w, r, map[string]string{"status": "ok"})
+}
+
+// checkWritable returns an error if the {directory} cannot be read or written
+func checkWritable(directory string) error {
+	info, err := os.Stat(directory)
+	if err != nil {
+		return err
+	}
+	if !info.IsDir() {
+		return fmt.Errorf("{%s} is not a directory", directory)
+	}
+
+	file, err := os.CreateTemp(directory, ".readyz-*")
+	if err != nil {
+		return err
+	}
+	defer os.Remove(file.Name())
+
+	if _, err := file.Write([]byte("ok")); err != nil {
+		file.Close()
+		return err
+	}
+	return file.Close()
+}
+
 func (s HTTPServer) getContentTypes(w http.ResponseWriter, r *http.Request) {
+	if stringsutil.Bool(r.URL.Query().Get("display")) {
+		s.writeResponse(w, r, pkg.IS_DISPLAY_CONTENT)
+		return
+	}
 	s.writeResponse(w, r, pkg.CONTENT_TYPES)
 }
 
@@ -195,35 +660,266 @@ func (s HTTPServer) getStatusCodes(w http.ResponseWriter, r *http.Request) {
 	s.writeResponse(w, r, pkg.HTTP_CODES)
 }
 
-func (s HTTPServer) findMockedRequest(r *http.Request) (*internal.MockedRequest, int, error) {
+func (s HTTPServer) getStatics(w http.ResponseWriter, r *http.Request) {
+	s.writeResponse(w, r, pkg.ALL_STATICS)
+}
+
+// dispatchMockedRequest dispatches the "/v1/{id}" and "/v1/{id}/{action}" requests to the right handler
+func (s HTTPServer) dispatchMockedRequest(w http.ResponseWriter, r *http.Request) {
+	routes := map[string]route{
+		"":      {"", s.measure(s.limitRate(s.requireSignature(s.injectFailure(s.getMockedRequest))))},
+		"stats": {"GET", s.getMockedRequestStats},
+		"curl":  {"GET", s.getMockedRequestCurl},
+		"reset": {"POST", s.resetMockedRequest},
+		"clone": {"POST", s.cloneMockedRequest},
+		"raw":   {"GET", s.requireSignature(s.getMockedRequestStored)},
+	}
+	if len(s.signingSecret) > 0 {
+		routes["sign"] = route{"GET", s.signMockedRequest}
+	}
+
+	mockId, action, _ := strings.Cut(strings.TrimPrefix(r.URL.Path, "/v1/"), "/")
+	route, is := routes[action]
+	if !is {
+		w.WriteHeader(404)
+		return
+	}
+	// the method of the mocked request itself is checked by its handler
+	if route.method != "" && !isMethodAllowed(r.Method, route.method) {
+		writeMethodNotAllowed(w, r, route.method)
+		return
+	}
+
+	r.SetPathValue("id", mockId)
+	route.handle(w, r)
+}
+
+// isMethodAllowed returns true if the {method} can be served by the {allowed} one,
+// the "HEAD" requests are served as the "GET" requests without body
+func isMethodAllowed(method, allowed string) bool {
+	return method == allowed || (allowed == http.MethodGet && method == http.MethodHead)
+}
+
+func (s HTTPServer) findMockedRequest(
+	r *http.Request, find func(mockId string) (*internal.MockedRequest, error)) (*internal.MockedRequest, int, error) {
+
 	url, err := url.ParseRequestURI(r.RequestURI)
 	if err != nil {
 		s.logger.Error(err, "error to parse URI", "uri", r.RequestURI)
 		return nil, 409, err
 	}
 
-	mock, err := s.mocker.Get(path.Base(url.Path))
+	mock, err := find(stringsutil.OrElse(r.PathValue("id"), path.Base(url.Path)))
 	if err != nil {
 		s.logger.Error(err, "error to get mock", "uri", r.RequestURI)
+		var malformedIdError internal.MalformedIdError
+		if errors.As(err, &malformedIdError) {
+			return nil, 400, err
+		}
 		return nil, 404, err
 	}
 
 	return mock, -1, nil
 }
 
+// getMockedRequest serves the mocked request {id}, a "HEAD" request gets the same headers without any hit.
+// The request method must be the method of the mocked request ("GET" by default) otherwise a 405 is returned.
 func (s HTTPServer) getMockedRequest(w http.ResponseWriter, r *http.Request) {
-	mock, statusCode, err := s.findMockedRequest(r)
+	// the body can be read by the conditions of the mocked request
+	if r.Body != nil {
+		r.Body = http.MaxBytesReader(w, r.Body, s.maxBodySize)
+	}
+
+	mock, statusCode, err := s.findMockedRequest(r, func(mockId string) (*internal.MockedRequest, error) {
+		return s.mocker.HitMethod(mockId, r.Method)
+	})
+	var methodNotAllowedError internal.MethodNotAllowedError
+	if errors.As(err, &methodNotAllowedError) {
+		writeMethodNotAllowed(w, r, methodNotAllowedError.Allowed)
+		return
+	}
+	if err != nil && statusCode == 404 {
+		s.writeNotFound(w, r, err)
+		return
+	}
+	if err != nil {
+		writeError(w, err, statusCode)
+		return
+	}
+
+	setServedMockId(w, stringsutil.OrElse(r.PathValue("id"), mock.Id))
+	response := s.newResponse(w, r)
+	response.JSONPath = r.URL.Query().Get("jsonpath")
+	if err := response.Write(*mock, r.URL.Query().Get("delay")); err != nil {
+		s.logger.Error(err, "error to write mock", "uri", r.RequestURI)
+		writeError(w, err, 400)
+	}
+}
+
+func (s HTTPServer) matchMockedRequest(w http.ResponseWriter, r *http.Request) {
+	if r.Body != nil {
+		r.Body = http.MaxBytesReader(w, r.Body, s.maxBodySize)
+	}
+
+	mock, err := s.mocker.Match(r)
+	if err != nil && s.proxyTarget != nil {
+		s.proxy(w, r)
+		return
+	}
+	if err != nil {
+		s.logger.Error(err, "error to match mock", "uri", r.RequestURI, "method", r.Method)
+		s.writeNotFound(w, r, err)
+		return
+	}
+	// the method of the mocked request (if defined) is enforced as on "/v1/{id}"
+	if mock.Method != "" && mock.AllowMethod(r.Method) != nil {
+		writeMethodNotAllowed(w, r, mock.Method)
+		return
+	}
+	if len(s.signingSecret) > 0 {
+		if err := s.verifySignature(r, mock.Id); err != nil {
+			writeError(w, err, 403)
+			return
+		}
+	}
+
+	// the matched request is counted (and its sequence advanced) as on "/v1/{id}"
+	mock, err = s.mocker.Hit(mock.Id)
+	if err != nil {
+		s.logger.Error(err, "error to hit mock", "uri", r.RequestURI)
+		s.writeNotFound(w, r, err)
+		return
+	}
+
+	setServedMockId(w, mock.Id)
+	if err := s.newResponse(w, r).Write(*mock, r.URL.Query().Get("delay")); err != nil {
+		s.logger.Error(err, "error to write mock", "uri", r.RequestURI)
+		writeError(w, err, 400)
+	}
+}
+
+// newResponse creates the {Response} of a served mocked request with the delay settings of the server and of the request
+func (s HTTPServer) newResponse(w http.ResponseWriter, r *http.Request) Response {
+	response := NewResponse(w, r, s.config.getMaxDelay().String())
+	response.DelayPerKB = r.URL.Query().Get("delayPerKB")
+	response.NoDelay = s.noDelay(r)
+	response.Bodies = s.bodies
+	response.MaxBodySize = s.maxBodySize
+	return response
+}
+
+// noDelay returns true if the delays are disabled on the server (see {WithoutDelay}) or by the "nodelay=true" parameter
+func (s HTTPServer) noDelay(r *http.Request) bool {
+	return s.disableDelay || stringsutil.Bool(r.URL.Query().Get("nodelay"))
+}
+
+// writeNotFound writes the not found mocked request (see {WithNotFoundMock}) with a 404 status if it exists,
+// the {err} otherwise
+func (s HTTPServer) writeNotFound(w http.ResponseWriter, r *http.Request, err error) {
+	if s.notFoundMockId != "" {
+		if mock, getErr := s.mocker.Get(s.notFoundMockId); getErr == nil {
+			mock.Status = 404
+			setServedMockId(w, mock.Id)
+			if writeErr := s.newResponse(w, r).Write(*mock, ""); writeErr == nil {
+				return
+			}
+		}
+	}
+	writeError(w, err, 404)
+}
+
+func (s HTTPServer) getMockedRequestStats(w http.ResponseWriter, r *http.Request) {
+	mock, statusCode, err := s.findMockedRequest(r, s.mocker.Get)
+	if err != nil {
+		writeError(w, err, statusCode)
+		return
+	}
+
+	s.writeResponse(w, r, map[string]interface{}{
+		"uuid":           mock.Id,
+		"hits":           mock.Hits,
+		"lastAccessedAt": mock.LastAccessedAt,
+	})
+}
+
+// getMockedRequestCurl writes the curl command which calls the mocked request {id} on the host of the request,
+// followed by a comment with the expected status and content type
+func (s HTTPServer) getMockedRequestCurl(w http.ResponseWriter, r *http.Request) {
+	mock, statusCode, err := s.findMockedRequest(r, s.mocker.Get)
 	if err != nil {
 		writeError(w, err, statusCode)
 		return
 	}
 
-	fmt.Printf("mock request: %s\n", mock.Id)
-	NewResponse(w, "60s").Write(*mock, r.URL.Query().Get("delay"))
+	target := url.URL{
+		Scheme: s.getProtocol(r),
+		Host:   r.Host,
+		Path:   "/v1" + s.namespacePath + "/" + mock.Id,
+	}
+
+	w.Header().Set("Content-Type", "text/plain")
+	fmt.Fprintf(w, "curl -i -X GET %s\n# expected: %d %s (%s)\n",
+		shellQuote(target.String()), mock.Status, http.StatusText(mock.Status), stringsutil.OrElse(mock.ContentType, "no content type"))
+}
+
+// shellQuote quotes the {value} as a single shell argument
+func shellQuote(value string) string {
+	return "'" + strings.ReplaceAll(value, "'", `'\''`) + "'"
+}
+
+func (s HTTPServer) resetMockedRequest(w http.ResponseWriter, r *http.Request) {
+	mockId := r.PathValue("id")
+	if err := s.mocker.Reset(mockId); err != nil {
+		s.logger.Error(err, "error to reset mock", "uri", r.RequestURI)
+		writeError(w, err, 404)
+		return
+	}
+
+	w.WriteHeader(204)
+}
+
+// cloneMockedRequest creates a copy of the mocked request {id} with the query parameters as overrides
+func (s HTTPServer) cloneMockedRequest(w http.ResponseWriter, r *http.Request) {
+	mock, statusCode, err := s.findMockedRequest(r, s.mocker.Get)
+	if err != nil {
+		writeError(w, err, statusCode)
+		return
+	}
+
+	id, err := s.mocker.Clone(mock.Id, r.URL.Query())
+	if err != nil {
+		s.logger.Error(err, "error to clone mock", "uri", r.RequestURI)
+		writeError(w, err, 409)
+		return
+	}
+
+	if internal.MOCKAPIC_REQ_MAX_LIMIT > 0 {
+		s.mocker.Clean(internal.MOCKAPIC_REQ_MAX_LIMIT)
+	}
+
+	s.writeResponse(w, r, map[string]interface{}{"id": *id, "_links": s.getLinks(r, *id)})
+}
+
+// getMockedRequestStored writes the stored file of the mocked request {id} as is (no delay, charset or body processing)
+func (s HTTPServer) getMockedRequestStored(w http.ResponseWriter, r *http.Request) {
+	data, err := s.mocker.Stored(r.PathValue("id"))
+	if err != nil {
+		s.logger.Error(err, "error to get stored mock", "uri", r.RequestURI)
+		var malformedIdError internal.MalformedIdError
+		if errors.As(err, &malformedIdError) {
+			writeError(w, err, 400)
+			return
+		}
+		writeError(w, err, 404)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.Write(data)
 }
 
 func (s HTTPServer) getMockedRequestRaw(w http.ResponseWriter, r *http.Request) {
-	mock, statusCode, err := s.findMockedRequest(r)
+	mock, statusCode, err := s.findMockedRequest(r, s.mocker.Get)
 	if err != nil {
 		writeError(w, err, statusCode)
 		return
@@ -236,30 +932,269 @@ func (s HTTPServer) getMockedRequestRaw(w http.ResponseWriter, r *http.Request)
 	s.writeResponse(w, r, mock)
 }
 
-func (s HTTPServer) addNewMock(w http.ResponseWriter, r *http.Request) {
-	body, err := io.ReadAll(r.Body)
+// readBody reads the request body limited to the max body size, the error is written if it cannot be read
+func (s HTTPServer) readBody(w http.ResponseWriter, r *http.Request) ([]byte, bool) {
+	body, err := io.ReadAll(http.MaxBytesReader(w, r.Body, s.maxBodySize))
 	if err != nil {
+		var maxBytesError *http.MaxBytesError
+		if errors.As(err, &maxBytesError) {
+			writeError(w, fmt.Errorf("body exceeds the max size of {%d} bytes", maxBytesError.Limit), 413)
+			return nil, false
+		}
 		s.logger.Error(err, "error to read body", "uri", r.RequestURI)
 		writeError(w, err, 500)
+		return nil, false
+	}
+	return body, true
+}
+
+// newMockParams returns the query parameters of the request to create a mocked request,
+// completed by the "X-Created-By" and "X-Tags" headers
+func newMockParams(r *http.Request) url.Values {
+	reqParams := r.URL.Query()
+	reqParams.Del("return")
+	for param, header := range map[string]string{"createdBy": "X-Created-By", "tags": "X-Tags"} {
+		if value := r.Header.Get(header); value != "" && !reqParams.Has(param) {
+			reqParams.Set(param, value)
+		}
+	}
+	return reqParams
+}
+
+// newMockStatusCode returns the status code of the {err} returned by the creation of a new mocked request
+func newMockStatusCode(err error) int {
+	var invalidBodyError internal.InvalidBodyError
+	var duplicateIdError internal.DuplicateIdError
+	var invalidHeaderError internal.InvalidHeaderError
+	if errors.As(err, &invalidBodyError) || errors.As(err, &duplicateIdError) || errors.As(err, &invalidHeaderError) {
+		return 409
+	}
+	var malformedIdError internal.MalformedIdError
+	if errors.As(err, &malformedIdError) {
+		return 400
+	}
+	var readOnlyError internal.ReadOnlyError
+	if errors.As(err, &readOnlyError) {
+		return 403
+	}
+	return 500
+}
+
+func (s HTTPServer) addNewMock(w http.ResponseWriter, r *http.Request) {
+	reqParams, body, ok := s.readNewMock(w, r)
+	if !ok {
 		return
 	}
 
-	id, err := s.mocker.New(r.URL.Query(), body)
+	id, err := s.mocker.New(reqParams, body)
 	if err != nil {
 		s.logger.Error(err, "error to create new mock", "uri", r.RequestURI, "body", body)
+		writeError(w, err, newMockStatusCode(err))
+		return
+	}
+
+	if internal.MOCKAPIC_REQ_MAX_LIMIT > 0 {
+		s.mocker.Clean(internal.MOCKAPIC_REQ_MAX_LIMIT)
+	}
+
+	s.countCreated()
+	s.countRemoteAddr(r.RemoteAddr)
+
+	data := map[string]interface{}{"id": *id, "_links": s.getLinks(r, *id)}
+	if r.URL.Query().Get("return") == "full" {
+		if mock, err := s.mocker.Get(*id); err == nil {
+			if slicesutil.Exist(pkg.IS_DISPLAY_CONTENT, mock.ContentType) {
+				mock.Body = string(mock.Body64)
+			}
+			data["mock"] = mock
+		}
+	}
+
+	s.writeResponse(w, r, data)
+}
+
+// validateMock runs the checks of a new mocked request (see {addNewMock}) without storing it
+func (s HTTPServer) validateMock(w http.ResponseWriter, r *http.Request) {
+	reqParams, body, ok := s.readNewMock(w, r)
+	if !ok {
+		return
+	}
+
+	if err := s.mocker.Validate(reqParams, body); err != nil {
+		writeError(w, err, 409)
+		return
+	}
+
+	s.writeResponse(w, r, map[string]bool{"valid": true})
+}
+
+func (s HTTPServer) deleteBatch(w http.ResponseWriter, r *http.Request) {
+	body, ok := s.readBody(w, r)
+	if !ok {
+		return
+	}
+
+	mockIds, err := jsonsutil.Unmarshal[[]string](body)
+	if err != nil {
+		writeError(w, errors.New("body must be a JSON array of ids"), 400)
+		return
+	}
+
+	deleted, errs := s.mocker.DeleteMany(mockIds)
+	s.writeResponse(w, r, map[string]interface{}{"deleted": deleted, "errors": errs})
+}
+
+// deleteAll removes all the mocked requests (of the namespace), the "confirm=true" parameter is required
+func (s HTTPServer) deleteAll(w http.ResponseWriter, r *http.Request) {
+	if !stringsutil.Bool(r.URL.Query().Get("confirm")) {
+		writeError(w, errors.New("parameter {confirm=true} is required to delete all the mocked requests"), 400)
+		return
+	}
+
+	deleted, err := s.mocker.DeleteAll()
+	if err != nil {
+		s.logger.Error(err, "error to delete all mocked requests", "uri", r.RequestURI)
+		var readOnlyError internal.ReadOnlyError
+		if errors.As(err, &readOnlyError) {
+			writeError(w, err, 403)
+			return
+		}
 		writeError(w, err, 500)
 		return
 	}
+	s.writeResponse(w, r, map[string]int{"deleted": deleted})
+}
+
+func (s HTTPServer) record(w http.ResponseWriter, r *http.Request) {
+	target := r.URL.Query().Get("url")
+	if targetURL, err := url.Parse(target); err != nil ||
+		(targetURL.Scheme != "http" && targetURL.Scheme != "https") || targetURL.Host == "" {
+		writeError(w, fmt.Errorf("url {%s} must be an absolute http(s) URL", target), 400)
+		return
+	}
+
+	req, err := httpsutil.NewHttpRequest(target, "")
+	if err != nil {
+		writeError(w, err, 400)
+		return
+	}
+
+	// the private addresses are rejected as the body URLs (see {WithPrivateBodyURL})
+	resp, err := s.bodies.newClient(recordTimeout).Do(req.Req.WithContext(r.Context()))
+	if err != nil {
+		s.logger.Error(err, "error to record url", "uri", r.RequestURI, "url", target)
+		writeError(w, fmt.Errorf("url {%s} cannot be recorded", target), 502)
+		return
+	}
+	defer resp.Body.Close()
+	resp.Body = http.MaxBytesReader(nil, resp.Body, s.maxBodySize)
+
+	reqParams, body, err := internal.RecordParams(resp)
+	if err != nil {
+		s.logger.Error(err, "error to read recorded response", "uri", r.RequestURI, "url", target)
+		writeError(w, fmt.Errorf("url {%s} cannot be recorded", target), 502)
+		return
+	}
+
+	// the recorded response can be a status or a content which is not supported
+	if err := s.mocker.Validate(reqParams, body); err != nil {
+		writeError(w, err, 409)
+		return
+	}
+
+	id, err := s.mocker.New(reqParams, body)
+	if err != nil {
+		s.logger.Error(err, "error to create new mock", "uri", r.RequestURI, "url", target)
+		writeError(w, err, newMockStatusCode(err))
+		return
+	}
 
 	if internal.MOCKAPIC_REQ_MAX_LIMIT > 0 {
 		s.mocker.Clean(internal.MOCKAPIC_REQ_MAX_LIMIT)
 	}
 
+	s.countCreated()
 	s.countRemoteAddr(r.RemoteAddr)
 
 	s.writeResponse(w, r, map[string]interface{}{"id": *id, "_links": s.getLinks(r, *id)})
 }
 
+func (s HTTPServer) importOpenAPI(w http.ResponseWriter, r *http.Request) {
+	body, ok := s.readBody(w, r)
+	if !ok {
+		return
+	}
+
+	operations, err := internal.ImportOpenAPI(s.mocker, body)
+	if err != nil {
+		writeError(w, err, 400)
+		return
+	}
+
+	if internal.MOCKAPIC_REQ_MAX_LIMIT > 0 {
+		s.mocker.Clean(internal.MOCKAPIC_REQ_MAX_LIMIT)
+	}
+
+	s.countRemoteAddr(r.RemoteAddr)
+
+	s.writeResponse(w, r, operations)
+}
+
+func (s HTTPServer) exportPostman(w http.ResponseWriter, r *http.Request) {
+	data, err := internal.ExportPostman(s.mocker, s.getProtocol(r)+"://"+r.Host+"/v1"+s.namespacePath)
+	if err != nil {
+		s.logger.Error(err, "error to export mocked requests", "uri", r.RequestURI)
+		writeError(w, err, 500)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.Header().Set("Content-Disposition", `attachment; filename="mockapic.postman_collection.json"`)
+	w.WriteHeader(200)
+	w.Write(data)
+}
+
+func (s HTTPServer) exportJSONL(w http.ResponseWriter, r *http.Request) {
+	mockedRequestLights, err := s.mocker.List()
+	if err != nil {
+		s.logger.Error(err, "error to export mocked requests", "uri", r.RequestURI)
+		writeError(w, err, 500)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/x-ndjson")
+	w.Header().Set("Content-Disposition", `attachment; filename="mockapic.jsonl"`)
+	w.WriteHeader(200)
+
+	controller := http.NewResponseController(w)
+	if _, err := internal.ExportJSONL(s.mocker, mockedRequestLights, w, func() { controller.Flush() }); err != nil {
+		s.logger.Error(err, "error to export mocked requests", "uri", r.RequestURI)
+	}
+}
+
+func (s HTTPServer) importJSONL(w http.ResponseWriter, r *http.Request) {
+	// each line is limited (a mocked request with its base64 body) and the whole stream too
+	body := http.MaxBytesReader(w, r.Body, s.maxBodySize*maxImportFactor)
+	nb, err := internal.ImportJSONL(s.mocker, body, int(s.maxBodySize)*2)
+	if err != nil {
+		var maxBytesError *http.MaxBytesError
+		if errors.As(err, &maxBytesError) {
+			writeError(w, fmt.Errorf("body exceeds the max size of {%d} bytes", maxBytesError.Limit), 413)
+			return
+		}
+		writeError(w, err, 400)
+		return
+	}
+
+	if internal.MOCKAPIC_REQ_MAX_LIMIT > 0 {
+		s.mocker.Clean(internal.MOCKAPIC_REQ_MAX_LIMIT)
+	}
+
+	s.countRemoteAddr(r.RemoteAddr)
+
+	s.writeResponse(w, r, map[string]int{"created": nb})
+}
+
 func (s HTTPServer) countRemoteAddr(requestRemoteAddr string) {
 	remoteAddrHistory := s.getRemoteAddr()
 
@@ -313,8 +1248,8 @@ func (s HTTPServer) getRemoteAddr() map[string]int {
 
 func (s HTTPServer) getLinks(r *http.Request, mockedRequestId string) map[string]string {
 	return map[string]string{
-		"self": s.getProtocol(r) + "://" + r.Host + "/v1/" + mockedRequestId,
-		"raw":  s.getProtocol(r) + "://" + r.Host + "/v1/raw/" + mockedRequestId,
+		"self": s.getProtocol(r) + "://" + r.Host + "/v1" + s.namespacePath + "/" + mockedRequestId,
+		"raw":  s.getProtocol(r) + "://" + r.Host + "/v1" + s.namespacePath + "/raw/" + mockedRequestId,
 	}
 }
 
@@ -326,6 +1261,53 @@ func (s HTTPServer) list(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
+	s.writeResponse(w, r, s.withLinks(r, mockedRequestLights))
+}
+
+func (s HTTPServer) search(w http.ResponseWriter, r *http.Request) {
+	filter, err := parseSearchFilter(r.URL.Query())
+	if err != nil {
+		writeError(w, err, 400)
+		return
+	}
+
+	mockedRequestLights, err := s.mocker.Search(*filter)
+	if err != nil {
+		s.logger.Error(err, "error to search mocked requests", "uri", r.RequestURI)
+		writeError(w, err, 500)
+		return
+	}
+
+	s.writeResponse(w, r, s.withLinks(r, mockedRequestLights))
+}
+
+// parseSearchFilter builds the {internal.SearchFilter} from the query parameters
+func parseSearchFilter(query url.Values) (*internal.SearchFilter, error) {
+	filter := &internal.SearchFilter{ContentType: query.Get("contentType"), Tag: query.Get("tag"), Collection: query.Get("collection")}
+
+	if value := query.Get("status"); value != "" {
+		status, err := strconv.Atoi(value)
+		if err != nil {
+			return nil, fmt.Errorf("status {%s} is malformed", value)
+		}
+		filter.Status = status
+	}
+
+	if value := query.Get("since"); value != "" {
+		since, err := internal.ParseTime(value)
+		if err != nil {
+			if since, err = time.ParseInLocation("2006-01-02", value, time.Local); err != nil {
+				return nil, fmt.Errorf("since {%s} is malformed", value)
+			}
+		}
+		filter.Since = since
+	}
+
+	return filter, nil
+}
+
+// withLinks adds the links to each mocked request of {mockedRequestLights}
+func (s HTTPServer) withLinks(r *http.Request, mockedRequestLights []internal.MockedRequestLight) []MockedRequestLightWithLinks {
 	all := slicesutil.TransformT[internal.MockedRequestLight, MockedRequestLightWithLinks](mockedRequestLights, func(mrl internal.MockedRequestLight) (*MockedRequestLightWithLinks, error) {
 		return &MockedRequestLightWithLinks{
 			MockedRequestLight: mrl,
@@ -336,8 +1318,67 @@ func (s HTTPServer) list(w http.ResponseWriter, r *http.Request) {
 	if len(all) == 0 {
 		all = []MockedRequestLightWithLinks{}
 	}
+	return all
+}
 
-	s.writeResponse(w, r, all)
+func (s HTTPServer) count(w http.ResponseWriter, r *http.Request) {
+	nb, err := s.mocker.Count()
+	if err != nil {
+		s.logger.Error(err, "error to count mocked requests", "uri", r.RequestURI)
+		writeError(w, err, 500)
+		return
+	}
+
+	s.writeResponse(w, r, map[string]int{"count": nb})
+}
+
+// facets returns the distinct (and sorted) content types and statuses of the mocked requests
+func (s HTTPServer) facets(w http.ResponseWriter, r *http.Request) {
+	mockedRequestLights, err := s.mocker.List()
+	if err != nil {
+		s.logger.Error(err, "error to list mocked requests", "uri", r.RequestURI)
+		writeError(w, err, 500)
+		return
+	}
+
+	contentTypes := []string{}
+	statuses := []int{}
+	for _, mrl := range mockedRequestLights {
+		if mrl.ContentType != "" && !slices.Contains(contentTypes, mrl.ContentType) {
+			contentTypes = append(contentTypes, mrl.ContentType)
+		}
+		if mrl.Status != 0 && !slices.Contains(statuses, mrl.Status) {
+			statuses = append(statuses, mrl.Status)
+		}
+	}
+	slices.Sort(contentTypes)
+	slices.Sort(statuses)
+
+	s.writeResponse(w, r, map[string]any{"contentTypes": contentTypes, "statuses": statuses})
+}
+
+// collections writes the collections of the mocked requests with their number of mocked requests
+func (s HTTPServer) collections(w http.ResponseWriter, r *http.Request) {
+	collections, err := internal.Collections(s.mocker)
+	if err != nil {
+		s.logger.Error(err, "error to list collections", "uri", r.RequestURI)
+		writeError(w, err, 500)
+		return
+	}
+
+	s.writeResponse(w, r, map[string]any{"collections": collections})
+}
+
+// deleteCollection removes all the mocked requests of the collection "/v1/collections/{name}"
+func (s HTTPServer) deleteCollection(w http.ResponseWriter, r *http.Request) {
+	deleted, errs, err := internal.DeleteCollection(s.mocker, strings.TrimPrefix(r.URL.Path, "/v1/collections/"))
+	if err != nil {
+		s.logger.Error(err, "error to delete collection", "uri", r.RequestURI)
+		writeError(w, err, 400)
+		return
+	}
+
+	s.writeResponse(w, r, map[string]any{"deleted": deleted, "errors": errs})
 }
 
 func (s HTTPServer) writeResponse(w http.ResponseWriter, r *http.Request, data any) {
@@ -352,10 +1393,17 @@ func (s HTTPServer) writeResponse(w http.ResponseWriter, r *http.Request, data a
 	w.Write(bytes)
 }
 
+// writeMethodNotAllowed writes a 405 error with the {allowed} methods in the "Allow" header
+func writeMethodNotAllowed(w http.ResponseWriter, r *http.Request, allowed ...string) {
+	w.Header().Set("Allow", strings.Join(allowed, ", "))
+	writeError(w, fmt.Errorf("method {%s} is not allowed", r.Method), 405)
+}
+
 func writeError(w http.ResponseWriter, err error, statusCode int) {
 	w.Header().Set("Content-Type", "application/json")
 	w.WriteHeader(statusCode)
 	if statusCode != 404 {
-		w.Write([]byte(fmt.Sprintf(`{"message": "%s"}`, err.Error())))
+		message, _ := json.Marshal(err.Error())
+		w.Write([]byte(`{"message": ` + string(message) + `}`))
 	}
 }
@@ -1,21 +1,31 @@
 package server
 
 import (
+	"bytes"
+	"context"
+	"encoding/base64"
 	"errors"
+	"fmt"
 	"io"
 	"log"
 	"net/http"
 	"net/http/httptest"
+	"net/url"
 	"os"
 	"path/filepath"
+	"reflect"
+	"slices"
 	"strings"
 	"testing"
 	"time"
 
 	"github.com/joakim-ribier/go-utils/pkg/httpsutil"
 	"github.com/joakim-ribier/go-utils/pkg/iosutil"
+	"github.com/joakim-ribier/go-utils/pkg/jsonsutil"
 	"github.com/joakim-ribier/go-utils/pkg/logsutil"
+	"github.com/joakim-ribier/go-utils/pkg/slicesutil"
 	"github.com/joakim-ribier/go-utils/pkg/stringsutil"
+	"github.com/joakim-ribier/go-utils/pkg/timesutil"
 	"github.com/joakim-ribier/mockapic/internal"
 	"github.com/joakim-ribier/mockapic/pkg"
 )
@@ -24,6 +34,7 @@ type MockerTest struct {
 	mockResponse       *internal.MockedRequest
 	mockResponseLights []internal.MockedRequestLight
 	clean              bool
+	cleanBySize        chan int64
 }
 
 func (m *MockerTest) Get(mockId string) (*internal.MockedRequest, error) {
@@ -33,6 +44,34 @@ func (m *MockerTest) Get(mockId string) (*internal.MockedRequest, error) {
 	return nil, errors.New("mockId does not exist")
 }
 
+func (m *MockerTest) Stored(mockId string) ([]byte, error) {
+	if m.mockResponse != nil {
+		return jsonsutil.Marshal(m.mockResponse)
+	}
+	return nil, errors.New("mockId does not exist")
+}
+
+func (m *MockerTest) Hit(mockId string) (*internal.MockedRequest, error) {
+	if m.mockResponse != nil {
+		m.mockResponse.Hits = m.mockResponse.Hits + 1
+		return m.mockResponse, nil
+	}
+	return nil, errors.New("mockId does not exist")
+}
+
+func (m *MockerTest) HitMethod(mockId, method string) (*internal.MockedRequest, error) {
+	if m.mockResponse == nil {
+		return nil, errors.New("mockId does not exist")
+	}
+	if err := m.mockResponse.AllowMethod(method); err != nil {
+		return nil, err
+	}
+	if method == http.MethodHead {
+		return m.mockResponse, nil
+	}
+	return m.Hit(mockId)
+}
+
 func (m *MockerTest) List() ([]internal.MockedRequestLight, error) {
 	if m.mockResponseLights != nil {
 		return m.mockResponseLights, nil
@@ -62,11 +101,102 @@ func (m *MockerTest) New(reqParams map[string][]string, body []byte) (*string, e
 	return &r, nil
 }
 
+func (m *MockerTest) Clone(mockId string, reqParams map[string][]string) (*string, error) {
+	if m.mockResponse == nil {
+		return nil, errors.New("mockId does not exist")
+	}
+
+	mockedRequest := *m.mockResponse
+	if len(reqParams["status"]) > 0 {
+		mockedRequest.Status = stringsutil.Int(reqParams["status"][0], -1)
+	}
+
+	m.mockResponse = &mockedRequest
+	var r string = "{clone-id}"
+	return &r, nil
+}
+
+func (m *MockerTest) Import(mockedRequests []internal.MockedRequest) (int, error) {
+	return len(mockedRequests), nil
+}
+
+func (m *MockerTest) ForNamespace(namespace string) (internal.Mocker, error) {
+	return &MockerTest{}, nil
+}
+
+func (m *MockerTest) Validate(reqParams map[string][]string, body []byte) error {
+	return nil
+}
+
 func (m *MockerTest) Clean(maxLimit int) (int, error) {
 	m.clean = true
 	return 0, nil
 }
 
+func (m *MockerTest) DeleteMany(mockIds []string) (int, map[string]string) {
+	nb := 0
+	errs := map[string]string{}
+	for _, mockId := range mockIds {
+		if slicesutil.Exist(slicesutil.TransformT(m.mockResponseLights, func(mrl internal.MockedRequestLight) (*string, error) {
+			return &mrl.Id, nil
+		}), mockId) {
+			nb = nb + 1
+		} else {
+			errs[mockId] = "mocked request does not exist"
+		}
+	}
+	return nb, errs
+}
+
+func (m *MockerTest) DeleteAll() (int, error) {
+	return len(m.mockResponseLights), nil
+}
+
+func (m *MockerTest) CleanExpired() (int, error) {
+	return 0, nil
+}
+
+func (m *MockerTest) Reload() error {
+	return nil
+}
+
+func (m *MockerTest) CleanBySize(maxBytes int64) (int, error) {
+	select {
+	case m.cleanBySize <- maxBytes:
+	default:
+	}
+	return 0, nil
+}
+
+func (m *MockerTest) Reset(mockId string) error {
+	if m.mockResponse != nil {
+		m.mockResponse.SequenceIndex = 0
+		return nil
+	}
+	return errors.New("mockId does not exist")
+}
+
+func (m *MockerTest) Search(filter internal.SearchFilter) ([]internal.MockedRequestLight, error) {
+	if m.mockResponseLights != nil {
+		return slicesutil.FilterT(m.mockResponseLights, filter.Match), nil
+	}
+	return nil, errors.New("error to search mocked responses")
+}
+
+func (m *MockerTest) Count() (int, error) {
+	if m.mockResponseLights != nil {
+		return len(m.mockResponseLights), nil
+	}
+	return 0, errors.New("error to count mocked responses")
+}
+
+func (m *MockerTest) Match(req *http.Request) (*internal.MockedRequest, error) {
+	if m.mockResponse != nil && m.mockResponse.When != nil && m.mockResponse.When.Match(req) {
+		return m.mockResponse, nil
+	}
+	return nil, errors.New("no mocked request matches")
+}
+
 var workingDirectory string
 var logger *logsutil.Logger
 
@@ -106,12 +236,83 @@ func TestListen(t *testing.T) {
 		t.Fatalf(`result: {%v} but expected {%v}`, resp.StatusCode, 200)
 	}
 
-	// testing '404' if bad endpoint is called
+	// testing '405' if bad method is called
 	req, _ = httpsutil.NewHttpRequest("http://localhost:3334/", "")
 	resp, _ = req.Method("POST").Call()
-	if resp.StatusCode != 404 {
-		t.Fatalf(`result: {%v} but expected {%v}`, resp.StatusCode, 200)
+	if resp.StatusCode != 405 {
+		t.Fatalf(`result: {%v} but expected {%v}`, resp.StatusCode, 405)
+	}
+}
+
+// TestShutdown calls HTTPServer.Shutdown(context.Context),
+// checking for a valid return value.
+func TestShutdown(t *testing.T) {
+	mocker := &MockerTest{
+		mockResponse: &internal.MockedRequest{
+			MockedRequestLight: internal.MockedRequestLight{
+				MockedRequestHeader: internal.MockedRequestHeader{
+					Status:      200,
+					ContentType: "text/plain",
+					Charset:     "UTF-8",
+				},
+			},
+			Body64: []byte("Hello World"),
+		},
+	}
+
+	listen := func(port string) (*HTTPServer, chan error) {
+		httpServer := NewHTTPServer(port, false, "", workingDirectory, mocker, *logger)
+		done := make(chan error, 1)
+		go func() {
+			done <- httpServer.Listen()
+		}()
+		time.Sleep(100 * time.Millisecond)
+		return httpServer, done
+	}
+
+	call := func(uri string) chan *http.Response {
+		responses := make(chan *http.Response, 1)
+		go func() {
+			resp, _ := http.Get(uri)
+			responses <- resp
+		}()
+		time.Sleep(50 * time.Millisecond)
+		return responses
+	}
+
+	// test if the in-flight delayed response is drained
+	httpServer, done := listen("3336")
+	responses := call("http://localhost:3336/v1/mock-id?delay=300ms")
+
+	ctx, cancel := context.WithTimeout(context.Background(), 2*time.Second)
+	defer cancel()
+	if err := httpServer.Shutdown(ctx); err != nil {
+		t.Fatalf(`result: {%v} but expected {%v}`, err, nil)
+	}
+	if err := <-done; err != nil {
+		t.Fatalf(`result: {%v} but expected {%v}`, err, nil)
+	}
+	if resp := <-responses; resp == nil || resp.StatusCode != 200 {
+		t.Fatalf(`result: {%v} but expected {%v}`, resp, 200)
+	}
+
+	// test if the in-flight delayed response is aborted once the deadline is exceeded
+	httpServer, done = listen("3337")
+	responses = call("http://localhost:3337/v1/mock-id?delay=30s")
+
+	withTime, _ := timesutil.WithExecutionTime(func() (*string, error) {
+		ctx, cancel := context.WithTimeout(context.Background(), 200*time.Millisecond)
+		defer cancel()
+		if err := httpServer.Shutdown(ctx); !errors.Is(err, context.DeadlineExceeded) {
+			t.Errorf(`result: {%v} but expected {%v}`, err, context.DeadlineExceeded)
+		}
+		<-responses
+		return nil, nil
+	})
+	if withTime.TimeInMillis > 2000 {
+		t.Fatalf(`result: {%v} but expected {%v}`, withTime.TimeInMillis, "< 2000")
 	}
+	<-done
 }
 
 // TestListen calls HTTPServer.Listen(),
@@ -137,11 +338,11 @@ func TestListenSSL(t *testing.T) {
 		t.Fatalf(`result: {%v} but expected {%v}`, resp, 200)
 	}
 
-	// testing '404' if bad endpoint is called
+	// testing '405' if bad method is called
 	req, _ = httpsutil.NewHttpRequest("https://localhost:3333/", "")
 	resp, _ = req.Method("POST").InsecureSkipVerify().Call()
-	if resp.StatusCode != 404 {
-		t.Fatalf(`result: {%v} but expected {%v}`, resp.StatusCode, 200)
+	if resp.StatusCode != 405 {
+		t.Fatalf(`result: {%v} but expected {%v}`, resp.StatusCode, 405)
 	}
 }
 
@@ -163,6 +364,183 @@ func TestRootEndpoint(t *testing.T) {
 	}
 }
 
+// TestHandlerWithMethodNotAllowed calls HTTPServer.handler(),
+// checking for a valid return value.
+func TestHandlerWithMethodNotAllowed(t *testing.T) {
+	tests := []struct {
+		method string
+		url    string
+		allow  string
+	}{
+		{http.MethodPost, "/", "GET"},
+		{http.MethodGet, "/v1/new", "POST"},
+		{http.MethodPost, "/v1/list", "GET"},
+		{http.MethodDelete, "/static/charsets", "GET"},
+		{http.MethodPost, "/v1/{id}", "GET"},
+		{http.MethodGet, "/v1/{id}/reset", "POST"},
+	}
+
+	handler := NewHTTPServer("{port}", false, "", workingDirectory, &MockerTest{mockResponse: &internal.MockedRequest{}}, *logger).handler()
+	for _, test := range tests {
+		w := httptest.NewRecorder()
+		handler.ServeHTTP(w, httptest.NewRequest(test.method, "http://localhost:3333"+test.url, nil))
+
+		res, body := geResultResponse(w, t)
+		expected := `{"message": "method {` + test.method + `} is not allowed"}`
+		if res.Status != "405 Method Not Allowed" || res.Header.Get("Allow") != test.allow || string(body) != expected {
+			t.Fatalf(`%s %s - result: {%v} but expected {%v}`, test.method, test.url, res.Header.Get("Allow"), test.allow)
+		}
+	}
+
+	// test if the path does not exist
+	w := httptest.NewRecorder()
+	handler.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "http://localhost:3333/v1/{id}/unknown", nil))
+
+	if res, _ := geResultResponse(w, t); res.Status != "404 Not Found" {
+		t.Fatalf(`result: {%v} but expected {%v}`, res.Status, "404")
+	}
+}
+
+// ##
+// #### ~/* endpoint
+// ##
+
+// TestRootEndpointWithMatchedRequest calls HTTPServer.root(http.ResponseWriter, *http.Request),
+// checking for a valid return value.
+func TestRootEndpointWithMatchedRequest(t *testing.T) {
+	req := httptest.NewRequest(http.MethodPost, "http://localhost:3333/api/users", strings.NewReader("{}"))
+	w := httptest.NewRecorder()
+
+	mocker := &MockerTest{
+		mockResponse: &internal.MockedRequest{
+			MockedRequestLight: internal.MockedRequestLight{
+				MockedRequestHeader: internal.MockedRequestHeader{
+					Status:      201,
+					ContentType: "application/json",
+					Charset:     "UTF-8",
+				},
+			},
+			When:   &internal.Matcher{Method: "POST", Path: "/api/users"},
+			Body64: []byte(`{"id":1}`),
+		},
+	}
+
+	NewHTTPServer("{port}", false, "", workingDirectory, mocker, *logger).root(w, req)
+
+	res, body := geResultResponse(w, t)
+	if res.Status != "201 Created" || string(body) != `{"id":1}` {
+		t.Fatalf(`result: {%v} but expected {%v}`, res, mocker.mockResponse)
+	}
+}
+
+// TestRootEndpointWithMatchedRequestBody calls HTTPServer.root(http.ResponseWriter, *http.Request),
+// checking for a valid return value.
+func TestRootEndpointWithMatchedRequestBody(t *testing.T) {
+	mocker := internal.NewInMemoryMock(*logger)
+	for action, status := range map[string]string{"create": "201", "delete": "204"} {
+		if _, err := mocker.New(map[string][]string{
+			"status":            {status},
+			"contentType":       {"text/plain"},
+			"charset":           {"UTF-8"},
+			"when.method":       {"POST"},
+			"when.path":         {"/api/orders"},
+			"when.bodyContains": {`"action":"` + action + `"`},
+		}, nil); err != nil {
+			t.Fatal(err)
+		}
+	}
+	s := NewHTTPServer("{port}", false, "", workingDirectory, mocker, *logger)
+
+	for action, expected := range map[string]int{"create": 201, "delete": 204, "update": 404} {
+		w := httptest.NewRecorder()
+		s.root(w, httptest.NewRequest(http.MethodPost, "http://localhost:3333/api/orders", strings.NewReader(`{"action":"`+action+`"}`)))
+
+		if w.Code != expected {
+			t.Fatalf(`%s - result: {%v} but expected {%v}`, action, w.Code, expected)
+		}
+	}
+}
+
+// TestRootEndpointWithUnmatchedRequest calls HTTPServer.root(http.ResponseWriter, *http.Request),
+// checking for a valid return value.
+func TestRootEndpointWithUnmatchedRequest(t *testing.T) {
+	req := httptest.NewRequest(http.MethodGet, "http://localhost:3333/api/users", nil)
+	w := httptest.NewRecorder()
+
+	NewHTTPServer("{port}", false, "", workingDirectory, &MockerTest{}, *logger).root(w, req)
+
+	res, _ := geResultResponse(w, t)
+	if res.Status != "404 Not Found" {
+		t.Fatalf(`result: {%v} but expected {%v}`, res, "404")
+	}
+}
+
+// ##
+// #### ~/healthz and ~/readyz endpoints
+// ##
+
+// TestHealthzEndpoint calls HTTPServer.healthz(http.ResponseWriter, *http.Request),
+// checking for a valid return value.
+func TestHealthzEndpoint(t *testing.T) {
+	w := httptest.NewRecorder()
+
+	NewHTTPServer("{port}", false, "", "wrong-directory", &MockerTest{}, *logger).
+		healthz(w, httptest.NewRequest(http.MethodGet, "http://localhost:3333/healthz", nil))
+
+	res, body := geResultResponse(w, t)
+	if res.Status != "200 OK" || string(body) != `{"status":"ok"}` {
+		t.Fatalf(`result: {%v} but expected {%v}`, string(body), `{"status":"ok"}`)
+	}
+}
+
+// TestVersionEndpoint calls HTTPServer.version(http.ResponseWriter, *http.Request),
+// checking for a valid return value.
+func TestVersionEndpoint(t *testing.T) {
+	w := httptest.NewRecorder()
+
+	NewHTTPServer("{port}", false, "", workingDirectory, &MockerTest{}, *logger).
+		version(w, httptest.NewRequest(http.MethodGet, "http://localhost:3333/version", nil))
+
+	res, body := geResultResponse(w, t)
+	info, err := jsonsutil.Unmarshal[map[string]string](body)
+	if res.Status != "200 OK" || err != nil || info["version"] != "dev" {
+		t.Fatalf(`result: {%v} but expected {%v}`, string(body), "dev")
+	}
+}
+
+// TestReadyzEndpoint calls HTTPServer.readyz(http.ResponseWriter, *http.Request),
+// checking for a valid return value.
+func TestReadyzEndpoint(t *testing.T) {
+	dir := t.TempDir()
+	req := httptest.NewRequest(http.MethodGet, "http://localhost:3333/readyz", nil)
+	w := httptest.NewRecorder()
+
+	NewHTTPServer("{port}", false, "", dir, &MockerTest{}, *logger).readyz(w, req)
+
+	res, body := geResultResponse(w, t)
+	if res.Status != "200 OK" || string(body) != `{"status":"ok"}` {
+		t.Fatalf(`result: {%v} but expected {%v}`, string(body), `{"status":"ok"}`)
+	}
+	if entries, _ := os.ReadDir(dir); len(entries) != 0 {
+		t.Fatalf(`result: {%v} but expected {%v}`, entries, "no temporary file")
+	}
+
+	// test if the working directory cannot be written (a file instead of a directory)
+	file := dir + "/file"
+	iosutil.Write([]byte("..."), file)
+
+	for _, workingDirectory := range []string{file, dir + "/does-not-exist"} {
+		w = httptest.NewRecorder()
+		NewHTTPServer("{port}", false, "", workingDirectory, &MockerTest{}, *logger).readyz(w, req)
+
+		res, body = geResultResponse(w, t)
+		expected := `{"message": "working directory {` + workingDirectory + `} is not ready"}`
+		if res.Status != "503 Service Unavailable" || string(body) != expected {
+			t.Fatalf(`result: {%v} but expected {%v}`, string(body), expected)
+		}
+	}
+}
+
 // ##
 // #### ~/static/content-types endpoint
 // ##
@@ -181,6 +559,21 @@ func TestGetContentTypesEndpoint(t *testing.T) {
 	}
 }
 
+// TestGetContentTypesEndpointWithDisplayFilter calls HTTPServer.getContentTypes(http.ResponseWriter, *http.Request),
+// checking for a valid return value.
+func TestGetContentTypesEndpointWithDisplayFilter(t *testing.T) {
+	req := httptest.NewRequest(http.MethodGet, "http://localhost:3333/static/content-types?display=true", nil)
+	w := httptest.NewRecorder()
+
+	NewHTTPServer("{port}", false, "", workingDirectory, &MockerTest{}, *logger).getContentTypes(w, req)
+
+	_, body := geResultResponse(w, t)
+	data, _ := jsonsutil.Unmarshal[[]string](body)
+	if !reflect.DeepEqual(data, pkg.IS_DISPLAY_CONTENT) || slicesutil.Exist(data, "image/png") {
+		t.Fatalf(`result: {%v} but expected {%v}`, data, pkg.IS_DISPLAY_CONTENT)
+	}
+}
+
 // ##
 // #### ~/static/charsets endpoint
 // ##
@@ -217,6 +610,27 @@ func TestGetStatusCodesEndpoint(t *testing.T) {
 	}
 }
 
+// TestGetStaticsEndpoint calls HTTPServer.getStatics(http.ResponseWriter, *http.Request),
+// checking for a valid return value.
+func TestGetStaticsEndpoint(t *testing.T) {
+	req := httptest.NewRequest(http.MethodGet, "http://localhost:3333/static/all", nil)
+	w := httptest.NewRecorder()
+
+	NewHTTPServer("{port}", false, "", workingDirectory, &MockerTest{}, *logger).getStatics(w, req)
+
+	_, body := geResultResponse(w, t)
+	data, err := jsonsutil.Unmarshal[map[string]interface{}](body)
+	if err != nil {
+		t.Fatalf(err.Error())
+	}
+
+	for _, key := range []string{"contentTypes", "displayContentTypes", "charsets", "statusCodes"} {
+		if value, is := data[key]; !is || reflect.ValueOf(value).Len() == 0 {
+			t.Fatalf(`result: {%v} but expected {%v}`, data, key)
+		}
+	}
+}
+
 // ##
 // #### ~/v1/{id} endpoint
 // ##
@@ -235,6 +649,46 @@ func TestGetMockedRequestEndpointIdNotFound(t *testing.T) {
 	}
 }
 
+// TestGetMockedRequestEndpointWithNotFoundMock calls HTTPServer.getMockedRequest(http.ResponseWriter, *http.Request),
+// checking for a valid return value.
+func TestGetMockedRequestEndpointWithNotFoundMock(t *testing.T) {
+	mocker := internal.NewInMemoryMock(*logger)
+	id, err := mocker.New(map[string][]string{
+		"status":      {"200"},
+		"contentType": {"application/json"},
+		"charset":     {"UTF-8"},
+	}, []byte(`{"error": "not found"}`))
+	if err != nil {
+		t.Fatalf(err.Error())
+	}
+
+	s := NewHTTPServer("{port}", false, "", workingDirectory, mocker, *logger, WithNotFoundMock(*id))
+
+	req := httptest.NewRequest(http.MethodGet, "http://localhost:3333/v1/00000000-0000-0000-0000-000000000000", nil)
+	w := httptest.NewRecorder()
+	s.getMockedRequest(w, req)
+
+	res, body := geResultResponse(w, t)
+	if res.Status != "404 Not Found" || string(body) != `{"error": "not found"}` ||
+		res.Header.Get("Content-Type") != "application/json; charset=UTF-8" {
+		t.Fatalf(`result: {%v %s} but expected {%v}`, res.Status, string(body), "404 with the custom body")
+	}
+}
+
+// TestGetMockedRequestEndpointWithMalformedId calls HTTPServer.getMockedRequest(http.ResponseWriter, *http.Request),
+// checking for a valid return value.
+func TestGetMockedRequestEndpointWithMalformedId(t *testing.T) {
+	req := httptest.NewRequest(http.MethodGet, "http://localhost:3333/v1/raw/..%2F..%2Fetc%2Fpasswd", nil)
+	w := httptest.NewRecorder()
+
+	NewHTTPServer("{port}", false, "", t.TempDir(), internal.NewMock(t.TempDir(), nil, *logger), *logger).getMockedRequestRaw(w, req)
+
+	res, body := geResultResponse(w, t)
+	if res.Status != "400 Bad Request" || string(body) != `{"message": "mocked request id {passwd} is malformed"}` {
+		t.Fatalf(`result: {%v} but expected {%v}`, string(body), "400")
+	}
+}
+
 // TestGetMockedRequestEndpoint calls HTTPServer.getMockedRequest(http.ResponseWriter, *http.Request),
 // checking for a valid return value.
 func TestGetMockedRequestEndpoint(t *testing.T) {
@@ -263,6 +717,30 @@ func TestGetMockedRequestEndpoint(t *testing.T) {
 	}
 }
 
+// TestGetMockedRequestEndpointWithMalformedDelay calls HTTPServer.getMockedRequest(http.ResponseWriter, *http.Request),
+// checking for a valid return value.
+func TestGetMockedRequestEndpointWithMalformedDelay(t *testing.T) {
+	req := httptest.NewRequest(http.MethodGet, "http://localhost:3333/v1/{id}?delay=1s-wrong", nil)
+	w := httptest.NewRecorder()
+
+	mocker := &MockerTest{
+		mockResponse: &internal.MockedRequest{
+			MockedRequestLight: internal.MockedRequestLight{
+				MockedRequestHeader: internal.MockedRequestHeader{
+					Status: 200,
+				},
+			},
+		},
+	}
+
+	NewHTTPServer("{port}", false, "", workingDirectory, mocker, *logger).getMockedRequest(w, req)
+
+	res, body := geResultResponse(w, t)
+	if res.Status != "400 Bad Request" || string(body) != `{"message": "delay {1s-wrong} is malformed"}` {
+		t.Fatalf(`result: {%v} but expected {%v}`, res, "400")
+	}
+}
+
 // TestGetMockedRequestEndpointWithBadRequestURI calls HTTPServer.getMockedRequest(http.ResponseWriter, *http.Request),
 // checking for a valid return value.
 func TestGetMockedRequestEndpointWithBadRequestURI(t *testing.T) {
@@ -281,173 +759,1403 @@ func TestGetMockedRequestEndpointWithBadRequestURI(t *testing.T) {
 }
 
 // ##
-// #### ~/v1/raw/{id} endpoint
+// #### ~/v1/{id}/stats endpoint
 // ##
 
-// TestGetMockedRequestRawEndpoint calls HTTPServer.getMockedRequestRaw(http.ResponseWriter, *http.Request),
+// TestGetMockedRequestStatsEndpoint calls HTTPServer.getMockedRequestStats(http.ResponseWriter, *http.Request),
 // checking for a valid return value.
-func TestGetMockedRequestRawEndpoint(t *testing.T) {
-	req := httptest.NewRequest(http.MethodGet, "http://localhost:3333/v1/raw/{id}", nil)
+func TestGetMockedRequestStatsEndpoint(t *testing.T) {
+	mocker := internal.NewMock(t.TempDir(), nil, *logger)
+	id, err := mocker.New(map[string][]string{
+		"status":      {"200"},
+		"contentType": {"text/plain"},
+		"charset":     {"UTF-8"},
+	}, []byte("Hello World"))
+	if err != nil {
+		t.Fatalf(err.Error())
+	}
+
+	s := NewHTTPServer("{port}", false, "", workingDirectory, mocker, *logger)
+	for i := 0; i < 3; i++ {
+		s.dispatchMockedRequest(httptest.NewRecorder(), httptest.NewRequest(http.MethodGet, "http://localhost:3333/v1/"+*id, nil))
+	}
+
 	w := httptest.NewRecorder()
+	s.dispatchMockedRequest(w, httptest.NewRequest(http.MethodGet, "http://localhost:3333/v1/"+*id+"/stats", nil))
 
-	mocker := &MockerTest{
-		mockResponse: &internal.MockedRequest{
-			MockedRequestLight: internal.MockedRequestLight{
-				MockedRequestHeader: internal.MockedRequestHeader{
-					Status:      200,
-					ContentType: "text/plain",
-					Charset:     "UTF-8",
-					Headers:     map[string]string{"x-language": "golang"},
+	res, body := geResultResponse(w, t)
+	if res.Status != "200 OK" || !strings.Contains(string(body), `"hits":3`) || !strings.Contains(string(body), `"uuid":"`+*id+`"`) {
+		t.Fatalf(`result: {%v} but expected {%v}`, string(body), "hits:3")
+	}
+}
+
+// TestGetMockedRequestStatsEndpointIdNotFound calls HTTPServer.getMockedRequestStats(http.ResponseWriter, *http.Request),
+// checking for a valid return value.
+func TestGetMockedRequestStatsEndpointIdNotFound(t *testing.T) {
+	w := httptest.NewRecorder()
+
+	NewHTTPServer("{port}", false, "", workingDirectory, &MockerTest{}, *logger).
+		dispatchMockedRequest(w, httptest.NewRequest(http.MethodGet, "http://localhost:3333/v1/{id}/stats", nil))
+
+	res, _ := geResultResponse(w, t)
+	if res.Status != "404 Not Found" {
+		t.Fatalf(`result: {%v} but expected {%v}`, res, "404")
+	}
+}
+
+// ##
+// #### ~/v1/{id}/curl endpoint
+// ##
+
+// TestGetMockedRequestCurlEndpoint calls HTTPServer.getMockedRequestCurl(http.ResponseWriter, *http.Request),
+// checking for a valid return value.
+func TestGetMockedRequestCurlEndpoint(t *testing.T) {
+	mocker := internal.NewMock(t.TempDir(), nil, *logger)
+	id, err := mocker.New(map[string][]string{
+		"status":      {"201"},
+		"contentType": {"application/json"},
+		"charset":     {"UTF-8"},
+	}, []byte(`{"name": "mockapic"}`))
+	if err != nil {
+		t.Fatalf(err.Error())
+	}
+
+	w := httptest.NewRecorder()
+	NewHTTPServer("{port}", false, "", workingDirectory, mocker, *logger).
+		dispatchMockedRequest(w, httptest.NewRequest(http.MethodGet, "http://localhost:3333/v1/"+*id+"/curl", nil))
+
+	res, body := geResultResponse(w, t)
+	expected := "curl -i -X GET 'http://localhost:3333/v1/" + *id + "'\n# expected: 201 Created (application/json)\n"
+	if res.StatusCode != 200 || !strings.HasPrefix(string(body), "curl") || string(body) != expected {
+		t.Fatalf(`result: {%v} but expected {%v}`, string(body), expected)
+	}
+}
+
+// TestShellQuote calls shellQuote(string),
+// checking for a valid return value.
+func TestShellQuote(t *testing.T) {
+	if value := shellQuote("http://localhost/it's"); value != `'http://localhost/it'\''s'` {
+		t.Fatalf(`result: {%v} but expected {%v}`, value, `'http://localhost/it'\''s'`)
+	}
+}
+
+// TestDispatchMockedRequestWithUnknownAction calls HTTPServer.dispatchMockedRequest(http.ResponseWriter, *http.Request),
+// checking for a valid return value.
+func TestDispatchMockedRequestWithUnknownAction(t *testing.T) {
+	w := httptest.NewRecorder()
+
+	NewHTTPServer("{port}", false, "", workingDirectory, &MockerTest{}, *logger).
+		dispatchMockedRequest(w, httptest.NewRequest(http.MethodGet, "http://localhost:3333/v1/{id}/unknown", nil))
+
+	res, _ := geResultResponse(w, t)
+	if res.Status != "404 Not Found" {
+		t.Fatalf(`result: {%v} but expected {%v}`, res, "404")
+	}
+}
+
+// ##
+// #### ~/v1/{id}/reset endpoint
+// ##
+
+// TestResetMockedRequestEndpoint calls HTTPServer.resetMockedRequest(http.ResponseWriter, *http.Request),
+// checking for a valid return value.
+func TestResetMockedRequestEndpoint(t *testing.T) {
+	mocker := internal.NewMock(t.TempDir(), nil, *logger)
+	id, err := mocker.New(map[string][]string{
+		"status":      {"200"},
+		"contentType": {"text/plain"},
+		"charset":     {"UTF-8"},
+		"sequence":    {"true"},
+	}, []byte(`[{"status":503},{"status":503},{"status":200,"body":"OK"}]`))
+	if err != nil {
+		t.Fatalf(err.Error())
+	}
+
+	s := NewHTTPServer("{port}", false, "", workingDirectory, mocker, *logger)
+	call := func(method, uri string) *httptest.ResponseRecorder {
+		w := httptest.NewRecorder()
+		s.dispatchMockedRequest(w, httptest.NewRequest(method, "http://localhost:3333"+uri, nil))
+		return w
+	}
+
+	statuses := []int{}
+	for i := 0; i < 4; i++ {
+		statuses = append(statuses, call(http.MethodGet, "/v1/"+*id).Code)
+	}
+	if !reflect.DeepEqual(statuses, []int{503, 503, 200, 503}) {
+		t.Fatalf(`result: {%v} but expected {%v}`, statuses, []int{503, 503, 200, 503})
+	}
+
+	if w := call(http.MethodPost, "/v1/"+*id+"/reset"); w.Code != 204 {
+		t.Fatalf(`result: {%v} but expected {%v}`, w.Code, 204)
+	}
+
+	if w := call(http.MethodGet, "/v1/"+*id); w.Code != 503 {
+		t.Fatalf(`result: {%v} but expected {%v}`, w.Code, 503)
+	}
+
+	// test if the mocked request does not exist
+	if w := call(http.MethodPost, "/v1/id-does-not-exist/reset"); w.Code != 404 {
+		t.Fatalf(`result: {%v} but expected {%v}`, w.Code, 404)
+	}
+}
+
+// TestHeadMockedRequestEndpoint calls HTTPServer.dispatchMockedRequest(http.ResponseWriter, *http.Request),
+// checking for a valid return value.
+func TestHeadMockedRequestEndpoint(t *testing.T) {
+	mocker := internal.NewInMemoryMock(*logger)
+	id, err := mocker.New(map[string][]string{
+		"status":      {"201"},
+		"contentType": {"text/plain"},
+		"charset":     {"UTF-8"},
+		"X-Language":  {"golang"},
+	}, []byte("Hello World"))
+	if err != nil {
+		t.Fatalf(err.Error())
+	}
+
+	s := NewHTTPServer("{port}", false, "", workingDirectory, mocker, *logger)
+	call := func(method string) *httptest.ResponseRecorder {
+		w := httptest.NewRecorder()
+		s.dispatchMockedRequest(w, httptest.NewRequest(method, "http://localhost:3333/v1/"+*id, nil))
+		return w
+	}
+
+	get, head := call(http.MethodGet), call(http.MethodHead)
+	if head.Code != 201 || head.Body.Len() != 0 ||
+		head.Header().Get("Content-Length") != "11" ||
+		head.Header().Get("Content-Length") != get.Header().Get("Content-Length") ||
+		head.Header().Get("Content-Type") != "text/plain; charset=UTF-8" ||
+		head.Header().Get("X-Language") != "golang" {
+		t.Fatalf(`result: {%v} but expected {%v}`, head.Header(), get.Header())
+	}
+
+	// test if the "HEAD" request is not counted as a hit
+	if mock, _ := mocker.Get(*id); mock.Hits != 1 {
+		t.Fatalf(`result: {%v} but expected {%v}`, mock.Hits, 1)
+	}
+
+	// test if the "HEAD" request is not allowed on the other actions
+	w := httptest.NewRecorder()
+	s.dispatchMockedRequest(w, httptest.NewRequest(http.MethodHead, "http://localhost:3333/v1/"+*id+"/reset", nil))
+	if w.Code != 405 {
+		t.Fatalf(`result: {%v} but expected {%v}`, w.Code, 405)
+	}
+}
+
+// TestMockedRequestEndpointWithMethod calls HTTPServer.dispatchMockedRequest(http.ResponseWriter, *http.Request),
+// checking for a valid return value.
+func TestMockedRequestEndpointWithMethod(t *testing.T) {
+	mocker := internal.NewInMemoryMock(*logger)
+	newMock := func(method string) string {
+		id, err := mocker.New(map[string][]string{
+			"status":      {"200"},
+			"contentType": {"text/plain"},
+			"charset":     {"UTF-8"},
+			"method":      {method},
+		}, []byte("Hello World"))
+		if err != nil {
+			t.Fatalf(err.Error())
+		}
+		return *id
+	}
+
+	s := NewHTTPServer("{port}", false, "", workingDirectory, mocker, *logger)
+	call := func(method, id string) *httptest.ResponseRecorder {
+		w := httptest.NewRecorder()
+		s.dispatchMockedRequest(w, httptest.NewRequest(method, "http://localhost:3333/v1/"+id, nil))
+		return w
+	}
+
+	getId := newMock("get")
+	if w := call(http.MethodGet, getId); w.Code != 200 || w.Body.String() != "Hello World" {
+		t.Fatalf(`result: {%v} but expected {%v}`, w.Code, 200)
+	}
+
+	// test if the other methods are rejected without any hit
+	w := call(http.MethodPost, getId)
+	if w.Code != 405 || w.Header().Get("Allow") != "GET" {
+		t.Fatalf(`result: {%v} but expected {%v}`, w.Code, 405)
+	}
+	if mock, _ := mocker.Get(getId); mock.Hits != 1 {
+		t.Fatalf(`result: {%v} but expected {%v}`, mock.Hits, 1)
+	}
+
+	postId := newMock("POST")
+	if w := call(http.MethodPost, postId); w.Code != 200 {
+		t.Fatalf(`result: {%v} but expected {%v}`, w.Code, 200)
+	}
+	if w := call(http.MethodGet, postId); w.Code != 405 || w.Header().Get("Allow") != "POST" {
+		t.Fatalf(`result: {%v} but expected {%v}`, w.Code, 405)
+	}
+
+	// test if the method is enforced on the matched requests too
+	matchedId, _ := mocker.New(map[string][]string{
+		"contentType": {"text/plain"},
+		"charset":     {"UTF-8"},
+		"method":      {"POST"},
+		"when.path":   {"/users/*"},
+	}, []byte("Hello World"))
+	w = httptest.NewRecorder()
+	s.matchMockedRequest(w, httptest.NewRequest(http.MethodGet, "http://localhost:3333/users/1", nil))
+	if w.Code != 405 || w.Header().Get("Allow") != "POST" {
+		t.Fatalf(`result: {%v} but expected {%v}`, w.Code, 405)
+	}
+	if mock, _ := mocker.Get(*matchedId); mock.Hits != 0 {
+		t.Fatalf(`result: {%v} but expected {%v}`, mock.Hits, 0)
+	}
+
+	// test if the matched request is counted as a hit
+	w = httptest.NewRecorder()
+	s.matchMockedRequest(w, httptest.NewRequest(http.MethodPost, "http://localhost:3333/users/1", nil))
+	if mock, _ := mocker.Get(*matchedId); w.Code != 200 || mock.Hits != 1 {
+		t.Fatalf(`result: {%v} but expected {%v}`, mock.Hits, 1)
+	}
+}
+
+// TestCloneMockedRequestEndpoint calls HTTPServer.dispatchMockedRequest(http.ResponseWriter, *http.Request),
+// checking for a valid return value.
+func TestCloneMockedRequestEndpoint(t *testing.T) {
+	mocker := internal.NewMock(t.TempDir(), nil, *logger)
+	id, err := mocker.New(map[string][]string{
+		"status":      {"200"},
+		"contentType": {"text/plain"},
+		"charset":     {"UTF-8"},
+	}, []byte("Hello World"))
+	if err != nil {
+		t.Fatalf(err.Error())
+	}
+
+	s := NewHTTPServer("{port}", false, "", workingDirectory, mocker, *logger)
+
+	w := httptest.NewRecorder()
+	s.dispatchMockedRequest(w, httptest.NewRequest(http.MethodPost, "http://localhost:3333/v1/"+*id+"/clone?status=503", nil))
+
+	resp, body := geResultResponse(w, t)
+	if resp.StatusCode != 200 {
+		t.Fatalf(`result: {%v} but expected {%v}`, resp.StatusCode, 200)
+	}
+
+	data, err := jsonsutil.Unmarshal[map[string]interface{}](body)
+	if err != nil {
+		t.Fatalf(err.Error())
+	}
+
+	clone, err := mocker.Get(data["id"].(string))
+	if err != nil || clone.Id == *id || clone.Status != 503 || string(clone.Body64) != "Hello World" {
+		t.Fatalf(`result: {%v} but expected {%v}`, clone, "a copy of the body with the status 503")
+	}
+
+	// test if the source is not updated
+	if source, _ := mocker.Get(*id); source.Status != 200 {
+		t.Fatalf(`result: {%v} but expected {%v}`, source.Status, 200)
+	}
+
+	// test if the mocked request does not exist
+	w = httptest.NewRecorder()
+	s.dispatchMockedRequest(w, httptest.NewRequest(http.MethodPost, "http://localhost:3333/v1/00000000-0000-0000-0000-000000000000/clone", nil))
+	if w.Code != 404 {
+		t.Fatalf(`result: {%v} but expected {%v}`, w.Code, 404)
+	}
+
+	// test if the override is not valid
+	w = httptest.NewRecorder()
+	s.dispatchMockedRequest(w, httptest.NewRequest(http.MethodPost, "http://localhost:3333/v1/"+*id+"/clone?status=999", nil))
+	if w.Code != 409 {
+		t.Fatalf(`result: {%v} but expected {%v}`, w.Code, 409)
+	}
+}
+
+// ##
+// #### ~/v1/raw/{id} endpoint
+// ##
+
+// TestGetMockedRequestRawEndpoint calls HTTPServer.getMockedRequestRaw(http.ResponseWriter, *http.Request),
+// checking for a valid return value.
+func TestGetMockedRequestRawEndpoint(t *testing.T) {
+	req := httptest.NewRequest(http.MethodGet, "http://localhost:3333/v1/raw/{id}", nil)
+	w := httptest.NewRecorder()
+
+	mocker := &MockerTest{
+		mockResponse: &internal.MockedRequest{
+			MockedRequestLight: internal.MockedRequestLight{
+				MockedRequestHeader: internal.MockedRequestHeader{
+					Status:      200,
+					ContentType: "text/plain",
+					Charset:     "UTF-8",
+					Headers:     map[string]string{"x-language": "golang"},
 				},
 			},
 			Body64: []byte("Hello World"),
 		},
 	}
 
-	NewHTTPServer("{port}", false, "", workingDirectory, mocker, *logger).getMockedRequestRaw(w, req)
+	NewHTTPServer("{port}", false, "", workingDirectory, mocker, *logger).getMockedRequestRaw(w, req)
+
+	res, data := geResultResponse(w, t)
+
+	if res.Status != "200 OK" ||
+		string(data) != `{"status":200,"contentType":"text/plain","charset":"UTF-8","headers":{"x-language":"golang"},"body":"Hello World","body64":"SGVsbG8gV29ybGQ="}` {
+
+		t.Fatalf(`result: {%v} but expected {%v}`, res, mocker.mockResponse)
+	}
+}
+
+// TestGetMockedRequestStoredEndpoint calls HTTPServer.dispatchMockedRequest(http.ResponseWriter, *http.Request),
+// checking for a valid return value.
+func TestGetMockedRequestStoredEndpoint(t *testing.T) {
+	mocker := internal.NewMock(t.TempDir(), nil, *logger)
+	id, err := mocker.New(map[string][]string{
+		"status":      {"200"},
+		"contentType": {"text/plain"},
+		"charset":     {"UTF-8"},
+		"delay":       {"10s"},
+	}, []byte("Hello World"))
+	if err != nil {
+		t.Fatalf(err.Error())
+	}
+
+	s := NewHTTPServer("{port}", false, "", workingDirectory, mocker, *logger)
+	w := httptest.NewRecorder()
+	s.dispatchMockedRequest(w, httptest.NewRequest(http.MethodGet, "http://localhost:3333/v1/"+*id+"/raw", nil))
+
+	res, body := geResultResponse(w, t)
+	if res.Status != "200 OK" || res.Header.Get("Content-Type") != "application/json" ||
+		!strings.Contains(string(body), `"createdAt":`) || !strings.Contains(string(body), `"body64":"SGVsbG8gV29ybGQ="`) {
+		t.Fatalf(`result: {%v} but expected {%v}`, string(body), "the stored file")
+	}
+
+	for mockId, status := range map[string]int{"malformed": 400, "d0b9ac5e-8a2f-4e31-9a77-2a6b2f1c7e10": 404} {
+		w := httptest.NewRecorder()
+		s.dispatchMockedRequest(w, httptest.NewRequest(http.MethodGet, "http://localhost:3333/v1/"+mockId+"/raw", nil))
+		if w.Code != status {
+			t.Fatalf(`%s - result: {%v} but expected {%v}`, mockId, w.Code, status)
+		}
+	}
+}
+
+// ##
+// #### ~/v1/list endpoint
+// ##
+
+// TestListEndpointWithError calls HTTPServer.list(http.ResponseWriter, *http.Request),
+// checking for a valid return value.
+func TestListEndpointWithError(t *testing.T) {
+	req := httptest.NewRequest(http.MethodGet, "http://localhost:3333/v1/list", nil)
+	w := httptest.NewRecorder()
+
+	NewHTTPServer("{port}", false, "", workingDirectory, &MockerTest{}, *logger).list(w, req)
+
+	res, body := geResultResponse(w, t)
+	if res.Status != "500 Internal Server Error" || string(body) != `{"message": "error to list mocked responses"}` {
+		t.Fatalf(`result: {%v} but expected {%v}`, res, "409")
+	}
+}
+
+// TestListEndpoint calls HTTPServer.list(http.ResponseWriter, *http.Request),
+// checking for a valid return value.
+func TestListEndpoint(t *testing.T) {
+	req := httptest.NewRequest(http.MethodGet, "http://localhost:3333/v1/list", nil)
+	w := httptest.NewRecorder()
+
+	mocker := &MockerTest{
+		mockResponseLights: []internal.MockedRequestLight{
+			{
+				MockedRequestHeader: internal.MockedRequestHeader{
+					Status:      200,
+					ContentType: "text/plain",
+					Charset:     "UTF-8",
+					Headers:     map[string]string{"x-language": "golang"},
+				},
+				Id: "{id-200}",
+			},
+			{
+				MockedRequestHeader: internal.MockedRequestHeader{
+					Status:      404,
+					ContentType: "application/json",
+					Charset:     "UTF-8",
+					Headers:     map[string]string{"x-language": "golang"},
+				},
+				Id: "{id-404}",
+			},
+		},
+	}
+	NewHTTPServer("{port}", false, "", workingDirectory, mocker, *logger).list(w, req)
+
+	res, body := geResultResponse(w, t)
+
+	expected := `[{"id":"{id-200}","status":200,"contentType":"text/plain","charset":"UTF-8","headers":{"x-language":"golang"},"_links":{"raw":"http://localhost:3333/v1/raw/{id-200}","self":"http://localhost:3333/v1/{id-200}"}},{"id":"{id-404}","status":404,"contentType":"application/json","charset":"UTF-8","headers":{"x-language":"golang"},"_links":{"raw":"http://localhost:3333/v1/raw/{id-404}","self":"http://localhost:3333/v1/{id-404}"}}]`
+	if res.Status != "200 OK" || string(body) != expected {
+		t.Fatalf(`result: {%v} but expected {%v}`, res, mocker)
+	}
+}
+
+// TestListEndpointReturnsEmptyNilInsteadOfNull calls HTTPServer.list(http.ResponseWriter, *http.Request),
+// checking for a valid return value.
+func TestListEndpointReturnsEmptyNilInsteadOfNull(t *testing.T) {
+	req := httptest.NewRequest(http.MethodGet, "http://localhost:3333/v1/list", nil)
+	w := httptest.NewRecorder()
+
+	mocker := &MockerTest{
+		mockResponseLights: []internal.MockedRequestLight{},
+	}
+	NewHTTPServer("{port}", false, "", workingDirectory, mocker, *logger).list(w, req)
+
+	res, body := geResultResponse(w, t)
+
+	if res.Status != "200 OK" || string(body) != "[]" {
+		t.Fatalf(`result: {%v} but expected {%v}`, string(body), "[]")
+	}
+}
+
+// ##
+// #### ~/v1/search endpoint
+// ##
+
+// TestSearchEndpoint calls HTTPServer.search(http.ResponseWriter, *http.Request),
+// checking for a valid return value.
+func TestSearchEndpoint(t *testing.T) {
+	mocker := &MockerTest{
+		mockResponseLights: []internal.MockedRequestLight{
+			{
+				Id:                  "{id-1}",
+				CreatedAt:           "2024-06-01 10:00:00",
+				MockedRequestHeader: internal.MockedRequestHeader{Status: 404, ContentType: "application/json"},
+			},
+			{
+				Id:                  "{id-2}",
+				CreatedAt:           "2023-06-01 10:00:00",
+				MockedRequestHeader: internal.MockedRequestHeader{Status: 404, ContentType: "text/plain"},
+			},
+			{
+				Id:                  "{id-3}",
+				CreatedAt:           "2024-06-01 10:00:00",
+				MockedRequestHeader: internal.MockedRequestHeader{Status: 200, ContentType: "application/json"},
+			},
+		},
+	}
+
+	tests := []struct {
+		query    string
+		expected []string
+	}{
+		{"", []string{"{id-1}", "{id-2}", "{id-3}"}},
+		{"status=404", []string{"{id-1}", "{id-2}"}},
+		{"contentType=application%2Fjson", []string{"{id-1}", "{id-3}"}},
+		{"since=2024-01-01", []string{"{id-1}", "{id-3}"}},
+		{"status=404&contentType=application%2Fjson&since=2024-01-01", []string{"{id-1}"}},
+		{"status=500", []string{}},
+	}
+
+	for _, test := range tests {
+		req := httptest.NewRequest(http.MethodGet, "http://localhost:3333/v1/search?"+test.query, nil)
+		w := httptest.NewRecorder()
+
+		NewHTTPServer("{port}", false, "", workingDirectory, mocker, *logger).search(w, req)
+
+		res, body := geResultResponse(w, t)
+		data, _ := jsonsutil.Unmarshal[[]MockedRequestLightWithLinks](body)
+		ids := []string{}
+		for _, mrl := range data {
+			ids = append(ids, mrl.Id)
+		}
+		if res.Status != "200 OK" || !reflect.DeepEqual(ids, test.expected) {
+			t.Fatalf(`%s - result: {%v} but expected {%v}`, test.query, ids, test.expected)
+		}
+	}
+}
+
+// TestSearchEndpointWithTag calls HTTPServer.search(http.ResponseWriter, *http.Request),
+// checking for a valid return value.
+func TestSearchEndpointWithTag(t *testing.T) {
+	s := NewHTTPServer("{port}", false, "", t.TempDir(), internal.NewInMemoryMock(*logger), *logger)
+
+	newMock := func(query string, headers map[string]string) string {
+		req := httptest.NewRequest(http.MethodPost, "http://localhost:3333/v1/new?contentType=text%2Fplain&charset=UTF-8&"+query, nil)
+		for key, value := range headers {
+			req.Header.Set(key, value)
+		}
+		w := httptest.NewRecorder()
+		s.addNewMock(w, req)
+
+		_, body := geResultResponse(w, t)
+		data, _ := jsonsutil.Unmarshal[map[string]any](body)
+		return data["id"].(string)
+	}
+
+	paymentsId := newMock("tags=team-payments,v2&createdBy=alice", nil)
+	headersId := newMock("", map[string]string{"X-Tags": "team-payments", "X-Created-By": "bob"})
+	newMock("tags=team-orders", nil)
+
+	w := httptest.NewRecorder()
+	s.search(w, httptest.NewRequest(http.MethodGet, "http://localhost:3333/v1/search?tag=team-payments", nil))
+
+	_, body := geResultResponse(w, t)
+	data, _ := jsonsutil.Unmarshal[[]MockedRequestLightWithLinks](body)
+	ids := slicesutil.TransformT[MockedRequestLightWithLinks, string](data, func(mrl MockedRequestLightWithLinks) (*string, error) {
+		return &mrl.Id, nil
+	})
+	slices.Sort(ids)
+
+	expected := []string{paymentsId, headersId}
+	slices.Sort(expected)
+	if !reflect.DeepEqual(ids, expected) {
+		t.Fatalf(`result: {%v} but expected {%v}`, ids, expected)
+	}
+
+	for _, mrl := range data {
+		if (mrl.Id == paymentsId && mrl.CreatedBy != "alice") || (mrl.Id == headersId && mrl.CreatedBy != "bob") {
+			t.Fatalf(`result: {%v} but expected {%v}`, mrl.CreatedBy, "alice or bob")
+		}
+	}
+}
+
+// TestSearchEndpointWithBadRequest calls HTTPServer.search(http.ResponseWriter, *http.Request),
+// checking for a valid return value.
+func TestSearchEndpointWithBadRequest(t *testing.T) {
+	tests := map[string]string{
+		"status=wrong": `{"message": "status {wrong} is malformed"}`,
+		"since=wrong":  `{"message": "since {wrong} is malformed"}`,
+	}
+
+	for query, expected := range tests {
+		req := httptest.NewRequest(http.MethodGet, "http://localhost:3333/v1/search?"+query, nil)
+		w := httptest.NewRecorder()
+
+		NewHTTPServer("{port}", false, "", workingDirectory, &MockerTest{}, *logger).search(w, req)
+
+		res, body := geResultResponse(w, t)
+		if res.Status != "400 Bad Request" || string(body) != expected {
+			t.Fatalf(`result: {%v} but expected {%v}`, string(body), expected)
+		}
+	}
+
+	// test if Mocker.Search returns an error
+	w := httptest.NewRecorder()
+	NewHTTPServer("{port}", false, "", workingDirectory, &MockerTest{}, *logger).
+		search(w, httptest.NewRequest(http.MethodGet, "http://localhost:3333/v1/search", nil))
+
+	if res, _ := geResultResponse(w, t); res.Status != "500 Internal Server Error" {
+		t.Fatalf(`result: {%v} but expected {%v}`, res.Status, "500")
+	}
+}
+
+// ##
+// #### ~/v1/count endpoint
+// ##
+
+// TestCountEndpoint calls HTTPServer.count(http.ResponseWriter, *http.Request),
+// checking for a valid return value.
+func TestCountEndpoint(t *testing.T) {
+	req := httptest.NewRequest(http.MethodGet, "http://localhost:3333/v1/count", nil)
+	w := httptest.NewRecorder()
+
+	mocker := &MockerTest{
+		mockResponseLights: []internal.MockedRequestLight{{Id: "{id-1}"}, {Id: "{id-2}"}},
+	}
+	NewHTTPServer("{port}", false, "", workingDirectory, mocker, *logger).count(w, req)
+
+	res, body := geResultResponse(w, t)
+	if res.Status != "200 OK" || string(body) != `{"count":2}` {
+		t.Fatalf(`result: {%v} but expected {%v}`, string(body), `{"count":2}`)
+	}
+}
+
+// TestCountEndpointWithError calls HTTPServer.count(http.ResponseWriter, *http.Request),
+// checking for a valid return value.
+func TestCountEndpointWithError(t *testing.T) {
+	req := httptest.NewRequest(http.MethodGet, "http://localhost:3333/v1/count", nil)
+	w := httptest.NewRecorder()
+
+	NewHTTPServer("{port}", false, "", workingDirectory, &MockerTest{}, *logger).count(w, req)
+
+	res, body := geResultResponse(w, t)
+	if res.Status != "500 Internal Server Error" || string(body) != `{"message": "error to count mocked responses"}` {
+		t.Fatalf(`result: {%v} but expected {%v}`, res, "500")
+	}
+}
+
+// TestFacetsEndpoint calls HTTPServer.facets(http.ResponseWriter, *http.Request),
+// checking for a valid return value.
+func TestFacetsEndpoint(t *testing.T) {
+	mocker := internal.NewInMemoryMock(*logger)
+	for _, params := range []map[string][]string{
+		{"status": {"404"}, "contentType": {"text/plain"}, "charset": {"UTF-8"}},
+		{"status": {"200"}, "contentType": {"application/json"}, "charset": {"UTF-8"}},
+		{"status": {"200"}, "contentType": {"text/plain"}, "charset": {"UTF-8"}},
+	} {
+		if _, err := mocker.New(params, []byte(`{"name": "mockapic"}`)); err != nil {
+			t.Fatalf(err.Error())
+		}
+	}
+
+	req := httptest.NewRequest(http.MethodGet, "http://localhost:3333/v1/facets", nil)
+	w := httptest.NewRecorder()
+	NewHTTPServer("{port}", false, "", workingDirectory, mocker, *logger).facets(w, req)
+
+	expected := `{"contentTypes":["application/json","text/plain"],"statuses":[200,404]}`
+	if res, body := geResultResponse(w, t); res.Status != "200 OK" || string(body) != expected {
+		t.Fatalf(`result: {%v} but expected {%v}`, string(body), expected)
+	}
+
+	// test if the store is empty
+	w = httptest.NewRecorder()
+	NewHTTPServer("{port}", false, "", workingDirectory, internal.NewInMemoryMock(*logger), *logger).facets(w, req)
+
+	expected = `{"contentTypes":[],"statuses":[]}`
+	if res, body := geResultResponse(w, t); res.Status != "200 OK" || string(body) != expected {
+		t.Fatalf(`result: {%v} but expected {%v}`, string(body), expected)
+	}
+}
+
+// TestCollectionsEndpoint calls HTTPServer.collections(http.ResponseWriter, *http.Request)
+// and HTTPServer.deleteCollection(http.ResponseWriter, *http.Request),
+// checking for a valid return value.
+func TestCollectionsEndpoint(t *testing.T) {
+	mocker := internal.NewMock(t.TempDir(), nil, *logger)
+	for _, collection := range []string{"checkout", "users", "checkout", ""} {
+		if _, err := mocker.New(map[string][]string{
+			"status": {"200"}, "contentType": {"text/plain"}, "charset": {"UTF-8"}, "collection": {collection},
+		}, []byte("Hello World")); err != nil {
+			t.Fatalf(err.Error())
+		}
+	}
+	handler := NewHTTPServer("{port}", false, "", workingDirectory, mocker, *logger).handler()
+
+	w := httptest.NewRecorder()
+	handler.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "http://localhost:3333/v1/collections", nil))
+	expected := `{"collections":[{"name":"checkout","count":2},{"name":"users","count":1}]}`
+	if res, body := geResultResponse(w, t); res.Status != "200 OK" || string(body) != expected {
+		t.Fatalf(`result: {%v} but expected {%v}`, string(body), expected)
+	}
+
+	w = httptest.NewRecorder()
+	handler.ServeHTTP(w, httptest.NewRequest(http.MethodDelete, "http://localhost:3333/v1/collections/checkout", nil))
+	expected = `{"deleted":2,"errors":{}}`
+	if res, body := geResultResponse(w, t); res.Status != "200 OK" || string(body) != expected {
+		t.Fatalf(`result: {%v} but expected {%v}`, string(body), expected)
+	}
+
+	w = httptest.NewRecorder()
+	handler.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "http://localhost:3333/v1/collections", nil))
+	expected = `{"collections":[{"name":"users","count":1}]}`
+	if res, body := geResultResponse(w, t); res.Status != "200 OK" || string(body) != expected {
+		t.Fatalf(`result: {%v} but expected {%v}`, string(body), expected)
+	}
+	if count, _ := mocker.Count(); count != 2 {
+		t.Fatalf(`result: {%v} but expected {%v}`, count, 2)
+	}
+
+	w = httptest.NewRecorder()
+	handler.ServeHTTP(w, httptest.NewRequest(http.MethodDelete, "http://localhost:3333/v1/collections/", nil))
+	expected = `{"message": "collection must be defined"}`
+	if res, body := geResultResponse(w, t); res.Status != "400 Bad Request" || string(body) != expected {
+		t.Fatalf(`result: {%v} but expected {%v}`, string(body), expected)
+	}
+}
+
+// ##
+// #### ~/v1/new endpoint
+// ##
+
+// TestAddNewEndpoint calls HTTPServer.addNewMock(http.ResponseWriter, *http.Request),
+// checking for a valid return value.
+func TestAddNewEndpoint(t *testing.T) {
+	internal.MOCKAPIC_REQ_MAX_LIMIT = 100
+	err := iosutil.Write([]byte(``), workingDirectory+"/remote-addr.json")
+	if err != nil {
+		t.Errorf("Error: %v", err)
+	}
+
+	URL := "http://localhost:3333/v1/new?status=200&contentType=text/plain&charset=UTF-8"
+	req := httptest.NewRequest(http.MethodPost, URL, strings.NewReader("Hello World"))
+	w := httptest.NewRecorder()
+
+	mocker := &MockerTest{mockResponse: nil, clean: false}
+	s := NewHTTPServer("{port}", false, "", workingDirectory, mocker, *logger)
+
+	s.addNewMock(w, req)
+
+	res, body := geResultResponse(w, t)
+
+	expected := internal.MockedRequest{
+		MockedRequestLight: internal.MockedRequestLight{
+			MockedRequestHeader: internal.MockedRequestHeader{
+				Status:      200,
+				ContentType: "text/plain",
+				Charset:     "UTF-8",
+				Headers:     map[string]string{},
+			},
+		},
+		Body64: []byte("Hello World"),
+	}
+
+	if res.Status != "200 OK" ||
+		string(body) != `{"_links":{"raw":"http://localhost:3333/v1/raw/{id}","self":"http://localhost:3333/v1/{id}"},"id":"{id}"}` ||
+		!mocker.mockResponse.Equals(expected) ||
+		!mocker.clean ||
+		len(s.getRemoteAddr()) != 1 {
+		t.Fatalf(`result: {%v} but expected {%v}`, res, expected)
+	}
+}
+
+// TestAddNewEndpointWithFullReturn calls HTTPServer.addNewMock(http.ResponseWriter, *http.Request),
+// checking for a valid return value.
+func TestAddNewEndpointWithFullReturn(t *testing.T) {
+	s := NewHTTPServer("{port}", false, "", t.TempDir(), internal.NewInMemoryMock(*logger), *logger)
+
+	call := func(URL string) map[string]any {
+		w := httptest.NewRecorder()
+		s.addNewMock(w, httptest.NewRequest(http.MethodPost, URL, strings.NewReader("Hello World")))
+
+		_, body := geResultResponse(w, t)
+		data, _ := jsonsutil.Unmarshal[map[string]any](body)
+		return data
+	}
+
+	if data := call("http://localhost:3333/v1/new?status=200&contentType=text/plain&charset=UTF-8"); data["mock"] != nil {
+		t.Fatalf(`result: {%v} but expected {%v}`, data, "no mock field")
+	}
+
+	data := call("http://localhost:3333/v1/new?status=201&contentType=text/plain&charset=UTF-8&X-Language=golang&return=full")
+	mock, ok := data["mock"].(map[string]any)
+	if !ok || mock["id"] != data["id"] || mock["status"] != float64(201) || mock["body"] != "Hello World" ||
+		mock["createdAt"] == nil || !reflect.DeepEqual(mock["headers"], map[string]any{"X-Language": "golang"}) {
+		t.Fatalf(`result: {%v} but expected {%v}`, data, "the full mock")
+	}
+}
+
+// TestAddNewEndpointWithUUID calls HTTPServer.addNewMock(http.ResponseWriter, *http.Request),
+// checking for a valid return value.
+func TestAddNewEndpointWithUUID(t *testing.T) {
+	s := NewHTTPServer("{port}", false, "", t.TempDir(), internal.NewInMemoryMock(*logger), *logger)
+	id := "3f7a9c2e-5b1d-4e8f-9a6c-0d2b4f6e8a1c"
+
+	tests := []struct {
+		uuid     string
+		status   string
+		expected string
+	}{
+		{id, "200 OK", `"id":"` + id + `"`},
+		{id, "409 Conflict", `{"message": "mocked request {` + id + `} already exists"}`},
+		{"wrong-uuid", "400 Bad Request", `{"message": "mocked request id {wrong-uuid} is malformed"}`},
+	}
+	for _, test := range tests {
+		URL := "http://localhost:3333/v1/new?contentType=text/plain&charset=UTF-8&uuid=" + test.uuid
+		w := httptest.NewRecorder()
+		s.addNewMock(w, httptest.NewRequest(http.MethodPost, URL, strings.NewReader("Hello World")))
+
+		if res, body := geResultResponse(w, t); res.Status != test.status || !strings.Contains(string(body), test.expected) {
+			t.Fatalf(`result: {%v %v} but expected {%v %v}`, res.Status, string(body), test.status, test.expected)
+		}
+	}
+}
+
+// TestAddNewEndpointWithBadRequest calls HTTPServer.addNewMock(http.ResponseWriter, *http.Request),
+// checking for a valid return value.
+func TestAddNewEndpointWithBadRequest(t *testing.T) {
+	req := httptest.NewRequest(http.MethodPost, "/v1/new", strings.NewReader("bad body..."))
+	w := httptest.NewRecorder()
+
+	mocker := &MockerTest{mockResponse: nil}
+	NewHTTPServer("{port}", false, "", workingDirectory, mocker, *logger).addNewMock(w, req)
+
+	res, body := geResultResponse(w, t)
+
+	if res.Status != "500 Internal Server Error" ||
+		string(body) != `{"message": "error to add new mocked response"}` {
+		t.Fatalf(`result: {%v} but expected {%v}`, res, "409")
+	}
+}
+
+// TestAddNewEndpointWithInvalidBody calls HTTPServer.addNewMock(http.ResponseWriter, *http.Request),
+// checking for a valid return value.
+func TestAddNewEndpointWithInvalidBody(t *testing.T) {
+	URL := "http://localhost:3333/v1/new?status=200&contentType=application%2Fjson&charset=UTF-8&validateBody=true"
+	req := httptest.NewRequest(http.MethodPost, URL, strings.NewReader(`{"id": 1,`))
+	w := httptest.NewRecorder()
+
+	mocker := internal.NewInMemoryMock(*logger)
+	NewHTTPServer("{port}", false, "", workingDirectory, mocker, *logger).addNewMock(w, req)
+
+	res, body := geResultResponse(w, t)
+	if res.Status != "409 Conflict" ||
+		string(body) != `{"message": "body is not a valid {application/json} content: unexpected end of JSON input at line 1, column 9"}` {
+		t.Fatalf(`result: {%v} but expected {%v}`, string(body), "409")
+	}
+	if nb, _ := mocker.Count(); nb != 0 {
+		t.Fatalf(`result: {%v} but expected {%v}`, nb, 0)
+	}
+}
+
+// TestAddNewEndpointWithMalformedSequence calls HTTPServer.addNewMock(http.ResponseWriter, *http.Request),
+// checking for a valid return value.
+func TestAddNewEndpointWithMalformedSequence(t *testing.T) {
+	URL := "http://localhost:3333/v1/new?status=200&contentType=text%2Fplain&charset=UTF-8&sequence=true"
+	req := httptest.NewRequest(http.MethodPost, URL, strings.NewReader("[\n  {\"status\": 200},\n  {\"status\" 503}\n]"))
+	w := httptest.NewRecorder()
+
+	mocker := internal.NewInMemoryMock(*logger)
+	NewHTTPServer("{port}", false, "", workingDirectory, mocker, *logger).addNewMock(w, req)
+
+	expected := `{"message": "body is not a valid {application/json} content: ` +
+		`invalid character '5' after object key at line 3, column 13"}`
+	if res, body := geResultResponse(w, t); res.Status != "409 Conflict" || string(body) != expected {
+		t.Fatalf(`result: {%v} but expected {%v}`, string(body), expected)
+	}
+}
+
+// TestAddNewEndpointWithInvalidHeaderName calls HTTPServer.addNewMock(http.ResponseWriter, *http.Request),
+// checking for a valid return value.
+func TestAddNewEndpointWithInvalidHeaderName(t *testing.T) {
+	URL := "http://localhost:3333/v1/new?status=200&contentType=text%2Fplain&charset=UTF-8&X%20Custom=value"
+	req := httptest.NewRequest(http.MethodPost, URL, strings.NewReader("Hello World"))
+	w := httptest.NewRecorder()
+
+	mocker := internal.NewInMemoryMock(*logger)
+	NewHTTPServer("{port}", false, "", workingDirectory, mocker, *logger).addNewMock(w, req)
+
+	res, body := geResultResponse(w, t)
+	if res.Status != "409 Conflict" || string(body) != `{"message": "header {X Custom} is malformed"}` {
+		t.Fatalf(`result: {%v} but expected {%v}`, string(body), "409")
+	}
+	if nb, _ := mocker.Count(); nb != 0 {
+		t.Fatalf(`result: {%v} but expected {%v}`, nb, 0)
+	}
+}
+
+// TestAddNewEndpointWithBase64Body calls HTTPServer.addNewMock(http.ResponseWriter, *http.Request)
+// and HTTPServer.getMockedRequest(http.ResponseWriter, *http.Request),
+// checking for a valid return value.
+func TestAddNewEndpointWithBase64Body(t *testing.T) {
+	png := "iVBORw0KGgoAAAANSUhEUgAAAAEAAAABCAQAAAC1HAwCAAAAC0lEQVR42mNkYAAAAAYAAjCB0C8AAAAASUVORK5CYII="
+	expected, _ := base64.StdEncoding.DecodeString(png)
+
+	s := NewHTTPServer("{port}", false, "", t.TempDir(), internal.NewInMemoryMock(*logger), *logger)
+
+	URL := "http://localhost:3333/v1/new?status=200&contentType=image/png&charset=UTF-8&bodyEncoding=base64"
+	w := httptest.NewRecorder()
+	s.addNewMock(w, httptest.NewRequest(http.MethodPost, URL, strings.NewReader(png)))
+
+	_, body := geResultResponse(w, t)
+	data, _ := jsonsutil.Unmarshal[map[string]any](body)
+
+	w = httptest.NewRecorder()
+	s.getMockedRequest(w, httptest.NewRequest(http.MethodGet, fmt.Sprintf("http://localhost:3333/v1/%s", data["id"]), nil))
+
+	res, body := geResultResponse(w, t)
+	if res.Status != "200 OK" || res.Header.Get("Content-Type") != "image/png" || !bytes.Equal(body, expected) {
+		t.Fatalf(`result: {%v} but expected {%v}`, body, expected)
+	}
+}
+
+// TestAddNewEndpointWithOctetStreamBody calls HTTPServer.addNewMock(http.ResponseWriter, *http.Request)
+// and HTTPServer.getMockedRequest(http.ResponseWriter, *http.Request),
+// checking for a valid return value.
+func TestAddNewEndpointWithOctetStreamBody(t *testing.T) {
+	expected := []byte{0x00, 0xff, 0x81, 0xa2, 0x69, 0x64, 0x01, 0x0a, 0x0d}
+
+	s := NewHTTPServer("{port}", false, "", t.TempDir(), internal.NewInMemoryMock(*logger), *logger)
+
+	URL := "http://localhost:3333/v1/new?status=200&contentType=application/octet-stream&bodyEncoding=base64"
+	w := httptest.NewRecorder()
+	s.addNewMock(w, httptest.NewRequest(http.MethodPost, URL, strings.NewReader(base64.StdEncoding.EncodeToString(expected))))
+
+	res, body := geResultResponse(w, t)
+	if res.Status != "200 OK" {
+		t.Fatalf(`result: {%v} but expected {%v}`, string(body), "200")
+	}
+	data, _ := jsonsutil.Unmarshal[map[string]any](body)
+
+	w = httptest.NewRecorder()
+	s.getMockedRequest(w, httptest.NewRequest(http.MethodGet, fmt.Sprintf("http://localhost:3333/v1/%s", data["id"]), nil))
 
-	res, data := geResultResponse(w, t)
+	res, body = geResultResponse(w, t)
+	if res.Status != "200 OK" || res.Header.Get("Content-Type") != "application/octet-stream" || !bytes.Equal(body, expected) {
+		t.Fatalf(`result: {%v} but expected {%v}`, body, expected)
+	}
+}
 
-	if res.Status != "200 OK" ||
-		string(data) != `{"status":200,"contentType":"text/plain","charset":"UTF-8","headers":{"x-language":"golang"},"body":"Hello World","body64":"SGVsbG8gV29ybGQ="}` {
+// TestAddNewEndpointWithProtobufBody calls HTTPServer.addNewMock(http.ResponseWriter, *http.Request)
+// and HTTPServer.getMockedRequest(http.ResponseWriter, *http.Request),
+// checking for a valid return value.
+func TestAddNewEndpointWithProtobufBody(t *testing.T) {
+	// message { int32 id = 1; string name = 2; } with id=150 and name="mockapic" (+ invalid UTF-8 bytes)
+	expected := []byte{0x08, 0x96, 0x01, 0x12, 0x08, 0x6d, 0x6f, 0x63, 0x6b, 0x61, 0x70, 0x69, 0x63, 0xff, 0xfe, 0x00}
 
-		t.Fatalf(`result: {%v} but expected {%v}`, res, mocker.mockResponse)
+	s := NewHTTPServer("{port}", false, "", t.TempDir(), internal.NewInMemoryMock(*logger), *logger)
+
+	URL := "http://localhost:3333/v1/new?status=200&contentType=application%2Fx-protobuf&bodyEncoding=base64"
+	w := httptest.NewRecorder()
+	s.addNewMock(w, httptest.NewRequest(http.MethodPost, URL, strings.NewReader(base64.StdEncoding.EncodeToString(expected))))
+
+	res, body := geResultResponse(w, t)
+	if res.Status != "200 OK" {
+		t.Fatalf(`result: {%v} but expected {%v}`, string(body), "200")
 	}
-}
+	data, _ := jsonsutil.Unmarshal[map[string]any](body)
 
-// ##
-// #### ~/v1/list endpoint
-// ##
+	w = httptest.NewRecorder()
+	s.getMockedRequest(w, httptest.NewRequest(http.MethodGet, fmt.Sprintf("http://localhost:3333/v1/%s", data["id"]), nil))
 
-// TestListEndpointWithError calls HTTPServer.list(http.ResponseWriter, *http.Request),
+	res, body = geResultResponse(w, t)
+	if res.Status != "200 OK" || res.Header.Get("Content-Type") != "application/x-protobuf" || !bytes.Equal(body, expected) {
+		t.Fatalf(`result: {%v} but expected {%v}`, body, expected)
+	}
+	if slices.Contains(pkg.IS_DISPLAY_CONTENT, "application/x-protobuf") {
+		t.Fatalf(`result: {%v} but expected {%v}`, pkg.IS_DISPLAY_CONTENT, "not a display content")
+	}
+}
+
+// TestAddNewEndpointWithTooLargeBody calls HTTPServer.addNewMock(http.ResponseWriter, *http.Request),
 // checking for a valid return value.
-func TestListEndpointWithError(t *testing.T) {
-	req := httptest.NewRequest(http.MethodGet, "http://localhost:3333/v1/list", nil)
+func TestAddNewEndpointWithTooLargeBody(t *testing.T) {
+	URL := "http://localhost:3333/v1/new?status=200&contentType=text/plain&charset=UTF-8"
+	req := httptest.NewRequest(http.MethodPost, URL, strings.NewReader(strings.Repeat("a", 11)))
 	w := httptest.NewRecorder()
 
-	NewHTTPServer("{port}", false, "", workingDirectory, &MockerTest{}, *logger).list(w, req)
+	mocker := &MockerTest{mockResponse: nil}
+	NewHTTPServer("{port}", false, "", workingDirectory, mocker, *logger, WithMaxBodySize(10)).addNewMock(w, req)
 
 	res, body := geResultResponse(w, t)
-	if res.Status != "500 Internal Server Error" || string(body) != `{"message": "error to list mocked responses"}` {
-		t.Fatalf(`result: {%v} but expected {%v}`, res, "409")
+
+	if res.Status != "413 Request Entity Too Large" ||
+		string(body) != `{"message": "body exceeds the max size of {10} bytes"}` ||
+		mocker.mockResponse != nil {
+		t.Fatalf(`result: {%v} but expected {%v}`, string(body), "413")
 	}
 }
 
-// TestListEndpoint calls HTTPServer.list(http.ResponseWriter, *http.Request),
+// ##
+// #### ~/v1/ns/{namespace}/* endpoint
+// ##
+
+// TestDispatchNamespace calls HTTPServer.dispatchNamespace(http.ResponseWriter, *http.Request),
 // checking for a valid return value.
-func TestListEndpoint(t *testing.T) {
-	req := httptest.NewRequest(http.MethodGet, "http://localhost:3333/v1/list", nil)
-	w := httptest.NewRecorder()
+func TestDispatchNamespace(t *testing.T) {
+	handler := NewHTTPServer("{port}", false, "", t.TempDir(), internal.NewInMemoryMock(*logger), *logger).handler()
 
-	mocker := &MockerTest{
-		mockResponseLights: []internal.MockedRequestLight{
-			{
-				MockedRequestHeader: internal.MockedRequestHeader{
-					Status:      200,
-					ContentType: "text/plain",
-					Charset:     "UTF-8",
-					Headers:     map[string]string{"x-language": "golang"},
-				},
-				Id: "{id-200}",
-			},
-			{
-				MockedRequestHeader: internal.MockedRequestHeader{
-					Status:      404,
-					ContentType: "application/json",
-					Charset:     "UTF-8",
-					Headers:     map[string]string{"x-language": "golang"},
-				},
-				Id: "{id-404}",
-			},
-		},
+	call := func(method, path string) (http.Response, []byte) {
+		w := httptest.NewRecorder()
+		handler.ServeHTTP(w, httptest.NewRequest(method, "http://localhost:3333"+path, strings.NewReader("Hello World")))
+		return geResultResponse(w, t)
 	}
-	NewHTTPServer("{port}", false, "", workingDirectory, mocker, *logger).list(w, req)
 
-	res, body := geResultResponse(w, t)
+	_, body := call(http.MethodPost, "/v1/ns/team-a/new?contentType=text%2Fplain&charset=UTF-8")
+	created, _ := jsonsutil.Unmarshal[map[string]any](body)
+	id := created["id"].(string)
+	if links := created["_links"].(map[string]any); links["self"] != "http://localhost:3333/v1/ns/team-a/"+id {
+		t.Fatalf(`result: {%v} but expected {%v}`, links, "http://localhost:3333/v1/ns/team-a/"+id)
+	}
 
-	expected := `[{"id":"{id-200}","status":200,"contentType":"text/plain","charset":"UTF-8","headers":{"x-language":"golang"},"_links":{"raw":"http://localhost:3333/v1/raw/{id-200}","self":"http://localhost:3333/v1/{id-200}"}},{"id":"{id-404}","status":404,"contentType":"application/json","charset":"UTF-8","headers":{"x-language":"golang"},"_links":{"raw":"http://localhost:3333/v1/raw/{id-404}","self":"http://localhost:3333/v1/{id-404}"}}]`
-	if res.Status != "200 OK" || string(body) != expected {
-		t.Fatalf(`result: {%v} but expected {%v}`, res, mocker)
+	if res, body := call(http.MethodGet, "/v1/ns/team-a/"+id); res.StatusCode != 200 || string(body) != "Hello World" {
+		t.Fatalf(`result: {%v} but expected {%v}`, string(body), "Hello World")
+	}
+
+	for path, expected := range map[string]int{"/v1/ns/team-a/list": 1, "/v1/ns/team-b/list": 0, "/v1/list": 0} {
+		_, body := call(http.MethodGet, path)
+		if data, _ := jsonsutil.Unmarshal[[]MockedRequestLightWithLinks](body); len(data) != expected {
+			t.Fatalf(`%s - result: {%v} but expected {%v}`, path, len(data), expected)
+		}
+	}
+
+	if res, _ := call(http.MethodGet, "/v1/"+id); res.StatusCode != 404 {
+		t.Fatalf(`result: {%v} but expected {%v}`, res.StatusCode, 404)
+	}
+
+	// test if a namespace cannot be nested
+	if res, _ := call(http.MethodGet, "/v1/ns/team-a/ns/team-b/list"); res.StatusCode != 404 {
+		t.Fatalf(`result: {%v} but expected {%v}`, res.StatusCode, 404)
+	}
+
+	if res, body := call(http.MethodGet, "/v1/ns/team%20a/list"); res.StatusCode != 400 ||
+		string(body) != `{"message": "namespace {team a} is malformed"}` {
+		t.Fatalf(`result: {%v} but expected {%v}`, string(body), "namespace is malformed")
 	}
 }
 
-// TestListEndpointReturnsEmptyNilInsteadOfNull calls HTTPServer.list(http.ResponseWriter, *http.Request),
+// ##
+// #### ~/v1/validate endpoint
+// ##
+
+// TestValidateEndpoint calls HTTPServer.validateMock(http.ResponseWriter, *http.Request),
 // checking for a valid return value.
-func TestListEndpointReturnsEmptyNilInsteadOfNull(t *testing.T) {
-	req := httptest.NewRequest(http.MethodGet, "http://localhost:3333/v1/list", nil)
-	w := httptest.NewRecorder()
+func TestValidateEndpoint(t *testing.T) {
+	mocker := internal.NewInMemoryMock(*logger)
+	s := NewHTTPServer("{port}", false, "", workingDirectory, mocker, *logger, WithMaxBodySize(20))
+
+	tests := []struct {
+		query    string
+		body     string
+		status   int
+		expected string
+	}{
+		{"status=200&contentType=text%2Fplain&charset=UTF-8", "Hello World", 200, `{"valid":true}`},
+		{"status=999&contentType=text%2Fplain&charset=UTF-8", "Hello World", 409,
+			`{"message": "status {999} does not exist (nearest supported: 508, 510, 511)"}`},
+		{"status=200&contentType=wrong&charset=UTF-8", "Hello World", 409, `{"message": "content type {wrong} does not exist"}`},
+		{"status=200&contentType=text%2Fplain&charset=wrong", "Hello World", 409, `{"message": "charset {wrong} does not exist"}`},
+		{"status=200&contentType=text%2Fplain&charset=UTF-8", strings.Repeat("a", 21), 413,
+			`{"message": "body exceeds the max size of {20} bytes"}`},
+	}
 
-	mocker := &MockerTest{
-		mockResponseLights: []internal.MockedRequestLight{},
+	for _, test := range tests {
+		req := httptest.NewRequest(http.MethodPost, "http://localhost:3333/v1/validate?"+test.query, strings.NewReader(test.body))
+		w := httptest.NewRecorder()
+
+		s.validateMock(w, req)
+
+		res, body := geResultResponse(w, t)
+		if res.StatusCode != test.status || string(body) != test.expected {
+			t.Fatalf(`%s - result: {%v} but expected {%v}`, test.query, string(body), test.expected)
+		}
 	}
-	NewHTTPServer("{port}", false, "", workingDirectory, mocker, *logger).list(w, req)
+
+	if nb, _ := mocker.Count(); nb != 0 {
+		t.Fatalf(`result: {%v} but expected {%v}`, nb, 0)
+	}
+}
+
+// ##
+// #### ~/v1/delete-batch endpoint
+// ##
+
+// TestDeleteBatchEndpoint calls HTTPServer.deleteBatch(http.ResponseWriter, *http.Request),
+// checking for a valid return value.
+func TestDeleteBatchEndpoint(t *testing.T) {
+	mocker := &MockerTest{mockResponseLights: []internal.MockedRequestLight{{Id: "{id-1}"}, {Id: "{id-2}"}}}
+	req := httptest.NewRequest(http.MethodPost, "http://localhost:3333/v1/delete-batch", strings.NewReader(`["{id-1}", "{id-2}", "{id-3}"]`))
+	w := httptest.NewRecorder()
+
+	NewHTTPServer("{port}", false, "", workingDirectory, mocker, *logger).deleteBatch(w, req)
 
 	res, body := geResultResponse(w, t)
+	expected := `{"deleted":2,"errors":{"{id-3}":"mocked request does not exist"}}`
+	if res.Status != "200 OK" || string(body) != expected {
+		t.Fatalf(`result: {%v} but expected {%v}`, string(body), expected)
+	}
 
-	if res.Status != "200 OK" || string(body) != "[]" {
-		t.Fatalf(`result: {%v} but expected {%v}`, string(body), "[]")
+	// test if the body is not a JSON array
+	req = httptest.NewRequest(http.MethodPost, "http://localhost:3333/v1/delete-batch", strings.NewReader(`{id-1}`))
+	w = httptest.NewRecorder()
+
+	NewHTTPServer("{port}", false, "", workingDirectory, mocker, *logger).deleteBatch(w, req)
+
+	res, body = geResultResponse(w, t)
+	if res.Status != "400 Bad Request" || string(body) != `{"message": "body must be a JSON array of ids"}` {
+		t.Fatalf(`result: {%v} but expected {%v}`, string(body), "400")
 	}
 }
 
 // ##
-// #### ~/v1/new endpoint
+// #### ~/v1/all endpoint
 // ##
 
-// TestAddNewEndpoint calls HTTPServer.addNewMock(http.ResponseWriter, *http.Request),
+// TestDeleteAllEndpoint calls HTTPServer.deleteAll(http.ResponseWriter, *http.Request),
 // checking for a valid return value.
-func TestAddNewEndpoint(t *testing.T) {
-	internal.MOCKAPIC_REQ_MAX_LIMIT = 100
-	err := iosutil.Write([]byte(``), workingDirectory+"/remote-addr.json")
-	if err != nil {
-		t.Errorf("Error: %v", err)
+func TestDeleteAllEndpoint(t *testing.T) {
+	handler := NewHTTPServer("{port}", false, "", t.TempDir(), internal.NewInMemoryMock(*logger), *logger).handler()
+
+	call := func(method, path string) (http.Response, []byte) {
+		w := httptest.NewRecorder()
+		handler.ServeHTTP(w, httptest.NewRequest(method, "http://localhost:3333"+path, strings.NewReader("Hello World")))
+		return geResultResponse(w, t)
 	}
 
-	URL := "http://localhost:3333/v1/new?status=200&contentType=text/plain&charset=UTF-8"
-	req := httptest.NewRequest(http.MethodPost, URL, strings.NewReader("Hello World"))
-	w := httptest.NewRecorder()
+	for _, path := range []string{"/v1", "/v1", "/v1", "/v1/ns/team-a"} {
+		call(http.MethodPost, path+"/new?contentType=text%2Fplain&charset=UTF-8")
+	}
 
-	mocker := &MockerTest{mockResponse: nil, clean: false}
-	s := NewHTTPServer("{port}", false, "", workingDirectory, mocker, *logger)
+	if res, body := call(http.MethodDelete, "/v1/all"); res.StatusCode != 400 ||
+		string(body) != `{"message": "parameter {confirm=true} is required to delete all the mocked requests"}` {
+		t.Fatalf(`result: {%v} but expected {%v}`, string(body), "400")
+	}
 
-	s.addNewMock(w, req)
+	if res, body := call(http.MethodDelete, "/v1/all?confirm=true"); res.StatusCode != 200 || string(body) != `{"deleted":3}` {
+		t.Fatalf(`result: {%v} but expected {%v}`, string(body), `{"deleted":3}`)
+	}
+
+	for path, expected := range map[string]int{"/v1/list": 0, "/v1/ns/team-a/list": 1} {
+		_, body := call(http.MethodGet, path)
+		if data, _ := jsonsutil.Unmarshal[[]MockedRequestLightWithLinks](body); len(data) != expected {
+			t.Fatalf(`%s - result: {%v} but expected {%v}`, path, len(data), expected)
+		}
+	}
+
+	if _, body := call(http.MethodDelete, "/v1/ns/team-a/all?confirm=true"); string(body) != `{"deleted":1}` {
+		t.Fatalf(`result: {%v} but expected {%v}`, string(body), `{"deleted":1}`)
+	}
+}
+
+// ##
+// #### ~/v1/record endpoint
+// ##
+
+// TestRecordEndpoint calls HTTPServer.record(http.ResponseWriter, *http.Request),
+// checking for a valid return value.
+func TestRecordEndpoint(t *testing.T) {
+	target := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json; charset=utf-8")
+		w.Header().Set("X-Language", "golang")
+		w.WriteHeader(202)
+		w.Write([]byte(`{"name": "mockapic"}`))
+	}))
+	defer target.Close()
+
+	mocker := internal.NewInMemoryMock(*logger)
+	req := httptest.NewRequest(http.MethodPost, "http://localhost:3333/v1/record?url="+url.QueryEscape(target.URL+"/api"), nil)
+	w := httptest.NewRecorder()
+
+	NewHTTPServer("{port}", false, "", t.TempDir(), mocker, *logger, WithPrivateBodyURL(true)).record(w, req)
 
 	res, body := geResultResponse(w, t)
 
 	expected := internal.MockedRequest{
 		MockedRequestLight: internal.MockedRequestLight{
 			MockedRequestHeader: internal.MockedRequestHeader{
-				Status:      200,
-				ContentType: "text/plain",
+				Status:      202,
+				ContentType: "application/json",
 				Charset:     "UTF-8",
-				Headers:     map[string]string{},
+				Headers:     map[string]string{"X-Language": "golang"},
 			},
 		},
-		Body64: []byte("Hello World"),
+		Body64: []byte(`{"name": "mockapic"}`),
 	}
+	data, _ := jsonsutil.Unmarshal[map[string]any](body)
+	if mock, err := mocker.Get(fmt.Sprint(data["id"])); res.Status != "200 OK" || err != nil || !mock.Equals(expected) {
+		t.Fatalf(`result: {%v} but expected {%v}`, string(body), expected)
+	}
+}
 
-	if res.Status != "200 OK" ||
-		string(body) != `{"_links":{"raw":"http://localhost:3333/v1/raw/{id}","self":"http://localhost:3333/v1/{id}"},"id":"{id}"}` ||
-		!mocker.mockResponse.Equals(expected) ||
-		!mocker.clean ||
-		len(s.getRemoteAddr()) != 1 {
-		t.Fatalf(`result: {%v} but expected {%v}`, res, expected)
+// TestRecordEndpointWithPrivateOrUnsupportedResponse calls HTTPServer.record(http.ResponseWriter, *http.Request),
+// checking for a valid return value.
+func TestRecordEndpointWithPrivateOrUnsupportedResponse(t *testing.T) {
+	target := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "text/plain; charset=utf-8")
+		w.WriteHeader(999)
+	}))
+	defer target.Close()
+	targetURL := "http://localhost:3333/v1/record?url=" + url.QueryEscape(target.URL)
+
+	// test if the loopback address is rejected by default
+	w := httptest.NewRecorder()
+	NewHTTPServer("{port}", false, "", t.TempDir(), &MockerTest{}, *logger).record(w, httptest.NewRequest(http.MethodPost, targetURL, nil))
+	if w.Code != 502 {
+		t.Fatalf(`result: {%v} but expected {%v}`, w.Code, 502)
+	}
+
+	// test if a status which is not supported is rejected
+	w = httptest.NewRecorder()
+	NewHTTPServer("{port}", false, "", t.TempDir(), internal.NewInMemoryMock(*logger), *logger, WithPrivateBodyURL(true)).
+		record(w, httptest.NewRequest(http.MethodPost, targetURL, nil))
+	if res, body := geResultResponse(w, t); res.StatusCode != 409 || !strings.Contains(string(body), "status {999} does not exist") {
+		t.Fatalf(`result: {%v} but expected {%v}`, string(body), 409)
 	}
 }
 
-// TestAddNewEndpointWithBadRequest calls HTTPServer.addNewMock(http.ResponseWriter, *http.Request),
+// TestRecordEndpointWithBadRequest calls HTTPServer.record(http.ResponseWriter, *http.Request),
 // checking for a valid return value.
-func TestAddNewEndpointWithBadRequest(t *testing.T) {
-	req := httptest.NewRequest(http.MethodPost, "/v1/new", strings.NewReader("bad body..."))
+func TestRecordEndpointWithBadRequest(t *testing.T) {
+	tests := []struct {
+		url      string
+		status   string
+		expected string
+	}{
+		{"", "400 Bad Request", `{"message": "url {} must be an absolute http(s) URL"}`},
+		{"file:///etc/passwd", "400 Bad Request", `{"message": "url {file:///etc/passwd} must be an absolute http(s) URL"}`},
+		{"http://localhost:1", "502 Bad Gateway", `{"message": "url {http://localhost:1} cannot be recorded"}`},
+	}
+
+	for _, test := range tests {
+		req := httptest.NewRequest(http.MethodPost, "http://localhost:3333/v1/record?url="+url.QueryEscape(test.url), nil)
+		w := httptest.NewRecorder()
+
+		NewHTTPServer("{port}", false, "", t.TempDir(), &MockerTest{}, *logger).record(w, req)
+
+		res, body := geResultResponse(w, t)
+		if res.Status != test.status || string(body) != test.expected {
+			t.Fatalf(`result: {%v} but expected {%v}`, string(body), test.expected)
+		}
+	}
+}
+
+// ##
+// #### ~/v1/import/openapi endpoint
+// ##
+
+// TestImportOpenAPIEndpoint calls HTTPServer.importOpenAPI(http.ResponseWriter, *http.Request),
+// checking for a valid return value.
+func TestImportOpenAPIEndpoint(t *testing.T) {
+	spec := `{"paths": {"/ping": {"get": {"responses": {"200": {"content": {"text/plain": {"example": "pong"}}}}}}}}`
+
+	mocker := internal.NewInMemoryMock(*logger)
+	req := httptest.NewRequest(http.MethodPost, "http://localhost:3333/v1/import/openapi", strings.NewReader(spec))
 	w := httptest.NewRecorder()
 
-	mocker := &MockerTest{mockResponse: nil}
-	NewHTTPServer("{port}", false, "", workingDirectory, mocker, *logger).addNewMock(w, req)
+	NewHTTPServer("{port}", false, "", t.TempDir(), mocker, *logger).importOpenAPI(w, req)
 
 	res, body := geResultResponse(w, t)
+	data, _ := jsonsutil.Unmarshal[[]internal.ImportedOperation](body)
 
-	if res.Status != "500 Internal Server Error" ||
-		string(body) != `{"message": "error to add new mocked response"}` {
-		t.Fatalf(`result: {%v} but expected {%v}`, res, "409")
+	if res.Status != "200 OK" || len(data) != 1 || data[0].Id == "" || data[0].Path != "/ping" {
+		t.Fatalf(`result: {%v} but expected {%v}`, string(body), "/ping")
+	}
+	if r, _ := mocker.Count(); r != 1 {
+		t.Fatalf(`result: {%v} but expected {%v}`, r, 1)
+	}
+
+	// test if the spec is malformed
+	req = httptest.NewRequest(http.MethodPost, "http://localhost:3333/v1/import/openapi", strings.NewReader("bad spec..."))
+	w = httptest.NewRecorder()
+
+	NewHTTPServer("{port}", false, "", t.TempDir(), mocker, *logger).importOpenAPI(w, req)
+
+	res, body = geResultResponse(w, t)
+	if res.Status != "400 Bad Request" ||
+		string(body) != `{"message": "spec must be a valid OpenAPI 3 JSON document"}` {
+		t.Fatalf(`result: {%v} but expected {%v}`, string(body), "400")
+	}
+}
+
+// ##
+// #### ~/v1/export/postman endpoint
+// ##
+
+// TestExportPostmanEndpoint calls HTTPServer.exportPostman(http.ResponseWriter, *http.Request),
+// checking for a valid return value.
+func TestExportPostmanEndpoint(t *testing.T) {
+	mocker := internal.NewInMemoryMock(*logger)
+	for i := 0; i < 2; i++ {
+		mocker.New(map[string][]string{"contentType": {"text/plain"}, "charset": {"UTF-8"}}, nil)
+	}
+
+	req := httptest.NewRequest(http.MethodGet, "http://localhost:3333/v1/export/postman", nil)
+	w := httptest.NewRecorder()
+
+	NewHTTPServer("{port}", false, "", workingDirectory, mocker, *logger).exportPostman(w, req)
+
+	res, body := geResultResponse(w, t)
+	collection, _ := jsonsutil.Unmarshal[internal.PostmanCollection](body)
+
+	if res.Status != "200 OK" || len(collection.Item) != 2 ||
+		!strings.HasPrefix(collection.Item[0].Request.URL.Raw, "http://localhost:3333/v1/") {
+		t.Fatalf(`result: {%v} but expected {%v}`, string(body), 2)
+	}
+
+	// test if Mocker.List returns an error
+	w = httptest.NewRecorder()
+	NewHTTPServer("{port}", false, "", workingDirectory, &MockerTest{}, *logger).exportPostman(w, req)
+
+	if res, _ := geResultResponse(w, t); res.Status != "500 Internal Server Error" {
+		t.Fatalf(`result: {%v} but expected {%v}`, res.Status, "500")
+	}
+}
+
+// ##
+// #### ~/v1/export/jsonl and ~/v1/import/jsonl endpoints
+// ##
+
+// TestExportImportJSONLEndpoints calls HTTPServer.exportJSONL(http.ResponseWriter, *http.Request)
+// and HTTPServer.importJSONL(http.ResponseWriter, *http.Request), checking for a valid return value.
+func TestExportImportJSONLEndpoints(t *testing.T) {
+	source := internal.NewMock(t.TempDir(), nil, *logger)
+	for _, body := range []string{"Hello", "World", ""} {
+		source.New(map[string][]string{"contentType": {"text/plain"}, "charset": {"UTF-8"}}, []byte(body))
+	}
+
+	w := httptest.NewRecorder()
+	NewHTTPServer("{port}", false, "", t.TempDir(), source, *logger).
+		exportJSONL(w, httptest.NewRequest(http.MethodGet, "http://localhost:3333/v1/export/jsonl", nil))
+
+	res, exported := geResultResponse(w, t)
+	if res.Status != "200 OK" || res.Header.Get("Content-Type") != "application/x-ndjson" || bytes.Count(exported, []byte("\n")) != 3 {
+		t.Fatalf(`result: {%v} but expected {%v}`, string(exported), "3 lines")
+	}
+
+	target := internal.NewMock(t.TempDir(), nil, *logger)
+	w = httptest.NewRecorder()
+	NewHTTPServer("{port}", false, "", t.TempDir(), target, *logger).
+		importJSONL(w, httptest.NewRequest(http.MethodPost, "http://localhost:3333/v1/import/jsonl", bytes.NewReader(exported)))
+
+	res, body := geResultResponse(w, t)
+	if res.Status != "200 OK" || string(body) != `{"created":3}` {
+		t.Fatalf(`result: {%v} but expected {%v}`, string(body), `{"created":3}`)
+	}
+	sourceList, _ := source.List()
+	targetList, _ := target.List()
+	if !reflect.DeepEqual(sourceList, targetList) {
+		t.Fatalf(`result: {%v} but expected {%v}`, targetList, sourceList)
+	}
+
+	// test if a malformed line is rejected
+	w = httptest.NewRecorder()
+	NewHTTPServer("{port}", false, "", t.TempDir(), target, *logger).
+		importJSONL(w, httptest.NewRequest(http.MethodPost, "http://localhost:3333/v1/import/jsonl", strings.NewReader("wrong")))
+
+	if res, body := geResultResponse(w, t); res.Status != "400 Bad Request" ||
+		string(body) != `{"message": "line[1]: line must be a JSON mocked request"}` {
+		t.Fatalf(`result: {%v} but expected {%v}`, string(body), "400")
+	}
+
+	// test if the whole stream is limited
+	w = httptest.NewRecorder()
+	NewHTTPServer("{port}", false, "", t.TempDir(), target, *logger, WithMaxBodySize(10)).
+		importJSONL(w, httptest.NewRequest(http.MethodPost, "http://localhost:3333/v1/import/jsonl", strings.NewReader(strings.Repeat("\n", 101))))
+
+	if res, body := geResultResponse(w, t); res.StatusCode != 413 ||
+		string(body) != `{"message": "body exceeds the max size of {100} bytes"}` {
+		t.Fatalf(`result: {%v} but expected {%v}`, string(body), "413")
+	}
+
+	// test if Mocker.List returns an error
+	w = httptest.NewRecorder()
+	NewHTTPServer("{port}", false, "", workingDirectory, &MockerTest{}, *logger).
+		exportJSONL(w, httptest.NewRequest(http.MethodGet, "http://localhost:3333/v1/export/jsonl", nil))
+
+	if res, _ := geResultResponse(w, t); res.Status != "500 Internal Server Error" {
+		t.Fatalf(`result: {%v} but expected {%v}`, res.Status, "500")
+	}
+}
+
+// TestCleanBySize calls HTTPServer.cleanBySize(chan struct{}),
+// checking for a valid return value.
+func TestCleanBySize(t *testing.T) {
+	mocker := &MockerTest{cleanBySize: make(chan int64, 1)}
+	s := NewHTTPServer("{port}", false, "", workingDirectory, mocker, *logger, WithStorageQuota(1024, 10*time.Millisecond))
+
+	done := make(chan struct{})
+	defer close(done)
+	go s.cleanBySize(done)
+
+	select {
+	case maxBytes := <-mocker.cleanBySize:
+		if maxBytes != 1024 {
+			t.Fatalf(`result: {%v} but expected {%v}`, maxBytes, 1024)
+		}
+	case <-time.After(time.Second):
+		t.Fatalf(`result: {%v} but expected {%v}`, "timeout", "Mocker.CleanBySize called")
+	}
+}
+
+// TestCleanExpired calls HTTPServer.cleanExpired(chan struct{}),
+// checking for a valid return value.
+func TestCleanExpired(t *testing.T) {
+	mocker := internal.NewInMemoryMock(*logger)
+	if _, err := mocker.New(map[string][]string{
+		"contentType": {"text/plain"}, "charset": {"UTF-8"}, "ttl": {"10ms"},
+	}, nil); err != nil {
+		t.Fatalf(err.Error())
+	}
+	s := NewHTTPServer("{port}", false, "", workingDirectory, mocker, *logger, WithReaperInterval(10*time.Millisecond))
+
+	done := make(chan struct{})
+	defer close(done)
+	go s.cleanExpired(done)
+
+	time.Sleep(100 * time.Millisecond)
+
+	if nb, _ := mocker.Count(); nb != 0 {
+		t.Fatalf(`result: {%v} but expected {%v}`, nb, 0)
 	}
 }
 
@@ -531,3 +2239,47 @@ func geResultResponse(w *httptest.ResponseRecorder, t *testing.T) (http.Response
 
 	return *res, data
 }
+
+// TestWriteError calls writeError(http.ResponseWriter, error, int),
+// checking for a valid return value.
+func TestWriteError(t *testing.T) {
+	w := httptest.NewRecorder()
+	writeError(w, errors.New(`invalid character '"' after array element`), 409)
+
+	res, body := geResultResponse(w, t)
+	message, err := jsonsutil.Unmarshal[map[string]string](body)
+	if res.StatusCode != 409 || err != nil || message["message"] != `invalid character '"' after array element` {
+		t.Fatalf(`result: {%v} but expected {%v}`, string(body), `invalid character '"' after array element`)
+	}
+}
+
+// TestGetMockedRequestEndpointWithOversizedBody calls HTTPServer.getMockedRequest(http.ResponseWriter, *http.Request),
+// checking for a valid return value.
+func TestGetMockedRequestEndpointWithOversizedBody(t *testing.T) {
+	mocker := &MockerTest{
+		mockResponse: &internal.MockedRequest{
+			MockedRequestLight: internal.MockedRequestLight{
+				MockedRequestHeader: internal.MockedRequestHeader{
+					Status:      200,
+					ContentType: "text/plain",
+					Charset:     "UTF-8",
+				},
+			},
+			Conditions: []internal.Condition{{BodyContains: "ping", Status: 201}},
+			Body64:     []byte("Hello World"),
+		},
+	}
+	s := NewHTTPServer("{port}", false, "", workingDirectory, mocker, *logger, WithMaxBodySize(10))
+
+	tests := map[string]int{
+		"ping":                 201,
+		"too large body, ping": 200,
+	}
+	for body, expected := range tests {
+		w := httptest.NewRecorder()
+		s.getMockedRequest(w, httptest.NewRequest(http.MethodGet, "http://localhost:3333/v1/{id}", strings.NewReader(body)))
+		if w.Code != expected {
+			t.Fatalf(`%s - result: {%v} but expected {%v}`, body, w.Code, expected)
+		}
+	}
+}
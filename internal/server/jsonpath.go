@@ -0,0 +1,110 @@
+package server
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/joakim-ribier/mockapic/internal"
+)
+
+// parseJSONPath parses a JSONPath {expr} into its segments (string keys and int indexes),
+// only the child operators are supported: "$.data.items", "$.data['items'][0]".
+func parseJSONPath(expr string) ([]any, error) {
+	malformed := fmt.Errorf("jsonpath {%s} is malformed", expr)
+
+	rest, is := strings.CutPrefix(expr, "$")
+	if !is {
+		return nil, malformed
+	}
+
+	segments := []any{}
+	for rest != "" {
+		switch rest[0] {
+		case '.':
+			end := strings.IndexAny(rest[1:], ".[")
+			if end < 0 {
+				end = len(rest) - 1
+			}
+			key := rest[1 : end+1]
+			if key == "" || key == "*" {
+				return nil, malformed
+			}
+			segments = append(segments, key)
+			rest = rest[end+1:]
+		case '[':
+			end := strings.Index(rest, "]")
+			if end < 0 {
+				return nil, malformed
+			}
+			value := rest[1:end]
+			if len(value) >= 2 && (value[0] == '\'' || value[0] == '"') && value[len(value)-1] == value[0] {
+				segments = append(segments, value[1:len(value)-1])
+			} else if index, err := strconv.Atoi(value); err == nil && index >= 0 {
+				segments = append(segments, index)
+			} else {
+				return nil, malformed
+			}
+			rest = rest[end+1:]
+		default:
+			return nil, malformed
+		}
+	}
+	return segments, nil
+}
+
+// evalJSONPath returns the value of the {segments} path in the {value} and false if the path does not exist
+func evalJSONPath(value any, segments []any) (any, bool) {
+	for _, segment := range segments {
+		switch key := segment.(type) {
+		case string:
+			object, is := value.(map[string]any)
+			if !is {
+				return nil, false
+			}
+			if value, is = object[key]; !is {
+				return nil, false
+			}
+		case int:
+			array, is := value.([]any)
+			if !is || key >= len(array) {
+				return nil, false
+			}
+			value = array[key]
+		}
+	}
+	return value, true
+}
+
+// projectJSON replaces the JSON body of the {mock} by the value of the JSONPath {expr}
+func projectJSON(mock *internal.MockedRequest, expr string) error {
+	segments, err := parseJSONPath(expr)
+	if err != nil {
+		return err
+	}
+	if mock.ContentType != "application/json" && mock.ContentType != "text/json" {
+		return fmt.Errorf("jsonpath cannot be applied on the content type {%s}", mock.ContentType)
+	}
+
+	decoder := json.NewDecoder(bytes.NewReader(mock.Body64))
+	decoder.UseNumber()
+	var body any
+	if err := decoder.Decode(&body); err != nil {
+		return errors.New("jsonpath cannot be applied on a malformed JSON body")
+	}
+
+	value, is := evalJSONPath(body, segments)
+	if !is {
+		return fmt.Errorf("jsonpath {%s} does not match the body", expr)
+	}
+
+	data, err := json.Marshal(value)
+	if err != nil {
+		return err
+	}
+	mock.Body64 = data
+	return nil
+}
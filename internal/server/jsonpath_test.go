@@ -0,0 +1,82 @@
+package server
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"reflect"
+	"testing"
+
+	"github.com/joakim-ribier/mockapic/internal"
+)
+
+// TestParseJSONPath calls parseJSONPath(string),
+// checking for a valid return value.
+func TestParseJSONPath(t *testing.T) {
+	tests := map[string][]any{
+		"$":                        {},
+		"$.data.items":             {"data", "items"},
+		"$.data.items[1].name":     {"data", "items", 1, "name"},
+		"$['data'][\"my.key\"][0]": {"data", "my.key", 0},
+	}
+	for expr, expected := range tests {
+		if r, err := parseJSONPath(expr); err != nil || !reflect.DeepEqual(r, expected) {
+			t.Fatalf(`%s - result: {%v} but expected {%v}`, expr, r, expected)
+		}
+	}
+
+	for _, expr := range []string{"", "data.items", "$..items", "$.data[", "$.data[-1]", "$.data[*]", "$.*"} {
+		if r, err := parseJSONPath(expr); err == nil {
+			t.Fatalf(`%s - result: {%v} but expected error`, expr, r)
+		}
+	}
+}
+
+// TestGetMockedRequestEndpointWithJSONPath calls HTTPServer.getMockedRequest(http.ResponseWriter, *http.Request),
+// checking for a valid return value.
+func TestGetMockedRequestEndpointWithJSONPath(t *testing.T) {
+	mocker := internal.NewInMemoryMock(*logger)
+	jsonId, err := mocker.New(map[string][]string{
+		"status":      {"200"},
+		"contentType": {"application/json"},
+		"charset":     {"UTF-8"},
+	}, []byte(`{"data": {"total": 2, "items": [{"id": 1, "name": "mockapic"}, {"id": 2, "name": "gmocky"}]}}`))
+	if err != nil {
+		t.Fatalf(err.Error())
+	}
+	textId, err := mocker.New(map[string][]string{
+		"status":      {"200"},
+		"contentType": {"text/plain"},
+		"charset":     {"UTF-8"},
+	}, []byte("Hello World"))
+	if err != nil {
+		t.Fatalf(err.Error())
+	}
+
+	s := NewHTTPServer("{port}", false, "", workingDirectory, mocker, *logger)
+
+	tests := []struct {
+		id       string
+		jsonpath string
+		status   string
+		expected string
+	}{
+		{*jsonId, "$.data.items[1]", "200 OK", `{"id":2,"name":"gmocky"}`},
+		{*jsonId, "$.data.items[0].name", "200 OK", `"mockapic"`},
+		{*jsonId, "$.data.total", "200 OK", `2`},
+		{*jsonId, "$.data.unknown", "400 Bad Request", `{"message": "jsonpath {$.data.unknown} does not match the body"}`},
+		{*jsonId, "$.data[", "400 Bad Request", `{"message": "jsonpath {$.data[} is malformed"}`},
+		{*textId, "$.data", "400 Bad Request", `{"message": "jsonpath cannot be applied on the content type {text/plain}"}`},
+		{*textId, "", "200 OK", `Hello World`},
+	}
+	for _, test := range tests {
+		req := httptest.NewRequest(http.MethodGet, "http://localhost:3333/v1/"+test.id+"?jsonpath="+url.QueryEscape(test.jsonpath), nil)
+		req.SetPathValue("id", test.id)
+		w := httptest.NewRecorder()
+		s.getMockedRequest(w, req)
+
+		if res, body := geResultResponse(w, t); res.Status != test.status || string(body) != test.expected {
+			t.Fatalf(`%s - result: {%v %s} but expected {%v %v}`, test.jsonpath, res.Status, string(body), test.status, test.expected)
+		}
+	}
+}
@@ -0,0 +1,134 @@
+package server
+
+import (
+	"fmt"
+	"net/http"
+	"slices"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/joakim-ribier/go-utils/pkg/genericsutil"
+)
+
+// metricsBuckets are the upper bounds (in seconds) of the serve duration histogram
+var metricsBuckets = []float64{0.005, 0.01, 0.025, 0.05, 0.1, 0.25, 0.5, 1, 2.5, 5, 10, 30, 60}
+
+// metrics collects the served mocked requests exposed on "/metrics" (Prometheus text format)
+type metrics struct {
+	mu       sync.Mutex
+	requests int64
+	byStatus map[int]int64
+	buckets  []int64
+	sum      float64
+}
+
+func newMetrics() *metrics {
+	return &metrics{
+		byStatus: map[int]int64{},
+		buckets:  make([]int64, len(metricsBuckets)),
+	}
+}
+
+// observe records a served request with its {status} and its {duration} (including the delay)
+func (m *metrics) observe(status int, duration time.Duration) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	m.requests = m.requests + 1
+	m.byStatus[status] = m.byStatus[status] + 1
+	m.sum = m.sum + duration.Seconds()
+	for i, bound := range metricsBuckets {
+		if duration.Seconds() <= bound {
+			m.buckets[i] = m.buckets[i] + 1
+		}
+	}
+}
+
+// write writes the collected metrics in the Prometheus text format
+func (m *metrics) write(w *strings.Builder) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	fmt.Fprintln(w, "# HELP mockapic_requests_total Total number of served mocked requests.")
+	fmt.Fprintln(w, "# TYPE mockapic_requests_total counter")
+	fmt.Fprintf(w, "mockapic_requests_total %d\n", m.requests)
+
+	fmt.Fprintln(w, "# HELP mockapic_responses_total Number of served mocked requests by status code.")
+	fmt.Fprintln(w, "# TYPE mockapic_responses_total counter")
+	codes := make([]int, 0, len(m.byStatus))
+	for code := range m.byStatus {
+		codes = append(codes, code)
+	}
+	slices.Sort(codes)
+	for _, code := range codes {
+		fmt.Fprintf(w, "mockapic_responses_total{code=\"%d\"} %d\n", code, m.byStatus[code])
+	}
+
+	fmt.Fprintln(w, "# HELP mockapic_serve_duration_seconds Duration of the served mocked requests (including the delay).")
+	fmt.Fprintln(w, "# TYPE mockapic_serve_duration_seconds histogram")
+	for i, bound := range metricsBuckets {
+		fmt.Fprintf(w, "mockapic_serve_duration_seconds_bucket{le=\"%s\"} %d\n",
+			strconv.FormatFloat(bound, 'g', -1, 64), m.buckets[i])
+	}
+	fmt.Fprintf(w, "mockapic_serve_duration_seconds_bucket{le=\"+Inf\"} %d\n", m.requests)
+	fmt.Fprintf(w, "mockapic_serve_duration_seconds_sum %s\n", strconv.FormatFloat(m.sum, 'g', -1, 64))
+	fmt.Fprintf(w, "mockapic_serve_duration_seconds_count %d\n", m.requests)
+}
+
+// metricsWriter records the status of the response written by a handler
+type metricsWriter struct {
+	http.ResponseWriter
+	status int
+}
+
+func (w *metricsWriter) WriteHeader(statusCode int) {
+	if w.status == 0 {
+		w.status = statusCode
+	}
+	w.ResponseWriter.WriteHeader(statusCode)
+}
+
+func (w *metricsWriter) Write(data []byte) (int, error) {
+	if w.status == 0 {
+		w.status = 200
+	}
+	return w.ResponseWriter.Write(data)
+}
+
+func (w *metricsWriter) recordStatus(statusCode int) {
+	if w.status == 0 {
+		w.status = statusCode
+	}
+}
+
+// Unwrap returns the underlying {http.ResponseWriter} (used by {http.ResponseController})
+func (w *metricsWriter) Unwrap() http.ResponseWriter {
+	return w.ResponseWriter
+}
+
+// measure records the status and the duration of each request served by {handle} (if the metrics are enabled)
+func (s HTTPServer) measure(handle func(w http.ResponseWriter, r *http.Request)) func(w http.ResponseWriter, r *http.Request) {
+	if s.metrics == nil {
+		return handle
+	}
+
+	return func(w http.ResponseWriter, r *http.Request) {
+		start := time.Now()
+		mw := &metricsWriter{ResponseWriter: w}
+
+		handle(mw, r)
+
+		s.metrics.observe(genericsutil.OrElse(mw.status, func() bool { return mw.status != 0 }, 200), time.Since(start))
+	}
+}
+
+func (s HTTPServer) getMetrics(w http.ResponseWriter, r *http.Request) {
+	var builder strings.Builder
+	s.metrics.write(&builder)
+
+	w.Header().Set("Content-Type", "text/plain; version=0.0.4; charset=utf-8")
+	w.WriteHeader(200)
+	w.Write([]byte(builder.String()))
+}
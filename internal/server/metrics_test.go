@@ -0,0 +1,104 @@
+package server
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/joakim-ribier/mockapic/internal"
+)
+
+// TestGetMetrics calls HTTPServer.getMetrics(http.ResponseWriter, *http.Request),
+// checking for a valid return value.
+func TestGetMetrics(t *testing.T) {
+	mocker := &MockerTest{
+		mockResponse: &internal.MockedRequest{
+			MockedRequestLight: internal.MockedRequestLight{
+				MockedRequestHeader: internal.MockedRequestHeader{
+					Status:      201,
+					ContentType: "text/plain",
+					Charset:     "UTF-8",
+				},
+			},
+			Body64: []byte("Hello World"),
+		},
+	}
+	handler := NewHTTPServer("{port}", false, "", workingDirectory, mocker, *logger, WithMetrics(true)).handler()
+
+	scrape := func() string {
+		w := httptest.NewRecorder()
+		handler.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "http://localhost:3333/metrics", nil))
+		if w.Code != 200 {
+			t.Fatalf(`result: {%v} but expected {%v}`, w.Code, 200)
+		}
+		return w.Body.String()
+	}
+
+	if body := scrape(); !strings.Contains(body, "mockapic_requests_total 0\n") {
+		t.Fatalf(`result: {%v} but expected {%v}`, body, "mockapic_requests_total 0")
+	}
+
+	for range 2 {
+		handler.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(http.MethodGet, "http://localhost:3333/v1/{id}", nil))
+	}
+
+	body := scrape()
+	for _, expected := range []string{
+		"mockapic_requests_total 2\n",
+		"mockapic_responses_total{code=\"201\"} 2\n",
+		"mockapic_serve_duration_seconds_bucket{le=\"+Inf\"} 2\n",
+		"mockapic_serve_duration_seconds_count 2\n",
+	} {
+		if !strings.Contains(body, expected) {
+			t.Fatalf(`result: {%v} but expected {%v}`, body, expected)
+		}
+	}
+}
+
+// TestGetMetricsWhenDisabled calls HTTPServer.handler(),
+// checking for a valid return value.
+func TestGetMetricsWhenDisabled(t *testing.T) {
+	w := httptest.NewRecorder()
+	NewHTTPServer("{port}", false, "", workingDirectory, &MockerTest{}, *logger).handler().
+		ServeHTTP(w, httptest.NewRequest(http.MethodGet, "http://localhost:3333/metrics", nil))
+
+	if w.Code != 404 {
+		t.Fatalf(`result: {%v} but expected {%v}`, w.Code, 404)
+	}
+}
+
+// TestGetMetricsWithStatusText calls HTTPServer.getMetrics(http.ResponseWriter, *http.Request),
+// checking for a valid return value.
+func TestGetMetricsWithStatusText(t *testing.T) {
+	mocker := &MockerTest{
+		mockResponse: &internal.MockedRequest{
+			MockedRequestLight: internal.MockedRequestLight{
+				MockedRequestHeader: internal.MockedRequestHeader{
+					Status:      418,
+					ContentType: "text/plain",
+					Charset:     "UTF-8",
+				},
+			},
+			StatusText: "I Am A Teapot",
+			Body64:     []byte("Hello World"),
+		},
+	}
+	handler := NewHTTPServer("{port}", false, "", workingDirectory, mocker, *logger, WithMetrics(true)).handler()
+
+	// the response is hijacked to write the status text
+	server := httptest.NewServer(handler)
+	defer server.Close()
+
+	resp, err := http.Get(server.URL + "/v1/{id}")
+	if err != nil {
+		t.Fatal(err)
+	}
+	resp.Body.Close()
+
+	w := httptest.NewRecorder()
+	handler.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "http://localhost:3333/metrics", nil))
+	if resp.Status != "418 I Am A Teapot" || !strings.Contains(w.Body.String(), "mockapic_responses_total{code=\"418\"} 1\n") {
+		t.Fatalf(`result: {%v} but expected {%v}`, w.Body.String(), "mockapic_responses_total{code=\"418\"} 1")
+	}
+}
@@ -0,0 +1,70 @@
+package server
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"io"
+	"mime"
+	"mime/multipart"
+	"net/http"
+	"net/url"
+)
+
+// bodyPartName is the name of the multipart/form-data part which carries the body of a new mocked request
+const bodyPartName = "body"
+
+// readNewMock reads the parameters and the body of a new mocked request (see {newMockParams} and {readBody}),
+// a "multipart/form-data" request carries the body in its "body" part (or the first file upload)
+// and the parameters in the other parts (the query parameters take precedence).
+func (s HTTPServer) readNewMock(w http.ResponseWriter, r *http.Request) (url.Values, []byte, bool) {
+	body, ok := s.readBody(w, r)
+	if !ok {
+		return nil, nil, false
+	}
+
+	reqParams := newMockParams(r)
+	mediaType, params, err := mime.ParseMediaType(r.Header.Get("Content-Type"))
+	if err != nil || mediaType != "multipart/form-data" {
+		return reqParams, body, true
+	}
+
+	body, err = parseMultipartMock(body, params["boundary"], reqParams)
+	if err != nil {
+		writeError(w, err, 400)
+		return nil, nil, false
+	}
+	return reqParams, body, true
+}
+
+// parseMultipartMock returns the content of the body part of the multipart {data}
+// and adds the values of the other parts to the {reqParams} (if not already defined).
+func parseMultipartMock(data []byte, boundary string, reqParams url.Values) ([]byte, error) {
+	if boundary == "" {
+		return nil, errors.New("multipart boundary must be defined")
+	}
+
+	var body []byte
+	reader := multipart.NewReader(bytes.NewReader(data), boundary)
+	for {
+		part, err := reader.NextPart()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return nil, fmt.Errorf("multipart body is malformed: %s", err.Error())
+		}
+
+		value, err := io.ReadAll(part)
+		if err != nil {
+			return nil, fmt.Errorf("multipart part {%s} cannot be read", part.FormName())
+		}
+
+		if part.FormName() == bodyPartName || (part.FileName() != "" && body == nil) {
+			body = value
+		} else if part.FormName() != "" && !reqParams.Has(part.FormName()) {
+			reqParams.Set(part.FormName(), string(value))
+		}
+	}
+	return body, nil
+}
@@ -0,0 +1,92 @@
+package server
+
+import (
+	"bytes"
+	"mime/multipart"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/joakim-ribier/go-utils/pkg/jsonsutil"
+	"github.com/joakim-ribier/mockapic/internal"
+)
+
+// newMultipartMock builds a multipart/form-data body with the {fields} and the {file} in the "body" part
+func newMultipartMock(t *testing.T, fields map[string]string, file []byte) (*bytes.Buffer, string) {
+	var buffer bytes.Buffer
+	writer := multipart.NewWriter(&buffer)
+	for name, value := range fields {
+		if err := writer.WriteField(name, value); err != nil {
+			t.Fatalf(err.Error())
+		}
+	}
+	part, err := writer.CreateFormFile("body", "index.html")
+	if err != nil {
+		t.Fatalf(err.Error())
+	}
+	part.Write(file)
+	writer.Close()
+
+	return &buffer, writer.FormDataContentType()
+}
+
+// TestAddNewEndpointWithMultipartBody calls HTTPServer.addNewMock(http.ResponseWriter, *http.Request),
+// checking for a valid return value.
+func TestAddNewEndpointWithMultipartBody(t *testing.T) {
+	mocker := internal.NewInMemoryMock(*logger)
+	s := NewHTTPServer("{port}", false, "", t.TempDir(), mocker, *logger)
+
+	file := []byte("<html><body>" + strings.Repeat("Hello World ", 100) + "</body></html>")
+	data, contentType := newMultipartMock(t, map[string]string{"status": "201", "contentType": "text/html", "charset": "UTF-8"}, file)
+
+	// the query parameters take precedence over the form parameters
+	req := httptest.NewRequest(http.MethodPost, "http://localhost:3333/v1/new?status=202", data)
+	req.Header.Set("Content-Type", contentType)
+	w := httptest.NewRecorder()
+	s.addNewMock(w, req)
+
+	res, body := geResultResponse(w, t)
+	if res.Status != "200 OK" {
+		t.Fatalf(`result: {%v} but expected {%v}`, string(body), "200 OK")
+	}
+
+	result, _ := jsonsutil.Unmarshal[map[string]any](body)
+	mock, err := mocker.Get(result["id"].(string))
+	if err != nil || mock.Status != 202 || mock.ContentType != "text/html" || !bytes.Equal(mock.Body64, file) {
+		t.Fatalf(`result: {%v} but expected {%v}`, mock, string(file))
+	}
+}
+
+// TestAddNewEndpointWithBadMultipartBody calls HTTPServer.addNewMock(http.ResponseWriter, *http.Request),
+// checking for a valid return value.
+func TestAddNewEndpointWithBadMultipartBody(t *testing.T) {
+	file := []byte(strings.Repeat("a", 100))
+	data, contentType := newMultipartMock(t, map[string]string{"status": "200", "contentType": "text/plain", "charset": "UTF-8"}, file)
+
+	tests := []struct {
+		contentType string
+		maxBodySize int64
+		status      string
+	}{
+		{contentType, 100, "413 Request Entity Too Large"},
+		{"multipart/form-data", 10 << 20, "400 Bad Request"},
+		{"multipart/form-data; boundary=wrong", 10 << 20, "400 Bad Request"},
+	}
+	for _, test := range tests {
+		mocker := internal.NewInMemoryMock(*logger)
+		s := NewHTTPServer("{port}", false, "", t.TempDir(), mocker, *logger, WithMaxBodySize(test.maxBodySize))
+
+		req := httptest.NewRequest(http.MethodPost, "http://localhost:3333/v1/new", bytes.NewReader(data.Bytes()))
+		req.Header.Set("Content-Type", test.contentType)
+		w := httptest.NewRecorder()
+		s.addNewMock(w, req)
+
+		if res, body := geResultResponse(w, t); res.Status != test.status {
+			t.Fatalf(`result: {%v %v} but expected {%v}`, res.Status, string(body), test.status)
+		}
+		if nb, _ := mocker.Count(); nb != 0 {
+			t.Fatalf(`result: {%v} but expected {%v}`, nb, 0)
+		}
+	}
+}
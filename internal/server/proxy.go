@@ -0,0 +1,59 @@
+package server
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httputil"
+	"net/url"
+	"time"
+)
+
+// proxyHeader marks the requests forwarded to the proxy target to detect the proxy loops
+const proxyHeader = "X-Mockapic-Proxied"
+
+// proxyTimeout is the max duration of a request forwarded to the proxy target
+const proxyTimeout = 30 * time.Second
+
+// ParseProxyTarget parses the {target} base URL of the requests which do not match any mocked request
+func ParseProxyTarget(target string) (*url.URL, error) {
+	targetURL, err := url.Parse(target)
+	if err != nil || (targetURL.Scheme != "http" && targetURL.Scheme != "https") || targetURL.Host == "" {
+		return nil, fmt.Errorf("url {%s} must be an absolute http(s) URL", target)
+	}
+	return targetURL, nil
+}
+
+// WithProxyTarget forwards the requests which do not match any mocked request to the {target} base URL
+// and returns its response (instead of a 404)
+func WithProxyTarget(target *url.URL) Option {
+	return func(s *HTTPServer) {
+		s.proxyTarget = target
+	}
+}
+
+// proxy forwards the request {r} to the proxy target and relays its response,
+// a request already forwarded by a mockapic server is rejected (508) to break the loops
+func (s HTTPServer) proxy(w http.ResponseWriter, r *http.Request) {
+	if r.Header.Get(proxyHeader) != "" {
+		writeError(w, errors.New("proxy loop detected"), 508)
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(r.Context(), proxyTimeout)
+	defer cancel()
+
+	proxy := &httputil.ReverseProxy{
+		Rewrite: func(pr *httputil.ProxyRequest) {
+			pr.SetURL(s.proxyTarget)
+			pr.SetXForwarded()
+			pr.Out.Header.Set(proxyHeader, "true")
+		},
+		ErrorHandler: func(w http.ResponseWriter, r *http.Request, err error) {
+			s.logger.Error(err, "error to proxy request", "uri", r.RequestURI, "target", s.proxyTarget.String())
+			writeError(w, fmt.Errorf("proxy target {%s} cannot be reached", s.proxyTarget.Host), 502)
+		},
+	}
+	proxy.ServeHTTP(w, r.WithContext(ctx))
+}
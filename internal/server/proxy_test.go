@@ -0,0 +1,75 @@
+package server
+
+import (
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/joakim-ribier/mockapic/internal"
+)
+
+// TestProxy calls HTTPServer.root(http.ResponseWriter, *http.Request),
+// checking for a valid return value.
+func TestProxy(t *testing.T) {
+	upstream := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, _ := io.ReadAll(r.Body)
+		w.Header().Set("X-Upstream", r.Header.Get(proxyHeader))
+		w.WriteHeader(201)
+		w.Write([]byte(r.Method + " " + r.URL.RequestURI() + " " + string(body)))
+	}))
+	defer upstream.Close()
+
+	target, err := ParseProxyTarget(upstream.URL)
+	if err != nil {
+		t.Fatalf(err.Error())
+	}
+
+	mocker := internal.NewInMemoryMock(*logger)
+	mocker.New(map[string][]string{
+		"contentType": {"text/plain"},
+		"charset":     {"UTF-8"},
+		"when.path":   {"/api/mocked"},
+	}, []byte("mocked"))
+
+	handler := NewHTTPServer("{port}", false, "", workingDirectory, mocker, *logger, WithProxyTarget(target)).handler()
+	call := func(req *http.Request) *httptest.ResponseRecorder {
+		w := httptest.NewRecorder()
+		handler.ServeHTTP(w, req)
+		return w
+	}
+
+	// test if the unmatched request is forwarded to the target
+	w := call(httptest.NewRequest(http.MethodPost, "http://localhost:3333/api/users?active=true", nil))
+	if w.Code != 201 || w.Body.String() != "POST /api/users?active=true " || w.Header().Get("X-Upstream") != "true" {
+		t.Fatalf(`result: {%v, %v} but expected {%v}`, w.Code, w.Body.String(), "POST /api/users?active=true")
+	}
+
+	// test if the matched request is still served by the mocked request
+	if w := call(httptest.NewRequest(http.MethodGet, "http://localhost:3333/api/mocked", nil)); w.Code != 200 || w.Body.String() != "mocked" {
+		t.Fatalf(`result: {%v, %v} but expected {%v}`, w.Code, w.Body.String(), "mocked")
+	}
+
+	// test if the proxy loop is detected
+	req := httptest.NewRequest(http.MethodGet, "http://localhost:3333/api/users", nil)
+	req.Header.Set(proxyHeader, "true")
+	if w := call(req); w.Code != 508 {
+		t.Fatalf(`result: {%v} but expected {%v}`, w.Code, 508)
+	}
+
+	// test if the target cannot be reached
+	upstream.Close()
+	if w := call(httptest.NewRequest(http.MethodGet, "http://localhost:3333/api/users", nil)); w.Code != 502 {
+		t.Fatalf(`result: {%v} but expected {%v}`, w.Code, 502)
+	}
+}
+
+// TestParseProxyTarget calls ParseProxyTarget(string),
+// checking for a valid return value.
+func TestParseProxyTarget(t *testing.T) {
+	for _, target := range []string{"", "localhost:8080", "ftp://localhost", "/api"} {
+		if _, err := ParseProxyTarget(target); err == nil {
+			t.Fatalf(`result: {%v} but expected {%v}`, err, "url {"+target+"} must be an absolute http(s) URL")
+		}
+	}
+}
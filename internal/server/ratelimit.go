@@ -0,0 +1,107 @@
+package server
+
+import (
+	"errors"
+	"math"
+	"net/http"
+	"slices"
+	"strconv"
+	"sync"
+	"time"
+)
+
+// rateLimiterMaxBuckets is the number of buckets above which the full buckets are removed,
+// then the least recently used ones (by {rateLimiterEvictedBuckets}) if there are still too many
+const (
+	rateLimiterMaxBuckets     = 10000
+	rateLimiterEvictedBuckets = rateLimiterMaxBuckets / 10
+)
+
+// rateLimiter is a token bucket rate limiter by key (client address)
+type rateLimiter struct {
+	mu      sync.Mutex
+	rate    float64
+	burst   float64
+	buckets map[string]*bucket
+	now     func() time.Time
+}
+
+type bucket struct {
+	tokens float64
+	last   time.Time
+}
+
+// newRateLimiter creates a {rateLimiter} allowing {rate} requests per second with a {burst}
+func newRateLimiter(rate float64, burst int) *rateLimiter {
+	return &rateLimiter{
+		rate:    rate,
+		burst:   math.Max(float64(burst), 1),
+		buckets: map[string]*bucket{},
+		now:     time.Now,
+	}
+}
+
+// allow takes a token from the bucket of the {key},
+// it returns false and the duration to wait for the next token if the bucket is empty
+func (l *rateLimiter) allow(key string) (bool, time.Duration) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	now := l.now()
+	b, is := l.buckets[key]
+	if !is {
+		if len(l.buckets) >= rateLimiterMaxBuckets {
+			l.removeFullBuckets(now)
+		}
+		if len(l.buckets) >= rateLimiterMaxBuckets {
+			l.removeOldestBuckets(rateLimiterEvictedBuckets)
+		}
+		b = &bucket{tokens: l.burst, last: now}
+		l.buckets[key] = b
+	}
+
+	b.tokens = math.Min(l.burst, b.tokens+now.Sub(b.last).Seconds()*l.rate)
+	b.last = now
+
+	if b.tokens < 1 {
+		return false, time.Duration((1 - b.tokens) / l.rate * float64(time.Second))
+	}
+	b.tokens = b.tokens - 1
+	return true, 0
+}
+
+func (l *rateLimiter) removeFullBuckets(now time.Time) {
+	for key, b := range l.buckets {
+		if b.tokens+now.Sub(b.last).Seconds()*l.rate >= l.burst {
+			delete(l.buckets, key)
+		}
+	}
+}
+
+// removeOldestBuckets removes the {nb} least recently used buckets
+func (l *rateLimiter) removeOldestBuckets(nb int) {
+	keys := make([]string, 0, len(l.buckets))
+	for key := range l.buckets {
+		keys = append(keys, key)
+	}
+	slices.SortFunc(keys, func(key1, key2 string) int {
+		return l.buckets[key1].last.Compare(l.buckets[key2].last)
+	})
+	for _, key := range keys[:min(nb, len(keys))] {
+		delete(l.buckets, key)
+	}
+}
+
+// limitRate responds 429 with a "Retry-After" header if the client exceeds the rate limit (if enabled)
+func (s HTTPServer) limitRate(handle func(w http.ResponseWriter, r *http.Request)) func(w http.ResponseWriter, r *http.Request) {
+	return func(w http.ResponseWriter, r *http.Request) {
+		if s.rateLimiter != nil {
+			if allowed, retryAfter := s.rateLimiter.allow(s.findRemoteAddr(r.RemoteAddr)); !allowed {
+				w.Header().Set("Retry-After", strconv.Itoa(int(math.Max(1, math.Ceil(retryAfter.Seconds())))))
+				writeError(w, errors.New("too many requests"), 429)
+				return
+			}
+		}
+		handle(w, r)
+	}
+}
@@ -0,0 +1,101 @@
+package server
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"testing"
+	"time"
+
+	"github.com/joakim-ribier/mockapic/internal"
+)
+
+// TestRateLimiterAllow calls rateLimiter.allow(string),
+// checking for a valid return value.
+func TestRateLimiterAllow(t *testing.T) {
+	now := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	limiter := newRateLimiter(2, 2)
+	limiter.now = func() time.Time { return now }
+
+	for i := 0; i < 2; i++ {
+		if r, _ := limiter.allow("client-1"); !r {
+			t.Fatalf(`result: {%v} but expected {%v}`, r, true)
+		}
+	}
+
+	// test if the bucket is empty
+	if r, retryAfter := limiter.allow("client-1"); r || retryAfter != 500*time.Millisecond {
+		t.Fatalf(`result: {%v, %v} but expected {%v, %v}`, r, retryAfter, false, 500*time.Millisecond)
+	}
+
+	// test if the bucket of another client is not shared
+	if r, _ := limiter.allow("client-2"); !r {
+		t.Fatalf(`result: {%v} but expected {%v}`, r, true)
+	}
+
+	// test if the bucket is refilled
+	now = now.Add(500 * time.Millisecond)
+	if r, _ := limiter.allow("client-1"); !r {
+		t.Fatalf(`result: {%v} but expected {%v}`, r, true)
+	}
+}
+
+// TestRateLimiterAllowWithMaxBuckets calls rateLimiter.allow(string),
+// checking for a valid return value.
+func TestRateLimiterAllowWithMaxBuckets(t *testing.T) {
+	now := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	limiter := newRateLimiter(0.001, 10)
+	limiter.now = func() time.Time { return now }
+
+	// each client spends one token so none of the buckets is full again
+	for i := 0; i < rateLimiterMaxBuckets+1; i++ {
+		limiter.allow("client-" + strconv.Itoa(i))
+		now = now.Add(time.Millisecond)
+	}
+
+	if nb := len(limiter.buckets); nb > rateLimiterMaxBuckets {
+		t.Fatalf(`result: {%v} but expected {%v}`, nb, "at most the max buckets")
+	}
+	if _, is := limiter.buckets["client-0"]; is {
+		t.Fatalf(`result: {%v} but expected {%v}`, is, "the oldest bucket removed")
+	}
+	if _, is := limiter.buckets["client-"+strconv.Itoa(rateLimiterMaxBuckets)]; !is {
+		t.Fatalf(`result: {%v} but expected {%v}`, is, "the last bucket kept")
+	}
+}
+
+// TestLimitRate calls HTTPServer.handler(),
+// checking for a valid return value.
+func TestLimitRate(t *testing.T) {
+	mocker := &MockerTest{
+		mockResponse: &internal.MockedRequest{
+			MockedRequestLight: internal.MockedRequestLight{
+				MockedRequestHeader: internal.MockedRequestHeader{Status: 200, ContentType: "text/plain", Charset: "UTF-8"},
+			},
+			When: &internal.Matcher{Path: "/api/*"},
+		},
+	}
+	handler := NewHTTPServer("{port}", false, "", t.TempDir(), mocker, *logger, WithRateLimit(1, 3)).handler()
+
+	statuses := map[int]int{}
+	for i := 0; i < 5; i++ {
+		w := httptest.NewRecorder()
+		handler.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "http://localhost:3333/api/users", nil))
+		statuses[w.Code] = statuses[w.Code] + 1
+
+		if w.Code == 429 && w.Header().Get("Retry-After") != "1" {
+			t.Fatalf(`result: {%v} but expected {%v}`, w.Header().Get("Retry-After"), "1")
+		}
+	}
+
+	if statuses[200] != 3 || statuses[429] != 2 {
+		t.Fatalf(`result: {%v} but expected {%v}`, statuses, map[int]int{200: 3, 429: 2})
+	}
+
+	// test if the health checks are not limited
+	w := httptest.NewRecorder()
+	handler.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "http://localhost:3333/healthz", nil))
+	if w.Code != 200 {
+		t.Fatalf(`result: {%v} but expected {%v}`, w.Code, 200)
+	}
+}
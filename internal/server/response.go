@@ -1,68 +1,585 @@
 package server
 
 import (
+	"bytes"
+	"compress/gzip"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"io"
+	"math/rand/v2"
 	"net/http"
+	"slices"
+	"strconv"
+	"strings"
 	"time"
 
+	"github.com/andybalholm/brotli"
 	"github.com/joakim-ribier/go-utils/pkg/genericsutil"
+	"github.com/joakim-ribier/go-utils/pkg/slicesutil"
 	"github.com/joakim-ribier/go-utils/pkg/stringsutil"
 	"github.com/joakim-ribier/mockapic/internal"
+	"github.com/joakim-ribier/mockapic/pkg"
+)
+
+// compressionMinSize is the minimum size of a body to be compressed
+const compressionMinSize = 1024
+
+// contentEncodings are the supported content encodings by order of preference (for the same quality)
+var contentEncodings = []string{"br", "gzip"}
+
+// flushThreshold is the size above which a body is written by flushed chunks of {flushChunkSize},
+// the body is still fully loaded in memory (the clients only receive it progressively)
+const (
+	flushThreshold = 1 << 20
+	flushChunkSize = 32 << 10
 )
 
 // Response represents a {http.ResponseWriter} from the HTTP request
 type Response struct {
 	ResponseWriter http.ResponseWriter
+	Request        *http.Request
 	DelayMax       time.Duration
+	// JSONPath projects the JSON body on the value of the expression ("$.data.items") if defined
+	JSONPath string
+	// DelayPerKB adds a delay by KB of the request body ("10ms"), it overrides the delay per KB of the mocked request
+	DelayPerKB string
+	// NoDelay ignores all the delays (delay, delay per KB, chunk delay and bandwidth) to respond immediately
+	NoDelay bool
+	// Bodies caches the bodies fetched from the body URLs of the mocked requests (not cached if nil)
+	Bodies *bodyCache
+	// MaxBodySize is the max size of a body fetched from a body URL or read to compute the delay per KB (the default max body size if not defined)
+	MaxBodySize int64
 }
 
 // NewResponse creates and initializes a {Response} struct
-func NewResponse(responseWriter http.ResponseWriter, delayMax string) Response {
+func NewResponse(responseWriter http.ResponseWriter, request *http.Request, delayMax string) Response {
 	duration, _ := time.ParseDuration(delayMax)
 
 	return Response{
 		ResponseWriter: responseWriter,
+		Request:        request,
 		DelayMax:       duration,
 	}
 }
 
 // Write writes the http response using the provided {mock} value
-// and delays the response {delay} parameter is setted
-func (r Response) Write(mock internal.MockedRequest, delay string) {
-	var duration time.Duration = 0
-	if parse, err := time.ParseDuration(delay); err == nil {
-		duration = genericsutil.OrElse(
-			parse, func() bool { return parse <= r.DelayMax }, r.DelayMax)
+// and delays the response if {delay} parameter (or the stored delay of the {mock}) is setted,
+// the {delay} can be a single duration ("100ms") or a range ("100ms-500ms"),
+// nothing is written if the request is canceled during the delay
+func (r Response) Write(mock internal.MockedRequest, delay string) error {
+	duration, err := r.getDelay(stringsutil.OrElse(delay, mock.Delay))
+	if err != nil {
+		return err
 	}
 
-	if duration > 0 {
-		time.Sleep(duration)
+	duration, err = r.addDelayPerKB(duration, stringsutil.OrElse(r.DelayPerKB, mock.DelayPerKB))
+	if err != nil {
+		return err
+	}
+
+	chunkDelay, err := r.getChunkDelay(mock)
+	if err != nil {
+		return err
+	}
+
+	if r.JSONPath != "" {
+		if _, err := parseJSONPath(r.JSONPath); err != nil {
+			return err
+		}
+	}
+
+	if r.NoDelay {
+		duration, chunkDelay = 0, 0
+	}
+
+	if duration > 0 && !r.sleep(duration) {
+		return nil
+	}
+
+	mock = mock.ApplyPool(rand.IntN).ApplyVariant(r.Request).ApplyConditions(r.Request).ApplyStatusWeights(rand.Float64()).ApplyFailure(rand.Float64())
+	if r.NoDelay {
+		mock.BandwidthKbps = 0
+	}
+
+	if !r.writeBodyURL(&mock) {
+		return nil
+	}
+
+	if r.JSONPath != "" {
+		if err := projectJSON(&mock, r.JSONPath); err != nil {
+			return err
+		}
 	}
 
+	// every step reads the {mock} as updated by the previous ones
 	r.
-		writeContentType(mock).
-		writeHeaders(mock).
-		writeBody(mock)
+		writeContentType(&mock).
+		writeLocation(&mock).
+		writeTemplate(&mock).
+		writeContentEncoding(&mock).
+		writeETag(&mock).
+		writeLastModified(&mock).
+		writeHeaders(&mock).
+		writeBody(&mock, chunkDelay).
+		writeTrailers(&mock)
+
+	return nil
+}
+
+// sleep waits for the {duration}, it returns false if the request is canceled (client disconnected) in the meantime
+func (r Response) sleep(duration time.Duration) bool {
+	if r.Request == nil {
+		time.Sleep(duration)
+		return true
+	}
+
+	timer := time.NewTimer(duration)
+	defer timer.Stop()
+
+	select {
+	case <-timer.C:
+		return true
+	case <-r.Request.Context().Done():
+		return false
+	}
 }
 
-func (r Response) writeContentType(mock internal.MockedRequest) Response {
+// getDelay picks a random duration in the {delay} range capped by the max delay
+func (r Response) getDelay(delay string) (time.Duration, error) {
+	if delay == "" {
+		return 0, nil
+	}
+
+	lower, upper, err := internal.ParseDelay(delay)
+	if err != nil {
+		return 0, err
+	}
+
+	duration := lower
+	if upper > lower {
+		duration = lower + rand.N(upper-lower+1)
+	}
+
+	return genericsutil.OrElse(
+		duration, func() bool { return duration <= r.DelayMax }, r.DelayMax), nil
+}
+
+// addDelayPerKB adds the {delayPerKB} for each KB of the request body to the {duration},
+// the total cannot exceed the max delay
+func (r Response) addDelayPerKB(duration time.Duration, delayPerKB string) (time.Duration, error) {
+	if delayPerKB == "" {
+		return duration, nil
+	}
+
+	perKB, err := time.ParseDuration(delayPerKB)
+	if err != nil || perKB < 0 {
+		return 0, fmt.Errorf("delay per KB {%s} is malformed", delayPerKB)
+	}
+
+	if perKB == 0 || duration >= r.DelayMax {
+		return min(duration, r.DelayMax), nil
+	}
+
+	// the body is not read beyond the size which already reaches the max delay
+	limit := int64(float64(r.DelayMax-duration)/float64(perKB)*1024) + 1
+	duration = duration + time.Duration(float64(perKB)*float64(r.requestBodySize(limit))/1024)
+	return min(duration, r.DelayMax), nil
+}
+
+// requestBodySize returns the size of the request body from its "Content-Length",
+// a body of unknown length is read up to {limit} bytes (and the max body size) and restored for the next readers
+func (r Response) requestBodySize(limit int64) int64 {
+	if r.Request == nil || r.Request.Body == nil || r.Request.Body == http.NoBody {
+		return 0
+	}
+	if r.Request.ContentLength >= 0 {
+		return r.Request.ContentLength
+	}
+
+	maxSize := r.MaxBodySize
+	if maxSize <= 0 {
+		maxSize = defaultMaxBodySize
+	}
+	body := r.Request.Body
+	prefix, _ := io.ReadAll(io.LimitReader(body, min(limit, maxSize)))
+	r.Request.Body = struct {
+		io.Reader
+		io.Closer
+	}{io.MultiReader(bytes.NewReader(prefix), body), body}
+	return int64(len(prefix))
+}
+
+// getChunkDelay returns the delay between the body chunks of the {mock}, it cannot exceed the max delay
+func (r Response) getChunkDelay(mock internal.MockedRequest) (time.Duration, error) {
+	if mock.ChunkDelay == "" {
+		return 0, nil
+	}
+
+	chunkDelay, err := time.ParseDuration(mock.ChunkDelay)
+	if err != nil {
+		return 0, fmt.Errorf("chunk delay {%s} is malformed", mock.ChunkDelay)
+	}
+	if chunkDelay > r.DelayMax {
+		return 0, fmt.Errorf("chunk delay {%s} exceeds the max delay {%s}", mock.ChunkDelay, r.DelayMax.String())
+	}
+	return chunkDelay, nil
+}
+
+// writeContentType sets the "Content-Type" header, the charset is omitted for the binary contents
+func (r Response) writeContentType(mock *internal.MockedRequest) Response {
 	if contentType := mock.ContentType; contentType != "" {
-		r.ResponseWriter.Header().
-			Set("Content-Type", contentType+"; charset="+stringsutil.OrElse(mock.Charset, "utf-8"))
+		if slicesutil.Exist(pkg.IS_BINARY_CONTENT, contentType) ||
+			(mock.Charset == "" && !slicesutil.Exist(pkg.IS_DISPLAY_CONTENT, contentType)) {
+			r.ResponseWriter.Header().Set("Content-Type", contentType)
+		} else {
+			r.ResponseWriter.Header().
+				Set("Content-Type", contentType+"; charset="+stringsutil.OrElse(mock.Charset, "utf-8"))
+		}
+	}
+	if len(mock.Variants) > 0 {
+		r.ResponseWriter.Header().Add("Vary", "Accept")
+	}
+	return r
+}
+
+// writeLocation sets the "Location" header of the redirect responses (3xx), the {mock} body is then skipped
+func (r Response) writeLocation(mock *internal.MockedRequest) Response {
+	if mock.Status < 300 || mock.Status > 399 || mock.Location == "" {
+		return r
+	}
+
+	r.ResponseWriter.Header().Set("Location", mock.Location)
+	mock.Body64 = nil
+	return r
+}
+
+// writeTemplate renders the {mock} body against the request if the mock is templated,
+// the body is written as is if the template cannot be rendered
+func (r Response) writeTemplate(mock *internal.MockedRequest) Response {
+	if !mock.Templated || !slicesutil.Exist(pkg.IS_DISPLAY_CONTENT, mock.ContentType) {
+		return r
+	}
+
+	if body, err := renderTemplate(mock.Body64, r.Request); err == nil {
+		mock.Body64 = body
+		r.ResponseWriter.Header().Set("Content-Length", strconv.Itoa(len(mock.Body64)))
+	}
+	return r
+}
+
+// writeContentEncoding compresses the {mock} body with the encoding (brotli or gzip) preferred by the request
+// if the body is a display content large enough to be worth it
+func (r Response) writeContentEncoding(mock *internal.MockedRequest) Response {
+	if len(mock.Body64) < compressionMinSize || !isBodyAllowed(mock.Status) || !slicesutil.Exist(pkg.IS_DISPLAY_CONTENT, mock.ContentType) {
+		return r
+	}
+
+	var buffer bytes.Buffer
+	var writer io.WriteCloser
+	encoding := negotiateEncoding(r.Request)
+	switch encoding {
+	case "br":
+		writer = brotli.NewWriter(&buffer)
+	case "gzip":
+		writer = gzip.NewWriter(&buffer)
+	default:
+		return r
+	}
+	if _, err := writer.Write(mock.Body64); err != nil {
+		return r
+	}
+	if err := writer.Close(); err != nil {
+		return r
+	}
+
+	mock.Body64 = buffer.Bytes()
+	r.ResponseWriter.Header().Set("Content-Encoding", encoding)
+	r.ResponseWriter.Header().Set("Content-Length", strconv.Itoa(len(mock.Body64)))
+	r.ResponseWriter.Header().Add("Vary", "Accept-Encoding")
+	return r
+}
+
+// writeETag sets the "ETag" header (hash of the {mock} body) on the successful responses,
+// the {mock} is replaced by a "304 Not Modified" response without body if the request "If-None-Match" header matches
+func (r Response) writeETag(mock *internal.MockedRequest) Response {
+	if mock.Status < 200 || mock.Status > 299 {
+		return r
+	}
+
+	hash := sha256.Sum256(mock.Body64)
+	etag := `"` + hex.EncodeToString(hash[:16]) + `"`
+	r.ResponseWriter.Header().Set("ETag", etag)
+
+	if r.Request != nil && matchETag(r.Request.Header.Get("If-None-Match"), etag) {
+		mock.Status = 304
+		mock.Body64 = nil
+		r.ResponseWriter.Header().Del("Content-Length")
 	}
 	return r
 }
 
-func (r Response) writeHeaders(mock internal.MockedRequest) Response {
+// writeLastModified sets the "Last-Modified" header (creation date of the {mock}) on the successful responses,
+// the {mock} is replaced by a "304 Not Modified" response without body if it has not been modified since the request
+// "If-Modified-Since" header (ignored if the request defines the "If-None-Match" header)
+func (r Response) writeLastModified(mock *internal.MockedRequest) Response {
+	if mock.Status < 200 || mock.Status > 299 {
+		return r
+	}
+
+	createdAt, err := internal.ParseTime(mock.CreatedAt)
+	if err != nil {
+		return r
+	}
+	r.ResponseWriter.Header().Set("Last-Modified", createdAt.UTC().Format(http.TimeFormat))
+
+	if r.Request == nil || r.Request.Header.Get("If-None-Match") != "" {
+		return r
+	}
+	if since, err := http.ParseTime(r.Request.Header.Get("If-Modified-Since")); err == nil && !createdAt.After(since) {
+		mock.Status = 304
+		mock.Body64 = nil
+		r.ResponseWriter.Header().Del("Content-Length")
+	}
+	return r
+}
+
+// matchETag returns true if the {etag} is one of the (weak compared) "If-None-Match" header values
+func matchETag(ifNoneMatch, etag string) bool {
+	for _, value := range strings.Split(ifNoneMatch, ",") {
+		value = strings.TrimPrefix(strings.TrimSpace(value), "W/")
+		if value == "*" || value == etag {
+			return true
+		}
+	}
+	return false
+}
+
+// negotiateEncoding returns the supported encoding with the highest quality in the "Accept-Encoding" header
+// of the request (the order of {contentEncodings} breaks the ties), an empty value (identity) if none is accepted
+func negotiateEncoding(request *http.Request) string {
+	if request == nil {
+		return ""
+	}
+
+	qualities := map[string]float64{}
+	for _, value := range strings.Split(request.Header.Get("Accept-Encoding"), ",") {
+		name, params, _ := strings.Cut(value, ";")
+		if name = strings.ToLower(strings.TrimSpace(name)); name != "" {
+			qualities[name] = parseQuality(params)
+		}
+	}
+
+	encoding, quality := "", 0.0
+	for _, name := range contentEncodings {
+		q, is := qualities[name]
+		if !is {
+			q = qualities["*"]
+		}
+		if q > quality {
+			encoding, quality = name, q
+		}
+	}
+	return encoding
+}
+
+// parseQuality returns the "q" value of the {params} of an "Accept-Encoding" element (1 if not defined, 0 if malformed)
+func parseQuality(params string) float64 {
+	for _, param := range strings.Split(params, ";") {
+		key, value, _ := strings.Cut(param, "=")
+		if strings.TrimSpace(key) != "q" {
+			continue
+		}
+		q, err := strconv.ParseFloat(strings.TrimSpace(value), 64)
+		if err != nil {
+			return 0
+		}
+		return q
+	}
+	return 1
+}
+
+// writeHeaders sets the {mock} headers and writes the status line,
+// the custom status text (if defined) is echoed in the "X-Status-Text" header and written in the status line
+// when the connection can be hijacked (not possible with HTTP/2), the {mock} body is then already written
+func (r Response) writeHeaders(mock *internal.MockedRequest) Response {
+	// the hop-by-hop headers of the connection cannot be forced (e.g. by an imported mocked request)
 	for key, value := range mock.Headers {
-		r.ResponseWriter.Header().Set(key, value)
+		if !internal.IsHopByHopHeader(key) {
+			r.ResponseWriter.Header().Set(key, renderHeaderValue(value))
+		}
+	}
+	for key, values := range mock.MultiHeaders {
+		if internal.IsHopByHopHeader(key) {
+			continue
+		}
+		for _, value := range values {
+			r.ResponseWriter.Header().Add(key, renderHeaderValue(value))
+		}
+	}
+
+	// the status is final here (e.g. a "304" of the ETag), the stored body is never written (nor its length)
+	// with a status which does not allow it
+	if !isBodyAllowed(mock.Status) {
+		mock.Body64 = nil
+		mock.ForceContentLength = nil
+		r.ResponseWriter.Header().Del("Content-Length")
+	}
+
+	// the length is always defined so a "HEAD" request gets the same one as a "GET" request
+	if len(mock.Body64) > 0 {
+		r.ResponseWriter.Header().Set("Content-Length", strconv.Itoa(len(mock.Body64)))
+	}
+
+	if len(mock.Trailers) > 0 {
+		keys := make([]string, 0, len(mock.Trailers))
+		for key := range mock.Trailers {
+			keys = append(keys, http.CanonicalHeaderKey(key))
+		}
+		slices.Sort(keys)
+		r.ResponseWriter.Header().Set("Trailer", strings.Join(keys, ", "))
+		// the trailers are only sent with a chunked body
+		r.ResponseWriter.Header().Del("Content-Length")
+	}
+	if mock.ChunkSize > 0 {
+		r.ResponseWriter.Header().Del("Content-Length")
+	}
+	// deliberately wrong length (opt-in) to test the clients on a mismatched body size
+	if mock.ForceContentLength != nil {
+		r.ResponseWriter.Header().Set("Content-Length", strconv.Itoa(*mock.ForceContentLength))
+	}
+
+	if mock.StatusText != "" {
+		r.ResponseWriter.Header().Set("X-Status-Text", mock.StatusText)
+		if r.writeRawResponse(*mock) {
+			mock.Body64 = nil
+			return r
+		}
 	}
 	r.ResponseWriter.WriteHeader(mock.Status)
 	return r
 }
 
-func (r Response) writeBody(mock internal.MockedRequest) Response {
-	if len(mock.Body64) > 0 {
-		r.ResponseWriter.Write(mock.Body64)
+// statusRecorder is implemented by the writers which record the status of the response (metrics, access log...)
+type statusRecorder interface {
+	recordStatus(statusCode int)
+}
+
+// recordStatus records the {statusCode} on each recording writer of the chain,
+// the hijacked responses are written without {http.ResponseWriter.WriteHeader}
+func recordStatus(w http.ResponseWriter, statusCode int) {
+	for {
+		if rw, ok := w.(statusRecorder); ok {
+			rw.recordStatus(statusCode)
+		}
+		unwrapper, ok := w.(interface{ Unwrap() http.ResponseWriter })
+		if !ok {
+			return
+		}
+		w = unwrapper.Unwrap()
+	}
+}
+
+// writeRawResponse hijacks the connection to write the whole {mock} response with its custom status text,
+// it returns false if the connection cannot be hijacked
+func (r Response) writeRawResponse(mock internal.MockedRequest) bool {
+	conn, buffer, err := http.NewResponseController(r.ResponseWriter).Hijack()
+	if err != nil {
+		return false
+	}
+	defer conn.Close()
+	recordStatus(r.ResponseWriter, mock.Status)
+
+	header := r.ResponseWriter.Header().Clone()
+	header.Set("Connection", "close")
+	if isBodyAllowed(mock.Status) && mock.ForceContentLength == nil {
+		header.Set("Content-Length", strconv.Itoa(len(mock.Body64)))
+	}
+	if header.Get("Date") == "" {
+		header.Set("Date", time.Now().UTC().Format(http.TimeFormat))
+	}
+
+	fmt.Fprintf(buffer, "HTTP/1.1 %03d %s\r\n", mock.Status, mock.StatusText)
+	header.Write(buffer)
+	buffer.WriteString("\r\n")
+	if r.Request == nil || r.Request.Method != http.MethodHead {
+		buffer.Write(mock.Body64)
+	}
+	buffer.Flush()
+	return true
+}
+
+// isBodyAllowed returns false for the {status} which must not have a body ("1xx", "204 No Content" and "304 Not Modified")
+func isBodyAllowed(status int) bool {
+	return status >= 200 && status != 204 && status != 304
+}
+
+// writeBody writes the {mock} body (by flushed chunks if needed), nothing is written for a "HEAD" request
+func (r Response) writeBody(mock *internal.MockedRequest, chunkDelay time.Duration) Response {
+	if r.Request != nil && r.Request.Method == http.MethodHead {
+		return r
+	}
+
+	w := r
+	if mock.BandwidthKbps > 0 {
+		w.ResponseWriter = newThrottledWriter(r, mock.BandwidthKbps)
+	}
+
+	if mock.ChunkSize > 0 && len(mock.Body64) > 0 {
+		w.writeChunks(mock.Body64, mock.ChunkSize, chunkDelay)
+	} else if len(mock.Body64) > flushThreshold {
+		w.flushBody(mock.Body64)
+	} else if len(mock.Body64) > 0 {
+		w.ResponseWriter.Write(mock.Body64)
+	}
+	return r
+}
+
+// writeTrailers flushes the body and sets the {mock} trailers (declared by {writeHeaders})
+func (r Response) writeTrailers(mock *internal.MockedRequest) Response {
+	if len(mock.Trailers) == 0 {
+		return r
+	}
+
+	http.NewResponseController(r.ResponseWriter).Flush()
+	for key, value := range mock.Trailers {
+		r.ResponseWriter.Header().Set(key, renderHeaderValue(value))
 	}
 	return r
 }
+
+// writeChunks writes the {body} by slices of {chunkSize} bytes, each of them is flushed and followed by the {chunkDelay},
+// the remaining body is written at once if the writer does not support flushing (nothing more if the request is canceled)
+func (r Response) writeChunks(body []byte, chunkSize int, chunkDelay time.Duration) {
+	controller := http.NewResponseController(r.ResponseWriter)
+	for len(body) > 0 {
+		chunk := body[:min(chunkSize, len(body))]
+		body = body[len(chunk):]
+
+		if _, err := r.ResponseWriter.Write(chunk); err != nil {
+			return
+		}
+		if len(body) == 0 {
+			return
+		}
+		if err := controller.Flush(); err != nil {
+			r.ResponseWriter.Write(body)
+			return
+		}
+		if chunkDelay > 0 && !r.sleep(chunkDelay) {
+			return
+		}
+	}
+}
+
+// flushBody writes the in-memory {body} by chunks and flushes each of them so the client receives it progressively
+func (r Response) flushBody(body []byte) {
+	controller := http.NewResponseController(r.ResponseWriter)
+	reader := bytes.NewReader(body)
+	for reader.Len() > 0 {
+		if _, err := io.CopyN(r.ResponseWriter, reader, flushChunkSize); err != nil && err != io.EOF {
+			return
+		}
+		controller.Flush()
+	}
+}
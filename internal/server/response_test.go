@@ -1,9 +1,19 @@
 package server
 
 import (
+	"bytes"
+	"compress/gzip"
+	"context"
+	"io"
 	"net/http"
+	"net/http/httptest"
+	"slices"
+	"strconv"
+	"strings"
 	"testing"
+	"time"
 
+	"github.com/andybalholm/brotli"
 	"github.com/joakim-ribier/go-utils/pkg/slicesutil"
 	"github.com/joakim-ribier/go-utils/pkg/timesutil"
 	"github.com/joakim-ribier/mockapic/internal"
@@ -45,7 +55,7 @@ func TestWrite(t *testing.T) {
 
 	r := NewResponse(&ResponseWriterTest{
 		headers: make(map[string][]string),
-	}, "60s")
+	}, httptest.NewRequest(http.MethodGet, "http://localhost:3333/v1/{id}", nil), "60s")
 
 	withTime, _ := timesutil.WithExecutionTime(func() (*internal.MockedRequest, error) {
 		r.Write(mocked, "")
@@ -76,7 +86,7 @@ func TestWriteWithMaxDelay(t *testing.T) {
 
 	r := NewResponse(&ResponseWriterTest{
 		headers: make(map[string][]string),
-	}, "1000ms")
+	}, httptest.NewRequest(http.MethodGet, "http://localhost:3333/v1/{id}", nil), "1000ms")
 
 	withTime, _ := timesutil.WithExecutionTime(func() (*internal.MockedRequest, error) {
 		r.Write(mocked, "30s")
@@ -87,3 +97,1018 @@ func TestWriteWithMaxDelay(t *testing.T) {
 		t.Fatalf(`result: {%v} but expected {%v}`, withTime.TimeInMillis, "1s max")
 	}
 }
+
+// TestWriteWithStoredDelay calls Response.Write(internal.Mock, string),
+// checking for a valid return value.
+func TestWriteWithStoredDelay(t *testing.T) {
+	tests := []struct {
+		name        string
+		storedDelay string
+		delay       string
+		maxDelay    string
+		min, max    int64
+	}{
+		{"no delay", "", "", "60s", 0, 50},
+		{"stored delay", "300ms", "", "60s", 300, 350},
+		{"request delay over the stored delay", "300ms", "100ms", "60s", 100, 150},
+		{"stored delay capped by the max delay", "300ms", "", "200ms", 200, 250},
+	}
+	for _, test := range tests {
+		mocked := internal.MockedRequest{
+			MockedRequestLight: internal.MockedRequestLight{
+				MockedRequestHeader: internal.MockedRequestHeader{
+					Status: 200,
+				},
+			},
+			Delay: test.storedDelay,
+		}
+
+		r := NewResponse(&ResponseWriterTest{
+			headers: make(map[string][]string),
+		}, httptest.NewRequest(http.MethodGet, "http://localhost:3333/v1/{id}", nil), test.maxDelay)
+
+		withTime, _ := timesutil.WithExecutionTime(func() (*internal.MockedRequest, error) {
+			return &mocked, r.Write(mocked, test.delay)
+		})
+
+		if withTime.TimeInMillis < test.min || withTime.TimeInMillis > test.max {
+			t.Fatalf(`%s result: {%v} but expected {%v-%v}`, test.name, withTime.TimeInMillis, test.min, test.max)
+		}
+	}
+}
+
+// TestWriteWithDelayPerKB calls Response.Write(internal.Mock, string),
+// checking for a valid return value.
+func TestWriteWithDelayPerKB(t *testing.T) {
+	tests := []struct {
+		name          string
+		size          int
+		contentLength bool
+		maxDelay      string
+		min, max      int64
+	}{
+		{"10KB body", 10 << 10, true, "60s", 100, 150},
+		{"30KB body", 30 << 10, true, "60s", 300, 350},
+		{"30KB body of unknown length", 30 << 10, false, "60s", 300, 350},
+		{"30KB body capped by the max delay", 30 << 10, true, "200ms", 200, 250},
+		{"30KB body of unknown length capped by the max delay", 30 << 10, false, "200ms", 200, 250},
+	}
+	for _, test := range tests {
+		mocked := internal.MockedRequest{
+			MockedRequestLight: internal.MockedRequestLight{
+				MockedRequestHeader: internal.MockedRequestHeader{
+					Status: 200,
+				},
+			},
+			DelayPerKB: "10ms",
+		}
+
+		reqBody := bytes.Repeat([]byte("a"), test.size)
+		req := httptest.NewRequest(http.MethodPost, "http://localhost:3333/upload", bytes.NewReader(reqBody))
+		if !test.contentLength {
+			req.ContentLength = -1
+		}
+
+		withTime, _ := timesutil.WithExecutionTime(func() (*internal.MockedRequest, error) {
+			return &mocked, NewResponse(httptest.NewRecorder(), req, test.maxDelay).Write(mocked, "")
+		})
+
+		if withTime.TimeInMillis < test.min || withTime.TimeInMillis > test.max {
+			t.Fatalf(`%s result: {%v} but expected {%v-%v}`, test.name, withTime.TimeInMillis, test.min, test.max)
+		}
+		if body, _ := io.ReadAll(req.Body); len(body) != test.size {
+			t.Fatalf(`%s result: {%v} but expected {%v}`, test.name, len(body), test.size)
+		}
+	}
+}
+
+// TestRequestBodySize calls Response.requestBodySize(int64),
+// checking for a valid return value.
+func TestRequestBodySize(t *testing.T) {
+	reqBody := bytes.Repeat([]byte("a"), 30<<10)
+	req := httptest.NewRequest(http.MethodPost, "http://localhost:3333/upload", bytes.NewReader(reqBody))
+	req.ContentLength = -1
+
+	r := NewResponse(httptest.NewRecorder(), req, "60s")
+	if size := r.requestBodySize(1 << 10); size != 1<<10 {
+		t.Fatalf(`result: {%v} but expected {%v}`, size, 1<<10)
+	}
+	if body, _ := io.ReadAll(req.Body); !bytes.Equal(body, reqBody) {
+		t.Fatalf(`result: {%v} but expected {%v}`, len(body), len(reqBody))
+	}
+
+	// test if the body is not read beyond the max body size
+	req = httptest.NewRequest(http.MethodPost, "http://localhost:3333/upload", bytes.NewReader(reqBody))
+	req.ContentLength = -1
+	r = NewResponse(httptest.NewRecorder(), req, "60s")
+	r.MaxBodySize = 10
+	if size := r.requestBodySize(1 << 10); size != 10 {
+		t.Fatalf(`result: {%v} but expected {%v}`, size, 10)
+	}
+}
+
+// TestWriteWithNoDelay calls Response.Write(internal.Mock, string),
+// checking for a valid return value.
+func TestWriteWithNoDelay(t *testing.T) {
+	mocked := internal.MockedRequest{
+		MockedRequestLight: internal.MockedRequestLight{
+			MockedRequestHeader: internal.MockedRequestHeader{
+				Status:      200,
+				ContentType: "text/plain",
+				Charset:     "UTF-8",
+			},
+		},
+		Delay:         "10s",
+		DelayPerKB:    "1s",
+		ChunkSize:     1,
+		ChunkDelay:    "1s",
+		BandwidthKbps: 1,
+		Body64:        []byte("Hello World"),
+	}
+
+	w := httptest.NewRecorder()
+	r := NewResponse(w, httptest.NewRequest(http.MethodPost, "http://localhost:3333/v1/{id}", strings.NewReader("Hello World")), "60s")
+	r.NoDelay = true
+
+	withTime, _ := timesutil.WithExecutionTime(func() (*internal.MockedRequest, error) {
+		return &mocked, r.Write(mocked, "20s")
+	})
+
+	if withTime.TimeInMillis >= 10 || w.Body.String() != "Hello World" {
+		t.Fatalf(`result: {%v} but expected {%v}`, withTime.TimeInMillis, "less than 10ms")
+	}
+}
+
+// TestWriteWithCanceledRequest calls Response.Write(internal.Mock, string),
+// checking for a valid return value.
+func TestWriteWithCanceledRequest(t *testing.T) {
+	mocked := internal.MockedRequest{
+		MockedRequestLight: internal.MockedRequestLight{
+			MockedRequestHeader: internal.MockedRequestHeader{
+				Status:      200,
+				ContentType: "text/plain",
+				Charset:     "UTF-8",
+			},
+		},
+		Body64: []byte("Hello World"),
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	time.AfterFunc(50*time.Millisecond, cancel)
+
+	w := httptest.NewRecorder()
+	req := httptest.NewRequest(http.MethodGet, "http://localhost:3333/v1/{id}", nil).WithContext(ctx)
+
+	withTime, _ := timesutil.WithExecutionTime(func() (*internal.MockedRequest, error) {
+		return &mocked, NewResponse(w, req, "60s").Write(mocked, "10s")
+	})
+
+	if withTime.TimeInMillis > 5000 || w.Body.Len() != 0 || len(w.Header()) != 0 {
+		t.Fatalf(`result: {%v} but expected {%v}`, w.Body.String(), "")
+	}
+}
+
+// TestWriteWithDelayRange calls Response.Write(internal.Mock, string),
+// checking for a valid return value.
+func TestWriteWithDelayRange(t *testing.T) {
+	mocked := internal.MockedRequest{
+		MockedRequestLight: internal.MockedRequestLight{
+			MockedRequestHeader: internal.MockedRequestHeader{
+				Status: 200,
+			},
+		},
+	}
+
+	r := NewResponse(&ResponseWriterTest{
+		headers: make(map[string][]string),
+	}, httptest.NewRequest(http.MethodGet, "http://localhost:3333/v1/{id}", nil), "60s")
+
+	withTime, err := timesutil.WithExecutionTime(func() (*internal.MockedRequest, error) {
+		return &mocked, r.Write(mocked, "200ms-400ms")
+	})
+
+	if err != nil || !(withTime.TimeInMillis >= 200 && withTime.TimeInMillis < 450) {
+		t.Fatalf(`result: {%v} but expected {%v}`, withTime.TimeInMillis, "between 200ms and 400ms")
+	}
+}
+
+// TestWriteWithDelayRangeAndMaxDelay calls Response.Write(internal.Mock, string),
+// checking for a valid return value.
+func TestWriteWithDelayRangeAndMaxDelay(t *testing.T) {
+	mocked := internal.MockedRequest{
+		MockedRequestLight: internal.MockedRequestLight{
+			MockedRequestHeader: internal.MockedRequestHeader{
+				Status: 200,
+			},
+		},
+	}
+
+	r := NewResponse(&ResponseWriterTest{
+		headers: make(map[string][]string),
+	}, httptest.NewRequest(http.MethodGet, "http://localhost:3333/v1/{id}", nil), "300ms")
+
+	withTime, err := timesutil.WithExecutionTime(func() (*internal.MockedRequest, error) {
+		return &mocked, r.Write(mocked, "10s-20s")
+	})
+
+	if err != nil || !(withTime.TimeInMillis >= 300 && withTime.TimeInMillis < 350) {
+		t.Fatalf(`result: {%v} but expected {%v}`, withTime.TimeInMillis, "300ms max")
+	}
+}
+
+// TestWriteWithMalformedDelay calls Response.Write(internal.Mock, string),
+// checking for a valid return value.
+func TestWriteWithMalformedDelay(t *testing.T) {
+	mocked := internal.MockedRequest{
+		MockedRequestLight: internal.MockedRequestLight{
+			MockedRequestHeader: internal.MockedRequestHeader{
+				Status: 200,
+			},
+		},
+	}
+
+	for _, delay := range []string{"wrong", "100ms-", "-100ms", "500ms-100ms", "100ms-wrong"} {
+		w := &ResponseWriterTest{headers: make(map[string][]string)}
+
+		err := NewResponse(w, httptest.NewRequest(http.MethodGet, "http://localhost:3333/v1/{id}", nil), "60s").Write(mocked, delay)
+		if err == nil || err.Error() != "delay {"+delay+"} is malformed" || w.statusCode != 0 {
+			t.Fatalf(`result: {%v} but expected {%v}`, err, "delay is malformed")
+		}
+	}
+}
+
+// TestWriteWithGzipEncoding calls Response.Write(internal.Mock, string),
+// checking for a valid return value.
+func TestWriteWithGzipEncoding(t *testing.T) {
+	body := strings.Repeat("Hello World ", 200)
+	mocked := internal.MockedRequest{
+		MockedRequestLight: internal.MockedRequestLight{
+			MockedRequestHeader: internal.MockedRequestHeader{
+				Status:      200,
+				ContentType: "text/plain",
+				Charset:     "UTF-8",
+			},
+		},
+		Body64: []byte(body),
+	}
+
+	req := httptest.NewRequest(http.MethodGet, "http://localhost:3333/v1/{id}", nil)
+	req.Header.Set("Accept-Encoding", "deflate, gzip;q=1.0")
+	w := httptest.NewRecorder()
+
+	NewResponse(w, req, "60s").Write(mocked, "")
+
+	res := w.Result()
+	defer res.Body.Close()
+
+	reader, err := gzip.NewReader(res.Body)
+	if err != nil {
+		t.Fatalf(err.Error())
+	}
+	data, err := io.ReadAll(reader)
+	if err != nil {
+		t.Fatalf(err.Error())
+	}
+
+	if res.Header.Get("Content-Encoding") != "gzip" ||
+		res.Header.Get("Content-Length") != strconv.Itoa(w.Body.Len()) ||
+		w.Body.Len() >= len(body) ||
+		string(data) != body {
+		t.Fatalf(`result: {%v} but expected {%v}`, res.Header, "gzip content")
+	}
+}
+
+// TestWriteWithBrotliEncoding calls Response.Write(internal.Mock, string),
+// checking for a valid return value.
+func TestWriteWithBrotliEncoding(t *testing.T) {
+	body := strings.Repeat("Hello World ", 200)
+	mocked := internal.MockedRequest{
+		MockedRequestLight: internal.MockedRequestLight{
+			MockedRequestHeader: internal.MockedRequestHeader{
+				Status:      200,
+				ContentType: "text/plain",
+				Charset:     "UTF-8",
+			},
+		},
+		Body64: []byte(body),
+	}
+
+	req := httptest.NewRequest(http.MethodGet, "http://localhost:3333/v1/{id}", nil)
+	req.Header.Set("Accept-Encoding", "gzip;q=0.5, br")
+	w := httptest.NewRecorder()
+
+	NewResponse(w, req, "60s").Write(mocked, "")
+
+	res := w.Result()
+	defer res.Body.Close()
+
+	data, err := io.ReadAll(brotli.NewReader(res.Body))
+	if err != nil {
+		t.Fatalf(err.Error())
+	}
+
+	if res.Header.Get("Content-Encoding") != "br" ||
+		res.Header.Get("Content-Length") != strconv.Itoa(w.Body.Len()) ||
+		w.Body.Len() >= len(body) ||
+		string(data) != body {
+		t.Fatalf(`result: {%v} but expected {%v}`, res.Header, "br content")
+	}
+}
+
+// TestNegotiateEncoding calls negotiateEncoding(*http.Request),
+// checking for a valid return value.
+func TestNegotiateEncoding(t *testing.T) {
+	tests := []struct {
+		acceptEncoding string
+		expected       string
+	}{
+		{"", ""},
+		{"deflate", ""},
+		{"gzip", "gzip"},
+		{"gzip, br", "br"},
+		{"br;q=0.5, gzip", "gzip"},
+		{"br;q=0.8, gzip;q=0.8", "br"},
+		{"br;q=0, gzip", "gzip"},
+		{"br;q=0, gzip;q=0", ""},
+		{"*", "br"},
+		{"br;q=0, *;q=0.1", "gzip"},
+		{"gzip;q=abc, br;q=0", ""},
+	}
+
+	for _, test := range tests {
+		req := httptest.NewRequest(http.MethodGet, "http://localhost:3333/v1/{id}", nil)
+		req.Header.Set("Accept-Encoding", test.acceptEncoding)
+
+		if result := negotiateEncoding(req); result != test.expected {
+			t.Fatalf(`%s - result: {%v} but expected {%v}`, test.acceptEncoding, result, test.expected)
+		}
+	}
+}
+
+// TestWriteWithGzipEncodingNotApplied calls Response.Write(internal.Mock, string),
+// checking for a valid return value.
+func TestWriteWithGzipEncodingNotApplied(t *testing.T) {
+	largeBody := bytes.Repeat([]byte("Hello World "), 200)
+
+	tests := []struct {
+		name           string
+		contentType    string
+		body           []byte
+		acceptEncoding string
+	}{
+		{"no Accept-Encoding header", "text/plain", largeBody, ""},
+		{"gzip not accepted", "text/plain", largeBody, "gzip;q=0, deflate"},
+		{"br and gzip not accepted", "text/plain", largeBody, "br;q=0, gzip;q=0, deflate"},
+		{"binary content", "image/png", largeBody, "gzip"},
+		{"small body", "text/plain", []byte("Hello World"), "gzip"},
+	}
+
+	for _, test := range tests {
+		mocked := internal.MockedRequest{
+			MockedRequestLight: internal.MockedRequestLight{
+				MockedRequestHeader: internal.MockedRequestHeader{
+					Status:      200,
+					ContentType: test.contentType,
+					Charset:     "UTF-8",
+				},
+			},
+			Body64: test.body,
+		}
+
+		req := httptest.NewRequest(http.MethodGet, "http://localhost:3333/v1/{id}", nil)
+		if test.acceptEncoding != "" {
+			req.Header.Set("Accept-Encoding", test.acceptEncoding)
+		}
+		w := httptest.NewRecorder()
+
+		NewResponse(w, req, "60s").Write(mocked, "")
+
+		if w.Header().Get("Content-Encoding") != "" || !bytes.Equal(w.Body.Bytes(), test.body) {
+			t.Fatalf(`%s - result: {%v} but expected {%v}`, test.name, w.Header(), "no encoding")
+		}
+	}
+}
+
+// TestWriteWithTemplatedBody calls Response.Write(internal.Mock, string),
+// checking for a valid return value.
+func TestWriteWithTemplatedBody(t *testing.T) {
+	mocked := internal.MockedRequest{
+		MockedRequestLight: internal.MockedRequestLight{
+			MockedRequestHeader: internal.MockedRequestHeader{
+				Status:      200,
+				ContentType: "application/json",
+				Charset:     "UTF-8",
+			},
+		},
+		Templated: true,
+		Body64:    []byte(`{"id": "{{query.id}}", "ua": "{{header.User-Agent}}"}`),
+	}
+
+	req := httptest.NewRequest(http.MethodGet, "http://localhost:3333/v1/{id}?id=42", nil)
+	req.Header.Set("User-Agent", "mockapic")
+	w := httptest.NewRecorder()
+
+	NewResponse(w, req, "60s").Write(mocked, "")
+
+	expected := `{"id": "42", "ua": "mockapic"}`
+	if w.Body.String() != expected || w.Header().Get("Content-Length") != strconv.Itoa(len(expected)) {
+		t.Fatalf(`result: {%v} but expected {%v}`, w.Body.String(), expected)
+	}
+
+	// test if the rendering is disabled
+	mocked.Templated = false
+	w = httptest.NewRecorder()
+
+	NewResponse(w, req, "60s").Write(mocked, "")
+
+	if w.Body.String() != string(mocked.Body64) {
+		t.Fatalf(`result: {%v} but expected {%v}`, w.Body.String(), string(mocked.Body64))
+	}
+}
+
+// TestWriteWithHeaderTokens calls Response.Write(internal.Mock, string),
+// checking for a valid return value.
+func TestWriteWithHeaderTokens(t *testing.T) {
+	mocked := internal.MockedRequest{
+		MockedRequestLight: internal.MockedRequestLight{
+			MockedRequestHeader: internal.MockedRequestHeader{
+				Status:      200,
+				ContentType: "text/plain",
+				Charset:     "UTF-8",
+				Headers:     map[string]string{"X-Request-Id": "{{uuid}}", "Date": "{{now}}"},
+			},
+		},
+	}
+
+	requestIds := []string{}
+	for i := 0; i < 2; i++ {
+		w := httptest.NewRecorder()
+		NewResponse(w, httptest.NewRequest(http.MethodGet, "http://localhost:3333/v1/{id}", nil), "60s").Write(mocked, "")
+
+		if _, err := time.Parse(http.TimeFormat, w.Header().Get("Date")); err != nil {
+			t.Fatalf(`result: {%v} but expected {%v}`, w.Header().Get("Date"), "RFC1123")
+		}
+		requestIds = append(requestIds, w.Header().Get("X-Request-Id"))
+	}
+
+	if requestIds[0] == "" || requestIds[0] == "{{uuid}}" || requestIds[0] == requestIds[1] {
+		t.Fatalf(`result: {%v} but expected {%v}`, requestIds, "two different ids")
+	}
+}
+
+// TestWriteWithConditions calls Response.Write(internal.Mock, string),
+// checking for a valid return value.
+func TestWriteWithConditions(t *testing.T) {
+	mocked := internal.MockedRequest{
+		MockedRequestLight: internal.MockedRequestLight{
+			MockedRequestHeader: internal.MockedRequestHeader{
+				Status:      401,
+				ContentType: "text/plain",
+				Charset:     "UTF-8",
+			},
+		},
+		Conditions: []internal.Condition{{Header: "Authorization", Status: 200, Body64: []byte("ok")}},
+		Body64:     []byte("unauthorized"),
+	}
+
+	// test if the condition matches
+	req := httptest.NewRequest(http.MethodGet, "http://localhost:3333/v1/{id}", nil)
+	req.Header.Set("Authorization", "Bearer token")
+	w := httptest.NewRecorder()
+
+	NewResponse(w, req, "60s").Write(mocked, "")
+
+	if w.Code != 200 || w.Body.String() != "ok" {
+		t.Fatalf(`result: {%v} but expected {%v}`, w.Body.String(), "ok")
+	}
+
+	// test if the condition does not match
+	w = httptest.NewRecorder()
+
+	NewResponse(w, httptest.NewRequest(http.MethodGet, "http://localhost:3333/v1/{id}", nil), "60s").Write(mocked, "")
+
+	if w.Code != 401 || w.Body.String() != "unauthorized" {
+		t.Fatalf(`result: {%v} but expected {%v}`, w.Body.String(), "unauthorized")
+	}
+}
+
+// TestWriteWithETag calls Response.Write(internal.Mock, string),
+// checking for a valid return value.
+func TestWriteWithETag(t *testing.T) {
+	mocked := internal.MockedRequest{
+		MockedRequestLight: internal.MockedRequestLight{
+			MockedRequestHeader: internal.MockedRequestHeader{
+				Status:      201,
+				ContentType: "text/plain",
+				Charset:     "UTF-8",
+			},
+		},
+		Body64: []byte("Hello World"),
+	}
+
+	w := httptest.NewRecorder()
+	NewResponse(w, httptest.NewRequest(http.MethodGet, "http://localhost:3333/v1/{id}", nil), "60s").Write(mocked, "")
+
+	etag := w.Header().Get("ETag")
+	if w.Code != 201 || etag == "" || w.Body.String() != "Hello World" {
+		t.Fatalf(`result: {%v} but expected {%v}`, w.Header(), "ETag")
+	}
+
+	// test if the request sends the ETag
+	req := httptest.NewRequest(http.MethodGet, "http://localhost:3333/v1/{id}", nil)
+	req.Header.Set("If-None-Match", `"other", W/`+etag)
+	w = httptest.NewRecorder()
+
+	NewResponse(w, req, "60s").Write(mocked, "")
+
+	if w.Code != 304 || w.Header().Get("ETag") != etag || w.Body.Len() != 0 {
+		t.Fatalf(`result: {%v} but expected {%v}`, w.Code, 304)
+	}
+
+	// test if the request sends an ETag which does not match
+	req.Header.Set("If-None-Match", `"other"`)
+	w = httptest.NewRecorder()
+
+	NewResponse(w, req, "60s").Write(mocked, "")
+
+	if w.Code != 201 || w.Body.String() != "Hello World" {
+		t.Fatalf(`result: {%v} but expected {%v}`, w.Code, 201)
+	}
+
+	// test if the status is not a success
+	mocked.Status = 500
+	w = httptest.NewRecorder()
+
+	NewResponse(w, req, "60s").Write(mocked, "")
+
+	if w.Code != 500 || w.Header().Get("ETag") != "" {
+		t.Fatalf(`result: {%v} but expected {%v}`, w.Header(), "no ETag")
+	}
+}
+
+// TestWriteContentType calls Response.Write(internal.Mock, string),
+// checking for a valid return value.
+func TestWriteContentType(t *testing.T) {
+	tests := []struct {
+		contentType string
+		charset     string
+		expected    string
+	}{
+		{"text/plain", "UTF-8", "text/plain; charset=UTF-8"},
+		{"application/json", "", "application/json; charset=utf-8"},
+		{"image/png", "", "image/png"},
+		{"image/jpeg", "UTF-8", "image/jpeg"},
+		{"application/octet-stream", "UTF-8", "application/octet-stream"},
+		{"application/msgpack", "", "application/msgpack"},
+		{"application/x-protobuf", "UTF-8", "application/x-protobuf"},
+		{"image/svg+xml", "", "image/svg+xml"},
+		{"image/svg+xml", "UTF-8", "image/svg+xml; charset=UTF-8"},
+	}
+
+	for _, test := range tests {
+		mocked := internal.MockedRequest{
+			MockedRequestLight: internal.MockedRequestLight{
+				MockedRequestHeader: internal.MockedRequestHeader{
+					Status:      200,
+					ContentType: test.contentType,
+					Charset:     test.charset,
+				},
+			},
+		}
+		w := httptest.NewRecorder()
+
+		NewResponse(w, httptest.NewRequest(http.MethodGet, "http://localhost:3333/v1/{id}", nil), "60s").Write(mocked, "")
+
+		if r := w.Header().Get("Content-Type"); r != test.expected {
+			t.Fatalf(`result: {%v} but expected {%v}`, r, test.expected)
+		}
+	}
+}
+
+// TestWriteWithFlushedBody calls Response.Write(internal.Mock, string),
+// checking for a valid return value.
+func TestWriteWithFlushedBody(t *testing.T) {
+	body := make([]byte, 3*flushThreshold+7)
+	for i := range body {
+		body[i] = byte(i % 251)
+	}
+
+	mocked := internal.MockedRequest{
+		MockedRequestLight: internal.MockedRequestLight{
+			MockedRequestHeader: internal.MockedRequestHeader{
+				Status:      200,
+				ContentType: "image/png",
+			},
+		},
+		Body64: body,
+	}
+
+	w := httptest.NewRecorder()
+	NewResponse(w, httptest.NewRequest(http.MethodGet, "http://localhost:3333/v1/{id}", nil), "60s").Write(mocked, "")
+
+	if w.Code != 200 || !w.Flushed || !bytes.Equal(w.Body.Bytes(), body) {
+		t.Fatalf(`result: {%v} but expected {%v}`, w.Body.Len(), len(body))
+	}
+}
+
+// TestWriteWithStatusText calls Response.Write(internal.Mock, string),
+// checking for a valid return value.
+func TestWriteWithStatusText(t *testing.T) {
+	mocked := internal.MockedRequest{
+		MockedRequestLight: internal.MockedRequestLight{
+			MockedRequestHeader: internal.MockedRequestHeader{
+				Status:      200,
+				ContentType: "text/plain",
+				Charset:     "UTF-8",
+			},
+		},
+		StatusText: "Everything Is Fine",
+		Body64:     []byte("Hello World"),
+	}
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		NewResponse(w, r, "60s").Write(mocked, "")
+	}))
+	defer server.Close()
+
+	resp, err := http.Get(server.URL)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer resp.Body.Close()
+	body, _ := io.ReadAll(resp.Body)
+
+	if resp.Status != "200 Everything Is Fine" ||
+		resp.Header.Get("X-Status-Text") != "Everything Is Fine" ||
+		string(body) != "Hello World" {
+		t.Fatalf(`result: {%v} but expected {%v}`, resp.Status, "200 Everything Is Fine")
+	}
+
+	// the connection cannot be hijacked, the status text is only echoed in the header
+	w := httptest.NewRecorder()
+	NewResponse(w, httptest.NewRequest(http.MethodGet, "http://localhost:3333/v1/{id}", nil), "60s").Write(mocked, "")
+
+	if w.Code != 200 || w.Header().Get("X-Status-Text") != "Everything Is Fine" || w.Body.String() != "Hello World" {
+		t.Fatalf(`result: {%v} but expected {%v}`, w.Header().Get("X-Status-Text"), "Everything Is Fine")
+	}
+}
+
+// TestWriteWithLocation calls Response.Write(internal.Mock, string),
+// checking for a valid return value.
+func TestWriteWithLocation(t *testing.T) {
+	mocked := internal.MockedRequest{
+		MockedRequestLight: internal.MockedRequestLight{
+			MockedRequestHeader: internal.MockedRequestHeader{
+				Status:      302,
+				ContentType: "text/plain",
+				Charset:     "UTF-8",
+				Location:    "https://github.com/joakim-ribier/mockapic",
+			},
+		},
+		Body64: []byte("Hello World"),
+	}
+
+	w := httptest.NewRecorder()
+	NewResponse(w, httptest.NewRequest(http.MethodGet, "http://localhost:3333/v1/{id}", nil), "60s").Write(mocked, "")
+
+	if w.Code != 302 || w.Header().Get("Location") != "https://github.com/joakim-ribier/mockapic" || w.Body.Len() != 0 {
+		t.Fatalf(`result: {%v} but expected {%v}`, w.Header().Get("Location"), "https://github.com/joakim-ribier/mockapic")
+	}
+}
+
+// TestWriteWithTrailers calls Response.Write(internal.Mock, string),
+// checking for a valid return value.
+func TestWriteWithTrailers(t *testing.T) {
+	mocked := internal.MockedRequest{
+		MockedRequestLight: internal.MockedRequestLight{
+			MockedRequestHeader: internal.MockedRequestHeader{
+				Status:      200,
+				ContentType: "application/json",
+				Charset:     "UTF-8",
+			},
+		},
+		Trailers: map[string]string{"grpc-status": "0", "grpc-message": "OK"},
+		Body64:   []byte(`{"id":1}`),
+	}
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		NewResponse(w, r, "60s").Write(mocked, "")
+	}))
+	defer server.Close()
+
+	resp, err := http.Get(server.URL)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer resp.Body.Close()
+
+	// the trailers are only available once the body is read
+	body, _ := io.ReadAll(resp.Body)
+	if string(body) != `{"id":1}` || resp.Trailer.Get("Grpc-Status") != "0" || resp.Trailer.Get("Grpc-Message") != "OK" {
+		t.Fatalf(`result: {%v} but expected {%v}`, resp.Trailer, mocked.Trailers)
+	}
+}
+
+// TestWriteWithFailureRate calls Response.Write(internal.Mock, string),
+// checking for a valid return value.
+func TestWriteWithFailureRate(t *testing.T) {
+	mocked := internal.MockedRequest{
+		MockedRequestLight: internal.MockedRequestLight{
+			MockedRequestHeader: internal.MockedRequestHeader{
+				Status:      200,
+				ContentType: "text/plain",
+				Charset:     "UTF-8",
+			},
+		},
+		FailureRate:   1.0,
+		FailureStatus: 503,
+		Body64:        []byte("Hello World"),
+	}
+
+	for range 20 {
+		w := httptest.NewRecorder()
+		NewResponse(w, httptest.NewRequest(http.MethodGet, "http://localhost:3333/v1/{id}", nil), "60s").Write(mocked, "")
+
+		if w.Code != 503 || w.Body.Len() != 0 {
+			t.Fatalf(`result: {%v} but expected {%v}`, w.Code, 503)
+		}
+	}
+}
+
+// TestWriteWithLastModified calls Response.Write(internal.Mock, string),
+// checking for a valid return value.
+func TestWriteWithLastModified(t *testing.T) {
+	createdAt := time.Date(2024, 7, 14, 10, 30, 0, 0, time.Local)
+	mocked := internal.MockedRequest{
+		MockedRequestLight: internal.MockedRequestLight{
+			CreatedAt: createdAt.Format("2006-01-02 15:04:05"),
+			MockedRequestHeader: internal.MockedRequestHeader{
+				Status:      200,
+				ContentType: "text/plain",
+				Charset:     "UTF-8",
+			},
+		},
+		Body64: []byte("Hello World"),
+	}
+
+	w := httptest.NewRecorder()
+	NewResponse(w, httptest.NewRequest(http.MethodGet, "http://localhost:3333/v1/{id}", nil), "60s").Write(mocked, "")
+
+	if lastModified := w.Header().Get("Last-Modified"); w.Code != 200 || lastModified != createdAt.UTC().Format(http.TimeFormat) {
+		t.Fatalf(`result: {%v} but expected {%v}`, lastModified, createdAt.UTC().Format(http.TimeFormat))
+	}
+
+	tests := map[string]int{
+		createdAt.UTC().Format(http.TimeFormat):                 304,
+		createdAt.Add(time.Hour).UTC().Format(http.TimeFormat):  304,
+		createdAt.Add(-time.Hour).UTC().Format(http.TimeFormat): 200,
+		"wrong-date": 200,
+	}
+	for ifModifiedSince, expected := range tests {
+		req := httptest.NewRequest(http.MethodGet, "http://localhost:3333/v1/{id}", nil)
+		req.Header.Set("If-Modified-Since", ifModifiedSince)
+		w := httptest.NewRecorder()
+
+		NewResponse(w, req, "60s").Write(mocked, "")
+
+		if w.Code != expected || (expected == 304 && w.Body.Len() != 0) {
+			t.Fatalf(`%s - result: {%v} but expected {%v}`, ifModifiedSince, w.Code, expected)
+		}
+	}
+
+	// test if the "If-None-Match" header takes precedence
+	req := httptest.NewRequest(http.MethodGet, "http://localhost:3333/v1/{id}", nil)
+	req.Header.Set("If-Modified-Since", createdAt.UTC().Format(http.TimeFormat))
+	req.Header.Set("If-None-Match", `"other"`)
+	w = httptest.NewRecorder()
+
+	NewResponse(w, req, "60s").Write(mocked, "")
+
+	if w.Code != 200 || w.Body.String() != "Hello World" {
+		t.Fatalf(`result: {%v} but expected {%v}`, w.Code, 200)
+	}
+}
+
+// flushCounterWriter is a {http.ResponseWriter} which records each written chunk and counts the flushes
+type flushCounterWriter struct {
+	*httptest.ResponseRecorder
+	chunks  []string
+	flushes int
+}
+
+func (w *flushCounterWriter) Write(data []byte) (int, error) {
+	w.chunks = append(w.chunks, string(data))
+	return w.ResponseRecorder.Write(data)
+}
+
+func (w *flushCounterWriter) Flush() {
+	w.flushes = w.flushes + 1
+	w.ResponseRecorder.Flush()
+}
+
+// TestWriteWithForceContentLength calls Response.Write(internal.Mock, string),
+// checking for a valid return value.
+func TestWriteWithForceContentLength(t *testing.T) {
+	forceContentLength := 100
+	mocked := internal.MockedRequest{
+		MockedRequestLight: internal.MockedRequestLight{
+			MockedRequestHeader: internal.MockedRequestHeader{
+				Status:      200,
+				ContentType: "text/plain",
+				Charset:     "UTF-8",
+			},
+		},
+		ForceContentLength: &forceContentLength,
+		Body64:             []byte("Hello World"),
+	}
+
+	w := httptest.NewRecorder()
+	NewResponse(w, httptest.NewRequest(http.MethodGet, "http://localhost:3333/v1/{id}", nil), "60s").Write(mocked, "")
+
+	if w.Header().Get("Content-Length") != "100" || w.Body.String() != "Hello World" {
+		t.Fatalf(`result: {%v} but expected {%v}`, w.Header().Get("Content-Length"), 100)
+	}
+
+	// test if a real client gets a truncated body
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		NewResponse(w, r, "60s").Write(mocked, "")
+	}))
+	defer server.Close()
+
+	res, err := http.Get(server.URL)
+	if err != nil {
+		t.Fatalf(err.Error())
+	}
+	defer res.Body.Close()
+
+	if _, err := io.ReadAll(res.Body); res.ContentLength != 100 || err != io.ErrUnexpectedEOF {
+		t.Fatalf(`result: {%v %v} but expected {%v}`, res.ContentLength, err, io.ErrUnexpectedEOF)
+	}
+}
+
+// TestWriteWithNoContent calls Response.Write(internal.Mock, string),
+// checking for a valid return value.
+func TestWriteWithNoContent(t *testing.T) {
+	forceContentLength := 100
+	for _, status := range []int{204, 304} {
+		mocked := internal.MockedRequest{
+			MockedRequestLight: internal.MockedRequestLight{
+				MockedRequestHeader: internal.MockedRequestHeader{
+					Status:      status,
+					ContentType: "text/plain",
+					Charset:     "UTF-8",
+				},
+			},
+			ForceContentLength: &forceContentLength,
+			Body64:             []byte(strings.Repeat("Hello World", 200)),
+		}
+
+		w := httptest.NewRecorder()
+		req := httptest.NewRequest(http.MethodGet, "http://localhost:3333/v1/{id}", nil)
+		req.Header.Set("Accept-Encoding", "gzip")
+		NewResponse(w, req, "60s").Write(mocked, "")
+
+		if w.Code != status || w.Body.Len() != 0 || w.Header().Get("Content-Length") != "" {
+			t.Fatalf(`%d - result: {%v} but expected {%v}`, status, w.Body.String(), "no body")
+		}
+	}
+}
+
+// TestWriteWithNotModifiedAndForcedLength calls Response.Write(internal.Mock, string),
+// checking for a valid return value.
+func TestWriteWithNotModifiedAndForcedLength(t *testing.T) {
+	forceContentLength := 100
+	mocked := internal.MockedRequest{
+		MockedRequestLight: internal.MockedRequestLight{
+			MockedRequestHeader: internal.MockedRequestHeader{
+				Status:      200,
+				ContentType: "text/plain",
+				Charset:     "UTF-8",
+			},
+		},
+		ForceContentLength: &forceContentLength,
+		Body64:             []byte("Hello World"),
+	}
+
+	w := httptest.NewRecorder()
+	NewResponse(w, httptest.NewRequest(http.MethodGet, "http://localhost:3333/v1/{id}", nil), "60s").Write(mocked, "")
+	etag := w.Header().Get("ETag")
+
+	w = httptest.NewRecorder()
+	req := httptest.NewRequest(http.MethodGet, "http://localhost:3333/v1/{id}", nil)
+	req.Header.Set("If-None-Match", etag)
+	NewResponse(w, req, "60s").Write(mocked, "")
+
+	if w.Code != 304 || w.Body.Len() != 0 || w.Header().Get("Content-Length") != "" {
+		t.Fatalf(`result: {%v %v} but expected {%v}`, w.Code, w.Header().Get("Content-Length"), "304 without length")
+	}
+}
+
+// TestWriteWithChunks calls Response.Write(internal.Mock, string),
+// checking for a valid return value.
+func TestWriteWithChunks(t *testing.T) {
+	mocked := internal.MockedRequest{
+		MockedRequestLight: internal.MockedRequestLight{
+			MockedRequestHeader: internal.MockedRequestHeader{
+				Status:      200,
+				ContentType: "text/plain",
+				Charset:     "UTF-8",
+			},
+		},
+		ChunkSize:  4,
+		ChunkDelay: "50ms",
+		Body64:     []byte("Hello World"),
+	}
+
+	w := &flushCounterWriter{ResponseRecorder: httptest.NewRecorder()}
+	withTime, _ := timesutil.WithExecutionTime(func() (*internal.MockedRequest, error) {
+		NewResponse(w, httptest.NewRequest(http.MethodGet, "http://localhost:3333/v1/{id}", nil), "60s").Write(mocked, "")
+		return &mocked, nil
+	})
+
+	expected := []string{"Hell", "o Wo", "rld"}
+	if !slices.Equal(w.chunks, expected) || w.flushes < 2 || w.Body.String() != "Hello World" {
+		t.Fatalf(`result: {%v, %d flushes} but expected {%v}`, w.chunks, w.flushes, expected)
+	}
+	if withTime.TimeInMillis < 100 {
+		t.Fatalf(`result: {%v} but expected {%v}`, withTime.TimeInMillis, ">= 100")
+	}
+
+	// test if the writer does not support flushing (the remaining body is written at once)
+	rw := &ResponseWriterTest{headers: make(map[string][]string)}
+	NewResponse(rw, httptest.NewRequest(http.MethodGet, "http://localhost:3333/v1/{id}", nil), "60s").Write(mocked, "")
+
+	if rw.body != "o World" {
+		t.Fatalf(`result: {%v} but expected {%v}`, rw.body, "o World")
+	}
+
+	// test if the chunk delay exceeds the max delay
+	mocked.ChunkDelay = "2m"
+	if err := NewResponse(httptest.NewRecorder(), nil, "60s").Write(mocked, ""); err == nil {
+		t.Fatalf(`result: {%v} but expected {%v}`, err, "chunk delay {2m} exceeds the max delay {1m0s}")
+	}
+}
+
+// TestWriteWithMultiHeaders calls Response.Write(internal.Mock, string),
+// checking for a valid return value.
+func TestWriteWithMultiHeaders(t *testing.T) {
+	mocked := internal.MockedRequest{
+		MockedRequestLight: internal.MockedRequestLight{
+			MockedRequestHeader: internal.MockedRequestHeader{
+				Status:       200,
+				ContentType:  "text/plain",
+				Charset:      "UTF-8",
+				Headers:      map[string]string{"X-Language": "golang"},
+				MultiHeaders: map[string][]string{"Set-Cookie": {"session=abc; Path=/", "theme=dark; Path=/"}},
+			},
+		},
+		Body64: []byte("Hello World"),
+	}
+
+	w := httptest.NewRecorder()
+	NewResponse(w, httptest.NewRequest(http.MethodGet, "http://localhost:3333/v1/{id}", nil), "60s").Write(mocked, "")
+
+	resp := w.Result()
+	expected := []string{"session=abc; Path=/", "theme=dark; Path=/"}
+	if !slices.Equal(resp.Header.Values("Set-Cookie"), expected) || len(resp.Cookies()) != 2 ||
+		resp.Header.Get("X-Language") != "golang" {
+		t.Fatalf(`result: {%v} but expected {%v}`, resp.Header, expected)
+	}
+}
+
+// TestWriteWithVariants calls Response.Write(internal.Mock, string),
+// checking for a valid return value.
+func TestWriteWithVariants(t *testing.T) {
+	mocked := internal.MockedRequest{
+		MockedRequestLight: internal.MockedRequestLight{
+			MockedRequestHeader: internal.MockedRequestHeader{
+				Status:      200,
+				ContentType: "application/json",
+				Charset:     "UTF-8",
+			},
+		},
+		Variants: []internal.MockedRequest{{
+			MockedRequestLight: internal.MockedRequestLight{
+				MockedRequestHeader: internal.MockedRequestHeader{Status: 200, ContentType: "application/xml", Charset: "UTF-8"},
+			},
+			Body64: []byte("<name>mockapic</name>"),
+		}},
+		Body64: []byte(`{"name": "mockapic"}`),
+	}
+
+	tests := map[string][]string{
+		"application/json": {"application/json; charset=UTF-8", `{"name": "mockapic"}`},
+		"application/xml":  {"application/xml; charset=UTF-8", "<name>mockapic</name>"},
+	}
+	for accept, expected := range tests {
+		req := httptest.NewRequest(http.MethodGet, "http://localhost:3333/v1/{id}", nil)
+		req.Header.Set("Accept", accept)
+		w := httptest.NewRecorder()
+
+		NewResponse(w, req, "60s").Write(mocked, "")
+
+		if w.Header().Get("Content-Type") != expected[0] || w.Body.String() != expected[1] || w.Header().Get("Vary") != "Accept" {
+			t.Fatalf(`%s - result: {%v, %v} but expected {%v}`, accept, w.Header(), w.Body.String(), expected)
+		}
+	}
+}
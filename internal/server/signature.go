@@ -0,0 +1,122 @@
+package server
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/url"
+	"path"
+	"strconv"
+	"time"
+
+	"github.com/joakim-ribier/go-utils/pkg/stringsutil"
+)
+
+// defaultSignatureTTL is the validity of a signed URL if the "ttl" parameter is not defined
+const defaultSignatureTTL = time.Hour
+
+// WithSigningSecret requires a valid signature ("sig" and "exp" parameters) to serve the mocked requests
+// ("/v1/{id}", "/v1/{id}/raw", "/v1/raw/{id}", "/v1/ws/{id}" and the matched requests), the signed URLs are provided by "/v1/{id}/sign", the signing is disabled if the {secret} is empty
+func WithSigningSecret(secret string) Option {
+	return func(s *HTTPServer) {
+		s.signingSecret = []byte(secret)
+	}
+}
+
+// sign returns the HMAC-SHA256 (hex encoded) of the {mockId} path (its namespace included) and the {expiresAt} unix time,
+// a URL signed in a namespace cannot be used in another one
+func (s HTTPServer) sign(mockId string, expiresAt int64) string {
+	mac := hmac.New(sha256.New, s.signingSecret)
+	mac.Write([]byte(s.namespacePath + "/" + mockId + "." + strconv.FormatInt(expiresAt, 10)))
+	return hex.EncodeToString(mac.Sum(nil))
+}
+
+// verifySignature returns an error if the request has no signature of the {mockId}, if it has expired or if it is invalid
+func (s HTTPServer) verifySignature(r *http.Request, mockId string) error {
+	sig, exp := r.URL.Query().Get("sig"), r.URL.Query().Get("exp")
+	if sig == "" || exp == "" {
+		return errors.New("signature is missing")
+	}
+
+	expiresAt, err := strconv.ParseInt(exp, 10, 64)
+	if err != nil {
+		return errors.New("signature is invalid")
+	}
+	if !hmac.Equal([]byte(sig), []byte(s.sign(mockId, expiresAt))) {
+		return errors.New("signature is invalid")
+	}
+	if time.Now().Unix() > expiresAt {
+		return errors.New("signature has expired")
+	}
+	return nil
+}
+
+// requireSignature responds 403 if the signing is enabled and the request does not carry a valid signature
+// of the mocked request {id} (the last segment of the path if it's not a path value)
+func (s HTTPServer) requireSignature(handle func(w http.ResponseWriter, r *http.Request)) func(w http.ResponseWriter, r *http.Request) {
+	return func(w http.ResponseWriter, r *http.Request) {
+		if len(s.signingSecret) > 0 {
+			if err := s.verifySignature(r, stringsutil.OrElse(r.PathValue("id"), path.Base(r.URL.Path))); err != nil {
+				writeError(w, err, 403)
+				return
+			}
+		}
+		handle(w, r)
+	}
+}
+
+// requireAdminIfSigned responds 401 if the signing is enabled and the request does not carry the admin token,
+// it protects the endpoints which expose the bodies of several mocked requests at once (e.g. the exports)
+func (s HTTPServer) requireAdminIfSigned(handle func(w http.ResponseWriter, r *http.Request)) func(w http.ResponseWriter, r *http.Request) {
+	return func(w http.ResponseWriter, r *http.Request) {
+		if len(s.signingSecret) > 0 && (s.adminToken == "" || !s.isAdmin(r)) {
+			writeError(w, errors.New("admin token is missing or invalid"), 401)
+			return
+		}
+		handle(w, r)
+	}
+}
+
+// signMockedRequest writes the URL of the mocked request {id} signed for the "ttl" duration (1h by default),
+// the admin token is always required (no URL can be signed if it's not defined)
+func (s HTTPServer) signMockedRequest(w http.ResponseWriter, r *http.Request) {
+	if s.adminToken == "" || !s.isAdmin(r) {
+		writeError(w, errors.New("admin token is missing or invalid"), 401)
+		return
+	}
+
+	ttl := defaultSignatureTTL
+	if value := r.URL.Query().Get("ttl"); value != "" {
+		duration, err := time.ParseDuration(value)
+		if err != nil || duration <= 0 {
+			writeError(w, fmt.Errorf("ttl {%s} must be a positive duration", value), 400)
+			return
+		}
+		ttl = duration
+	}
+
+	mock, statusCode, err := s.findMockedRequest(r, s.mocker.Get)
+	if err != nil {
+		writeError(w, err, statusCode)
+		return
+	}
+
+	expiresAt := time.Now().Add(ttl).Unix()
+	target := url.URL{
+		Scheme: s.getProtocol(r),
+		Host:   r.Host,
+		Path:   "/v1" + s.namespacePath + "/" + mock.Id,
+		RawQuery: url.Values{
+			"exp": {strconv.FormatInt(expiresAt, 10)},
+			"sig": {s.sign(mock.Id, expiresAt)},
+		}.Encode(),
+	}
+
+	s.writeResponse(w, r, map[string]string{
+		"url":       target.String(),
+		"expiresAt": time.Unix(expiresAt, 0).UTC().Format(time.RFC3339),
+	})
+}
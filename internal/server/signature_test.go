@@ -0,0 +1,223 @@
+package server
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/joakim-ribier/go-utils/pkg/jsonsutil"
+	"github.com/joakim-ribier/mockapic/internal"
+)
+
+func newSignedServer(t *testing.T, opts ...Option) (*HTTPServer, string) {
+	mocker := internal.NewInMemoryMock(*logger)
+	id, err := mocker.New(map[string][]string{
+		"status":      {"200"},
+		"contentType": {"text/plain"},
+		"charset":     {"UTF-8"},
+	}, []byte("Hello World"))
+	if err != nil {
+		t.Fatalf(err.Error())
+	}
+
+	return NewHTTPServer("{port}", false, "", workingDirectory, mocker, *logger, opts...), *id
+}
+
+// TestGetMockedRequestEndpointWithSignature calls HTTPServer.dispatchMockedRequest(http.ResponseWriter, *http.Request),
+// checking for a valid return value.
+func TestGetMockedRequestEndpointWithSignature(t *testing.T) {
+	s, id := newSignedServer(t, WithSigningSecret("secret"))
+	future, past := time.Now().Add(time.Hour).Unix(), time.Now().Add(-time.Hour).Unix()
+
+	tests := map[string]struct {
+		query   string
+		status  int
+		message string
+	}{
+		"valid":    {"?exp=" + strconv.FormatInt(future, 10) + "&sig=" + s.sign(id, future), 200, ""},
+		"expired":  {"?exp=" + strconv.FormatInt(past, 10) + "&sig=" + s.sign(id, past), 403, "signature has expired"},
+		"tampered": {"?exp=" + strconv.FormatInt(future+60, 10) + "&sig=" + s.sign(id, future), 403, "signature is invalid"},
+		"other id": {"?exp=" + strconv.FormatInt(future, 10) + "&sig=" + s.sign("other", future), 403, "signature is invalid"},
+		"missing":  {"", 403, "signature is missing"},
+	}
+
+	for name, test := range tests {
+		w := httptest.NewRecorder()
+		s.dispatchMockedRequest(w, httptest.NewRequest(http.MethodGet, "http://localhost:3333/v1/"+id+test.query, nil))
+
+		res, body := geResultResponse(w, t)
+		if res.StatusCode != test.status || (test.message != "" && string(body) != `{"message": "`+test.message+`"}`) {
+			t.Fatalf(`%s - result: {%v %v} but expected {%v}`, name, res.StatusCode, string(body), test.status)
+		}
+	}
+
+	// test if a signature made with another secret is rejected
+	other, _ := newSignedServer(t, WithSigningSecret("other-secret"))
+	w := httptest.NewRecorder()
+	s.dispatchMockedRequest(w, httptest.NewRequest(http.MethodGet,
+		"http://localhost:3333/v1/"+id+"?exp="+strconv.FormatInt(future, 10)+"&sig="+other.sign(id, future), nil))
+	if w.Code != 403 {
+		t.Fatalf(`result: {%v} but expected {%v}`, w.Code, 403)
+	}
+}
+
+// TestSignMockedRequestEndpoint calls HTTPServer.signMockedRequest(http.ResponseWriter, *http.Request),
+// checking for a valid return value.
+func TestSignMockedRequestEndpoint(t *testing.T) {
+	s, id := newSignedServer(t, WithSigningSecret("secret"), WithAdminToken("token"))
+	handler := s.handler()
+
+	req := httptest.NewRequest(http.MethodGet, "http://localhost:3333/v1/"+id+"/sign?ttl=10m", nil)
+	req.Header.Set(adminTokenHeader, "token")
+	w := httptest.NewRecorder()
+	handler.ServeHTTP(w, req)
+
+	res, body := geResultResponse(w, t)
+	signed, err := jsonsutil.Unmarshal[map[string]string](body)
+	if res.StatusCode != 200 || err != nil || !strings.HasPrefix(signed["url"], "http://localhost:3333/v1/"+id+"?exp=") {
+		t.Fatalf(`result: {%v} but expected {%v}`, string(body), "a signed URL")
+	}
+
+	// test if the signed URL serves the mocked request
+	w = httptest.NewRecorder()
+	handler.ServeHTTP(w, httptest.NewRequest(http.MethodGet, signed["url"], nil))
+	if w.Code != 200 || w.Body.String() != "Hello World" {
+		t.Fatalf(`result: {%v} but expected {%v}`, w.Code, 200)
+	}
+
+	// test if the ttl is malformed
+	req = httptest.NewRequest(http.MethodGet, "http://localhost:3333/v1/"+id+"/sign?ttl=-1m", nil)
+	req.Header.Set(adminTokenHeader, "token")
+	w = httptest.NewRecorder()
+	handler.ServeHTTP(w, req)
+	if w.Code != 400 {
+		t.Fatalf(`result: {%v} but expected {%v}`, w.Code, 400)
+	}
+
+	// test if the admin token is required
+	w = httptest.NewRecorder()
+	handler.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "http://localhost:3333/v1/"+id+"/sign", nil))
+	if w.Code != 401 {
+		t.Fatalf(`result: {%v} but expected {%v}`, w.Code, 401)
+	}
+
+	// test if no URL can be signed if the admin token is not defined
+	s, id = newSignedServer(t, WithSigningSecret("secret"))
+	w = httptest.NewRecorder()
+	s.handler().ServeHTTP(w, httptest.NewRequest(http.MethodGet, "http://localhost:3333/v1/"+id+"/sign", nil))
+	if w.Code != 401 {
+		t.Fatalf(`result: {%v} but expected {%v}`, w.Code, 401)
+	}
+}
+
+// TestSignMockedRequestEndpointDisabled calls HTTPServer.dispatchMockedRequest(http.ResponseWriter, *http.Request),
+// checking for a valid return value.
+func TestSignMockedRequestEndpointDisabled(t *testing.T) {
+	s, id := newSignedServer(t)
+
+	w := httptest.NewRecorder()
+	s.dispatchMockedRequest(w, httptest.NewRequest(http.MethodGet, "http://localhost:3333/v1/"+id+"/sign", nil))
+	if w.Code != 404 {
+		t.Fatalf(`result: {%v} but expected {%v}`, w.Code, 404)
+	}
+
+	// test if the mocked request is served without any signature
+	w = httptest.NewRecorder()
+	s.dispatchMockedRequest(w, httptest.NewRequest(http.MethodGet, "http://localhost:3333/v1/"+id, nil))
+	if w.Code != 200 {
+		t.Fatalf(`result: {%v} but expected {%v}`, w.Code, 200)
+	}
+}
+
+// TestRoutesWithSignature calls HTTPServer.handler(),
+// checking for a valid return value.
+func TestRoutesWithSignature(t *testing.T) {
+	s, id := newSignedServer(t, WithSigningSecret("secret"), WithAdminToken("token"))
+	handler := s.handler()
+	future := time.Now().Add(time.Hour).Unix()
+	query := "?exp=" + strconv.FormatInt(future, 10) + "&sig=" + s.sign(id, future)
+
+	tests := map[string]struct {
+		url    string
+		status int
+	}{
+		"stored without sig": {"/v1/" + id + "/raw", 403},
+		"stored with sig":    {"/v1/" + id + "/raw" + query, 200},
+		"raw without sig":    {"/v1/raw/" + id, 403},
+		"raw with sig":       {"/v1/raw/" + id + query, 200},
+		"ws without sig":     {"/v1/ws/" + id, 403},
+		"export postman":     {"/v1/export/postman", 401},
+		"export jsonl":       {"/v1/export/jsonl", 401},
+	}
+
+	for name, test := range tests {
+		w := httptest.NewRecorder()
+		handler.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "http://localhost:3333"+test.url, nil))
+		if w.Code != test.status {
+			t.Fatalf(`%s - result: {%v} but expected {%v}`, name, w.Code, test.status)
+		}
+	}
+}
+
+// TestMatchMockedRequestWithSignature calls HTTPServer.matchMockedRequest(http.ResponseWriter, *http.Request),
+// checking for a valid return value.
+func TestMatchMockedRequestWithSignature(t *testing.T) {
+	mocker := internal.NewInMemoryMock(*logger)
+	id, err := mocker.New(map[string][]string{
+		"status":      {"200"},
+		"contentType": {"text/plain"},
+		"charset":     {"UTF-8"},
+		"when.path":   {"/users/*"},
+	}, []byte("Hello World"))
+	if err != nil {
+		t.Fatalf(err.Error())
+	}
+	s := NewHTTPServer("{port}", false, "", workingDirectory, mocker, *logger, WithSigningSecret("secret"))
+
+	w := httptest.NewRecorder()
+	s.matchMockedRequest(w, httptest.NewRequest(http.MethodGet, "http://localhost:3333/users/1", nil))
+	if w.Code != 403 {
+		t.Fatalf(`result: {%v} but expected {%v}`, w.Code, 403)
+	}
+
+	future := time.Now().Add(time.Hour).Unix()
+	w = httptest.NewRecorder()
+	s.matchMockedRequest(w, httptest.NewRequest(http.MethodGet,
+		"http://localhost:3333/users/1?exp="+strconv.FormatInt(future, 10)+"&sig="+s.sign(*id, future), nil))
+	if w.Code != 200 || w.Body.String() != "Hello World" {
+		t.Fatalf(`result: {%v} but expected {%v}`, w.Code, 200)
+	}
+}
+
+// TestSignMockedRequestEndpointWithNamespace calls HTTPServer.handler(),
+// checking for a valid return value.
+func TestSignMockedRequestEndpointWithNamespace(t *testing.T) {
+	s, _ := newSignedServer(t, WithSigningSecret("secret"), WithAdminToken("token"))
+	handler := s.handler()
+	call := func(method, path string) *httptest.ResponseRecorder {
+		req := httptest.NewRequest(method, "http://localhost:3333"+path, strings.NewReader("Hello World"))
+		req.Header.Set(adminTokenHeader, "token")
+		w := httptest.NewRecorder()
+		handler.ServeHTTP(w, req)
+		return w
+	}
+
+	created, _ := jsonsutil.Unmarshal[map[string]any](call(http.MethodPost, "/v1/ns/team-a/new?contentType=text%2Fplain&charset=UTF-8").Body.Bytes())
+	id := created["id"].(string)
+
+	signed, _ := jsonsutil.Unmarshal[map[string]string](call(http.MethodGet, "/v1/ns/team-a/"+id+"/sign").Body.Bytes())
+	query := signed["url"][strings.Index(signed["url"], "?"):]
+	if w := call(http.MethodGet, "/v1/ns/team-a/"+id+query); w.Code != 200 || w.Body.String() != "Hello World" {
+		t.Fatalf(`result: {%v} but expected {%v}`, w.Code, 200)
+	}
+
+	// test if the signature is rejected in another namespace (or out of any namespace)
+	for _, path := range []string{"/v1/ns/team-b/" + id, "/v1/" + id} {
+		if w := call(http.MethodGet, path+query); w.Code != 403 {
+			t.Fatalf(`%s - result: {%v} but expected {%v}`, path, w.Code, 403)
+		}
+	}
+}
@@ -0,0 +1,86 @@
+package server
+
+import (
+	"bytes"
+	"net/http"
+	"regexp"
+	"strconv"
+	"strings"
+	"text/template"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// shorthandVariable matches the shorthand variables like {{query.id}} or {{header.User-Agent}}
+var shorthandVariable = regexp.MustCompile(`\{\{\s*(query|header|path)\.([^\s{}]+)\s*\}\}`)
+
+// headerToken matches the tokens of a header value like {{now}} or {{uuid}}
+var headerToken = regexp.MustCompile(`\{\{\s*(now|uuid)\s*\}\}`)
+
+// renderHeaderValue substitutes the tokens of the header {value}:
+//   - {{now}} the current time (RFC1123)
+//   - {{uuid}} a new UUID
+//
+// The unknown tokens are kept as is.
+func renderHeaderValue(value string) string {
+	return headerToken.ReplaceAllStringFunc(value, func(token string) string {
+		if headerToken.FindStringSubmatch(token)[1] == "now" {
+			return time.Now().UTC().Format(http.TimeFormat)
+		}
+		return uuid.NewString()
+	})
+}
+
+// renderTemplate renders the {body} as a text template using the data of the {request}:
+//   - {{query.id}} (or {{index .query "id"}}) the query parameter "id"
+//   - {{header.User-Agent}} (or {{index .header "User-Agent"}}) the header "User-Agent"
+//   - {{path.0}} (or {{index .path "0"}}) the first segment of the path
+//
+// The unresolved variables are rendered as empty strings.
+func renderTemplate(body []byte, request *http.Request) ([]byte, error) {
+	text := shorthandVariable.ReplaceAllStringFunc(string(body), func(variable string) string {
+		matches := shorthandVariable.FindStringSubmatch(variable)
+		key := matches[2]
+		if matches[1] == "header" {
+			key = http.CanonicalHeaderKey(key)
+		}
+		return `{{index .` + matches[1] + ` "` + key + `"}}`
+	})
+
+	tmpl, err := template.New("body").Option("missingkey=zero").Parse(text)
+	if err != nil {
+		return nil, err
+	}
+
+	var buffer bytes.Buffer
+	if err := tmpl.Execute(&buffer, templateData(request)); err != nil {
+		return nil, err
+	}
+	return buffer.Bytes(), nil
+}
+
+// templateData builds the data exposed to the template from the {request}
+func templateData(request *http.Request) map[string]map[string]string {
+	query := map[string]string{}
+	header := map[string]string{}
+	path := map[string]string{}
+
+	if request != nil {
+		for key := range request.URL.Query() {
+			query[key] = request.URL.Query().Get(key)
+		}
+		for key := range request.Header {
+			header[key] = request.Header.Get(key)
+		}
+		for i, segment := range strings.Split(strings.Trim(request.URL.Path, "/"), "/") {
+			path[strconv.Itoa(i)] = segment
+		}
+	}
+
+	return map[string]map[string]string{
+		"query":  query,
+		"header": header,
+		"path":   path,
+	}
+}
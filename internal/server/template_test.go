@@ -0,0 +1,71 @@
+package server
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// TestRenderTemplate calls renderTemplate([]byte, *http.Request),
+// checking for a valid return value.
+func TestRenderTemplate(t *testing.T) {
+	req := httptest.NewRequest(http.MethodGet, "http://localhost:3333/api/users?id=1", nil)
+	req.Header.Set("User-Agent", "mockapic")
+
+	body := `{"id": "{{query.id}}", "ua": "{{header.user-agent}}", "resource": "{{ path.1 }}", "raw": "{{index .query "id"}}"}`
+
+	r, err := renderTemplate([]byte(body), req)
+	if err != nil {
+		t.Fatalf(err.Error())
+	}
+
+	expected := `{"id": "1", "ua": "mockapic", "resource": "users", "raw": "1"}`
+	if string(r) != expected {
+		t.Fatalf(`result: {%v} but expected {%v}`, string(r), expected)
+	}
+}
+
+// TestRenderTemplateWithUnresolvedVariables calls renderTemplate([]byte, *http.Request),
+// checking for a valid return value.
+func TestRenderTemplateWithUnresolvedVariables(t *testing.T) {
+	req := httptest.NewRequest(http.MethodGet, "http://localhost:3333/", nil)
+
+	r, err := renderTemplate([]byte(`{"id": "{{query.id}}", "ua": "{{header.X-Unknown}}", "name": "{{.query.name}}"}`), req)
+	if err != nil {
+		t.Fatalf(err.Error())
+	}
+
+	expected := `{"id": "", "ua": "", "name": ""}`
+	if string(r) != expected {
+		t.Fatalf(`result: {%v} but expected {%v}`, string(r), expected)
+	}
+}
+
+// TestRenderTemplateWithMalformedTemplate calls renderTemplate([]byte, *http.Request),
+// checking for a valid return value.
+func TestRenderTemplateWithMalformedTemplate(t *testing.T) {
+	req := httptest.NewRequest(http.MethodGet, "http://localhost:3333/", nil)
+
+	if r, err := renderTemplate([]byte(`{{if}}`), req); err == nil {
+		t.Fatalf(`result: {%v} but expected error`, string(r))
+	}
+}
+
+// TestRenderHeaderValue calls renderHeaderValue(string),
+// checking for a valid return value.
+func TestRenderHeaderValue(t *testing.T) {
+	if r, err := time.Parse(http.TimeFormat, renderHeaderValue("{{now}}")); err != nil || time.Since(r) > time.Minute {
+		t.Fatalf(`result: {%v} but expected {%v}`, r, "now")
+	}
+
+	if r, err := uuid.Parse(renderHeaderValue("{{ uuid }}")); err != nil {
+		t.Fatalf(`result: {%v} but expected {%v}`, r, "uuid")
+	}
+
+	if r := renderHeaderValue("id-{{unknown}}-{{query.id}}"); r != "id-{{unknown}}-{{query.id}}" {
+		t.Fatalf(`result: {%v} but expected {%v}`, r, "id-{{unknown}}-{{query.id}}")
+	}
+}
@@ -0,0 +1,45 @@
+package server
+
+import (
+	"crypto/x509"
+	"testing"
+	"time"
+
+	"github.com/joakim-ribier/go-utils/pkg/httpsutil"
+)
+
+// TestNewSelfSignedCert calls newSelfSignedCert(),
+// checking for a valid return value.
+func TestNewSelfSignedCert(t *testing.T) {
+	cert, err := newSelfSignedCert()
+	if err != nil {
+		t.Fatalf(err.Error())
+	}
+
+	certificate, err := x509.ParseCertificate(cert.Certificate[0])
+	if err != nil {
+		t.Fatalf(err.Error())
+	}
+	if err := certificate.VerifyHostname("localhost"); err != nil {
+		t.Fatalf(`result: {%v} but expected {%v}`, err, nil)
+	}
+}
+
+// TestListenSelfSignedCert calls HTTPServer.Listen(),
+// checking for a valid return value.
+func TestListenSelfSignedCert(t *testing.T) {
+	httpServer := NewHTTPServer("3338", true, "", workingDirectory, &MockerTest{}, *logger, WithSelfSignedCert(true))
+
+	go func() {
+		if err := httpServer.Listen(); err != nil {
+			t.Errorf("Error: %v", err)
+		}
+	}()
+	time.Sleep(100 * time.Millisecond)
+
+	req, _ := httpsutil.NewHttpRequest("https://localhost:3338/", "")
+	resp, err := req.InsecureSkipVerify().Call()
+	if err != nil || resp.StatusCode != 200 {
+		t.Fatalf(`result: {%v} but expected {%v}`, resp, 200)
+	}
+}
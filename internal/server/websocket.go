@@ -0,0 +1,142 @@
+package server
+
+import (
+	"bufio"
+	"crypto/sha1"
+	"encoding/base64"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"net/http"
+	"strings"
+	"time"
+)
+
+// webSocketGUID is the key suffix defined by the RFC 6455 to compute the "Sec-WebSocket-Accept" header
+const webSocketGUID = "258EAFA5-E914-47DA-95CA-C5AB0DC85B11"
+
+// webSocket opcodes (RFC 6455)
+const (
+	webSocketText  byte = 0x1
+	webSocketClose byte = 0x8
+)
+
+// serveWebSocket upgrades the connection and replays the frames of the mocked request {id} (as text frames),
+// each frame is delayed by the frame delay of the mocked request (if defined) and the socket is closed at the end,
+// the hit is only counted once the connection is upgraded
+func (s HTTPServer) serveWebSocket(w http.ResponseWriter, r *http.Request) {
+	mock, statusCode, err := s.findMockedRequest(r, s.mocker.Get)
+	if err != nil {
+		writeError(w, err, statusCode)
+		return
+	}
+	if len(mock.Frames) == 0 {
+		writeError(w, fmt.Errorf("mocked request {%s} does not define any frame", mock.Id), 400)
+		return
+	}
+
+	key, err := webSocketKey(r)
+	if err != nil {
+		writeError(w, err, 400)
+		return
+	}
+	if r.Header.Get("Sec-WebSocket-Version") != "13" {
+		w.Header().Set("Sec-WebSocket-Version", "13")
+		writeError(w, errors.New("websocket version must be {13}"), 426)
+		return
+	}
+
+	delay, _ := time.ParseDuration(mock.FrameDelay)
+	if s.noDelay(r) {
+		delay = 0
+	}
+
+	conn, buffer, err := http.NewResponseController(w).Hijack()
+	if err != nil {
+		s.logger.Error(err, "error to hijack the connection", "uri", r.RequestURI)
+		writeError(w, errors.New("websocket is not supported"), 500)
+		return
+	}
+	defer conn.Close()
+
+	setServedMockId(w, mock.Id)
+
+	fmt.Fprintf(buffer, "HTTP/1.1 101 Switching Protocols\r\n"+
+		"Upgrade: websocket\r\n"+
+		"Connection: Upgrade\r\n"+
+		"Sec-WebSocket-Accept: %s\r\n\r\n", webSocketAccept(key))
+	if err := buffer.Flush(); err != nil {
+		return
+	}
+
+	if served, err := s.mocker.Hit(mock.Id); err == nil {
+		mock = served
+	}
+
+	for i, frame := range mock.Frames {
+		if i > 0 && delay > 0 && !waitFrame(r, delay) {
+			return
+		}
+		if err := writeWebSocketFrame(buffer.Writer, webSocketText, []byte(frame)); err != nil {
+			s.logger.Error(err, "error to write websocket frame", "uri", r.RequestURI)
+			return
+		}
+	}
+
+	writeWebSocketFrame(buffer.Writer, webSocketClose, binary.BigEndian.AppendUint16(nil, 1000))
+}
+
+// waitFrame waits the {delay} between two frames, it returns false if the request is canceled in the meantime
+// (e.g. the server is shut down)
+func waitFrame(r *http.Request, delay time.Duration) bool {
+	timer := time.NewTimer(delay)
+	defer timer.Stop()
+
+	select {
+	case <-timer.C:
+		return true
+	case <-r.Context().Done():
+		return false
+	}
+}
+
+// webSocketKey returns the "Sec-WebSocket-Key" header of the upgrade request {r}
+func webSocketKey(r *http.Request) (string, error) {
+	if !strings.EqualFold(r.Header.Get("Upgrade"), "websocket") ||
+		!strings.Contains(strings.ToLower(r.Header.Get("Connection")), "upgrade") {
+		return "", errors.New("request must be a websocket upgrade")
+	}
+
+	key := r.Header.Get("Sec-WebSocket-Key")
+	if key == "" {
+		return "", errors.New("websocket key must be defined")
+	}
+	return key, nil
+}
+
+// webSocketAccept computes the "Sec-WebSocket-Accept" header value of the {key}
+func webSocketAccept(key string) string {
+	hash := sha1.Sum([]byte(key + webSocketGUID))
+	return base64.StdEncoding.EncodeToString(hash[:])
+}
+
+// writeWebSocketFrame writes and flushes a final (unmasked) frame with the {opcode} and the {payload}
+func writeWebSocketFrame(w *bufio.Writer, opcode byte, payload []byte) error {
+	header := []byte{0x80 | opcode}
+	switch {
+	case len(payload) < 126:
+		header = append(header, byte(len(payload)))
+	case len(payload) <= 0xFFFF:
+		header = binary.BigEndian.AppendUint16(append(header, 126), uint16(len(payload)))
+	default:
+		header = binary.BigEndian.AppendUint64(append(header, 127), uint64(len(payload)))
+	}
+
+	if _, err := w.Write(header); err != nil {
+		return err
+	}
+	if _, err := w.Write(payload); err != nil {
+		return err
+	}
+	return w.Flush()
+}
@@ -0,0 +1,163 @@
+package server
+
+import (
+	"bufio"
+	"context"
+	"encoding/binary"
+	"io"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/joakim-ribier/mockapic/internal"
+)
+
+// readWebSocketFrame reads an unmasked frame and returns its opcode and its payload
+func readWebSocketFrame(reader *bufio.Reader) (byte, []byte, error) {
+	header := make([]byte, 2)
+	if _, err := io.ReadFull(reader, header); err != nil {
+		return 0, nil, err
+	}
+
+	length := uint64(header[1] & 0x7F)
+	switch length {
+	case 126:
+		extended := make([]byte, 2)
+		if _, err := io.ReadFull(reader, extended); err != nil {
+			return 0, nil, err
+		}
+		length = uint64(binary.BigEndian.Uint16(extended))
+	case 127:
+		extended := make([]byte, 8)
+		if _, err := io.ReadFull(reader, extended); err != nil {
+			return 0, nil, err
+		}
+		length = binary.BigEndian.Uint64(extended)
+	}
+
+	payload := make([]byte, length)
+	_, err := io.ReadFull(reader, payload)
+	return header[0] & 0x0F, payload, err
+}
+
+// TestServeWebSocket calls HTTPServer.serveWebSocket(http.ResponseWriter, *http.Request),
+// checking for a valid return value.
+func TestServeWebSocket(t *testing.T) {
+	mocker := internal.NewInMemoryMock(*logger)
+	id, err := mocker.New(map[string][]string{
+		"contentType": {"text/plain"},
+		"charset":     {"UTF-8"},
+		"frames":      {`["hello", "` + strings.Repeat("x", 200) + `", "world"]`},
+		"frameDelay":  {"10ms"},
+	}, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	server := httptest.NewServer(NewHTTPServer("{port}", false, "", workingDirectory, mocker, *logger).handler())
+	defer server.Close()
+
+	conn, err := net.Dial("tcp", strings.TrimPrefix(server.URL, "http://"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer conn.Close()
+
+	io.WriteString(conn, "GET /v1/ws/"+*id+" HTTP/1.1\r\n"+
+		"Host: localhost\r\n"+
+		"Upgrade: websocket\r\n"+
+		"Connection: Upgrade\r\n"+
+		"Sec-WebSocket-Key: dGhlIHNhbXBsZSBub25jZQ==\r\n"+
+		"Sec-WebSocket-Version: 13\r\n\r\n")
+
+	reader := bufio.NewReader(conn)
+	resp, err := http.ReadResponse(reader, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if resp.StatusCode != 101 || resp.Header.Get("Sec-WebSocket-Accept") != "s3pPLMBiTxaQ9kYGzzhZRbK+xOo=" {
+		t.Fatalf(`result: {%v} but expected {%v}`, resp.Status, "101 Switching Protocols")
+	}
+
+	frames := []string{}
+	for {
+		opcode, payload, err := readWebSocketFrame(reader)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if opcode == webSocketClose {
+			if code := binary.BigEndian.Uint16(payload); code != 1000 {
+				t.Fatalf(`result: {%v} but expected {%v}`, code, 1000)
+			}
+			break
+		}
+		frames = append(frames, string(payload))
+	}
+
+	expected := []string{"hello", strings.Repeat("x", 200), "world"}
+	if !reflect.DeepEqual(frames, expected) {
+		t.Fatalf(`result: {%v} but expected {%v}`, frames, expected)
+	}
+	if mock, _ := mocker.Get(*id); mock.Hits != 1 {
+		t.Fatalf(`result: {%v} but expected {%v}`, mock.Hits, 1)
+	}
+}
+
+// TestServeWebSocketWithBadRequest calls HTTPServer.serveWebSocket(http.ResponseWriter, *http.Request),
+// checking for a valid return value.
+func TestServeWebSocketWithBadRequest(t *testing.T) {
+	mocker := internal.NewInMemoryMock(*logger)
+	id, _ := mocker.New(map[string][]string{"contentType": {"text/plain"}, "charset": {"UTF-8"}}, nil)
+	s := NewHTTPServer("{port}", false, "", workingDirectory, mocker, *logger)
+
+	tests := map[string]int{
+		"/v1/ws/" + *id: 400,
+		"/v1/ws/{id}":   404,
+	}
+	for path, expected := range tests {
+		req := httptest.NewRequest(http.MethodGet, "http://localhost:3333"+path, nil)
+		req.Header.Set("Upgrade", "websocket")
+		req.Header.Set("Connection", "Upgrade")
+		req.Header.Set("Sec-WebSocket-Key", "dGhlIHNhbXBsZSBub25jZQ==")
+		req.Header.Set("Sec-WebSocket-Version", "13")
+		w := httptest.NewRecorder()
+
+		s.handler().ServeHTTP(w, req)
+
+		if w.Code != expected {
+			t.Fatalf(`%s - result: {%v} but expected {%v}`, path, w.Code, expected)
+		}
+	}
+	// test if a rejected handshake is not counted
+	framesId, _ := mocker.New(map[string][]string{"contentType": {"text/plain"}, "charset": {"UTF-8"}, "frames": {`["hello"]`}}, nil)
+	req := httptest.NewRequest(http.MethodGet, "http://localhost:3333/v1/ws/"+*framesId, nil)
+	req.Header.Set("Upgrade", "websocket")
+	req.Header.Set("Connection", "Upgrade")
+	req.Header.Set("Sec-WebSocket-Key", "dGhlIHNhbXBsZSBub25jZQ==")
+	req.Header.Set("Sec-WebSocket-Version", "8")
+	w := httptest.NewRecorder()
+	s.handler().ServeHTTP(w, req)
+
+	if mock, _ := mocker.Get(*framesId); w.Code != 426 || mock.Hits != 0 {
+		t.Fatalf(`result: {%v %v} but expected {%v}`, w.Code, mock.Hits, "426 without hit")
+	}
+}
+
+// TestWaitFrame calls waitFrame(*http.Request, time.Duration),
+// checking for a valid return value.
+func TestWaitFrame(t *testing.T) {
+	req := httptest.NewRequest(http.MethodGet, "http://localhost:3333/v1/ws/{id}", nil)
+	if !waitFrame(req, time.Millisecond) {
+		t.Fatalf(`result: {%v} but expected {%v}`, false, true)
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	if waitFrame(req.WithContext(ctx), time.Hour) {
+		t.Fatalf(`result: {%v} but expected {%v}`, true, false)
+	}
+}
@@ -0,0 +1,38 @@
+package internal
+
+import (
+	"os"
+
+	"github.com/joakim-ribier/go-utils/pkg/jsonsutil"
+)
+
+// Stored returns the content of the file of the mocked request {mockId} as is (decompressed if needed, metadata included),
+// the predefined requests (not stored) are returned marshaled.
+func (m Mock) Stored(mockId string) ([]byte, error) {
+	// the predefined requests can be defined with any id (not a UUID)
+	if i := m.findPredefined(mockId); i > -1 {
+		return jsonsutil.Marshal(m.predefinedMockedRequests[i].toMockedRequest())
+	}
+
+	if err := validateId(mockId); err != nil {
+		return nil, err
+	}
+	return readMockFile(os.DirFS(m.workingDirectory), mockId)
+}
+
+// Stored returns the mocked request {mockId} marshaled as it is kept (metadata included).
+func (m *InMemoryMock) Stored(mockId string) ([]byte, error) {
+	mock, err := m.Get(mockId)
+	if err != nil {
+		return nil, err
+	}
+	return jsonsutil.Marshal(mock)
+}
+
+// Stored returns the content of the file of the mocked request {mockId} as is (metadata included).
+func (m EmbeddedMock) Stored(mockId string) ([]byte, error) {
+	if err := validateId(mockId); err != nil {
+		return nil, err
+	}
+	return readMockFile(m.fsys, mockId)
+}
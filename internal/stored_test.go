@@ -0,0 +1,76 @@
+package internal
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+// TestStored calls Mock.Stored,
+// checking for a valid return value.
+func TestStored(t *testing.T) {
+	mocker := NewMock(t.TempDir(), nil, *logger)
+	id, err := mocker.New(map[string][]string{
+		"status":      {"200"},
+		"contentType": {"text/plain"},
+		"charset":     {"UTF-8"},
+	}, []byte("Hello World"))
+	if err != nil {
+		t.Fatalf(err.Error())
+	}
+
+	data, err := mocker.Stored(*id)
+	expected, _ := os.ReadFile(filepath.Join(mocker.workingDirectory, *id+".json"))
+	if err != nil || string(data) != string(expected) || !strings.Contains(string(data), `"createdAt":`) {
+		t.Fatalf(`result: {%v} but expected {%v}`, string(data), string(expected))
+	}
+
+	// test if the id is malformed
+	if _, err := mocker.Stored("../malformed"); err == nil || err.Error() != "mocked request id {../malformed} is malformed" {
+		t.Fatalf(`result: {%v} but expected {%v}`, err, "mocked request id is malformed")
+	}
+
+	// test if a predefined request is returned whatever its id
+	predefined := PredefinedMockedRequest{MockedRequest: MockedRequest{
+		MockedRequestLight: MockedRequestLight{
+			Id:                  "hello-world",
+			MockedRequestHeader: MockedRequestHeader{Status: 200, ContentType: "text/plain", Charset: "UTF-8"},
+		},
+	}}
+	mocker = NewMock(t.TempDir(), []PredefinedMockedRequest{predefined}, *logger)
+	if data, err := mocker.Stored("hello-world"); err != nil || !strings.Contains(string(data), `"id":"hello-world"`) {
+		t.Fatalf(`result: {%v} but expected {%v}`, err, "hello-world")
+	}
+}
+
+// TestStoredInMemory calls InMemoryMock.Stored,
+// checking for a valid return value.
+func TestStoredInMemory(t *testing.T) {
+	mocker := NewInMemoryMock(*logger)
+	id, err := mocker.New(map[string][]string{
+		"status":      {"200"},
+		"contentType": {"text/plain"},
+		"charset":     {"UTF-8"},
+	}, []byte("Hello World"))
+	if err != nil {
+		t.Fatalf(err.Error())
+	}
+
+	if data, err := mocker.Stored(*id); err != nil || !strings.Contains(string(data), `"createdAt":`) {
+		t.Fatalf(`result: {%v} but expected {%v}`, string(data), "createdAt")
+	}
+	if _, err := mocker.Stored("unknown"); err == nil {
+		t.Fatalf(`result: {%v} but expected {%v}`, err, "mocked request does not exist")
+	}
+}
+
+// TestStoredEmbedded calls EmbeddedMock.Stored,
+// checking for a valid return value.
+func TestStoredEmbedded(t *testing.T) {
+	mockId := "8d7e5a9e-3c1b-4f25-9a4e-2f0c6b1d7e01"
+	data, err := NewEmbeddedMock(embeddedFS, *logger).Stored(mockId)
+	if err != nil || string(data) != string(embeddedFS[mockId+".json"].Data) {
+		t.Fatalf(`result: {%v} but expected {%v}`, string(data), string(embeddedFS[mockId+".json"].Data))
+	}
+}
@@ -0,0 +1,39 @@
+package internal
+
+import "time"
+
+// legacyTimeLayout is the layout (local time without timezone) of the dates of the mocked requests stored before RFC3339
+const legacyTimeLayout = "2006-01-02 15:04:05"
+
+// sortableTimeLayout is a fixed width UTC layout which keeps the chronological order when the dates are compared as strings
+const sortableTimeLayout = "2006-01-02T15:04:05.000000000Z"
+
+// timestamp returns the current date in RFC3339 (with nanoseconds to order the requests created in the same second)
+// with the timezone of the host (see the "TZ" environment variable)
+func timestamp() string {
+	return time.Now().Format(time.RFC3339Nano)
+}
+
+// ParseTime parses a date of a mocked request, RFC3339 or the legacy layout ("2006-01-02 15:04:05" in local time).
+func ParseTime(value string) (time.Time, error) {
+	if t, err := time.Parse(time.RFC3339, value); err == nil {
+		return t, nil
+	}
+	return time.ParseInLocation(legacyTimeLayout, value, time.Local)
+}
+
+// sortableTime returns the {value} date in a layout which can be compared as a string (whatever its layout and timezone),
+// a malformed {value} is returned as is.
+func sortableTime(value string) string {
+	t, err := ParseTime(value)
+	if err != nil {
+		return value
+	}
+	return t.UTC().Format(sortableTimeLayout)
+}
+
+// sortableKey returns the sortable {createdAt} date of a mocked request followed by its {id},
+// the {id} breaks the tie between the requests created at the same date.
+func sortableKey(createdAt, id string) string {
+	return sortableTime(createdAt) + " " + id
+}
@@ -0,0 +1,67 @@
+package internal
+
+import (
+	"testing"
+	"time"
+)
+
+// TestNewWithRFC3339CreatedAt calls Mocker.New,
+// checking for a valid return value.
+func TestNewWithRFC3339CreatedAt(t *testing.T) {
+	mocker := NewMock(t.TempDir(), nil, *logger)
+
+	id, err := mocker.New(reqParams, []byte("Hello World"))
+	if err != nil {
+		t.Fatalf(err.Error())
+	}
+
+	r, err := mocker.Get(*id)
+	if err != nil {
+		t.Fatalf(err.Error())
+	}
+
+	if createdAt, err := time.Parse(time.RFC3339, r.CreatedAt); err != nil || time.Since(createdAt) > time.Minute {
+		t.Fatalf(`result: {%v} but expected {%v}`, r.CreatedAt, "RFC3339 date")
+	}
+}
+
+// TestParseTime calls ParseTime(string),
+// checking for a valid return value.
+func TestParseTime(t *testing.T) {
+	expected := time.Date(2024, 8, 1, 10, 0, 0, 0, time.Local)
+
+	for _, value := range []string{expected.Format(time.RFC3339), "2024-08-01 10:00:00"} {
+		if r, err := ParseTime(value); err != nil || !r.Equal(expected) {
+			t.Fatalf(`%s - result: {%v} but expected {%v}`, value, r, expected)
+		}
+	}
+
+	if r, err := ParseTime("01/08/2024"); err == nil {
+		t.Fatalf(`result: {%v} but expected error`, r)
+	}
+}
+
+// TestListWithMixedCreatedAt calls Mocker.List,
+// checking for a valid return value.
+func TestListWithMixedCreatedAt(t *testing.T) {
+	mocker := NewInMemoryMock(*logger)
+
+	createdAts := []string{
+		"2024-08-01T11:00:00+02:00", // 09:00 UTC
+		"2024-08-01T10:00:00Z",
+		"1970-01-01 00:00:01", // legacy layout
+	}
+	for _, createdAt := range createdAts {
+		id, err := mocker.New(reqParams, []byte("Hello World"))
+		if err != nil {
+			t.Fatalf(err.Error())
+		}
+		setCreatedAt(mocker, *id, createdAt)
+	}
+
+	values, _ := mocker.List()
+	if len(values) != 3 || values[0].CreatedAt != createdAts[1] ||
+		values[1].CreatedAt != createdAts[0] || values[2].CreatedAt != createdAts[2] {
+		t.Fatalf(`result: {%v} but expected {%v}`, values, "sorted from the most recently created")
+	}
+}
@@ -0,0 +1,127 @@
+package internal
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"slices"
+	"strconv"
+	"strings"
+
+	"github.com/joakim-ribier/go-utils/pkg/genericsutil"
+	"github.com/joakim-ribier/go-utils/pkg/jsonsutil"
+	"github.com/joakim-ribier/go-utils/pkg/slicesutil"
+	"github.com/joakim-ribier/mockapic/pkg"
+)
+
+// acceptRange represents a media range of the "Accept" header with its quality
+type acceptRange struct {
+	mediaType string
+	q         float64
+}
+
+// parseAccept parses the {accept} header value as media ranges sorted by quality (the order is kept for the same quality),
+// the media ranges which are not acceptable (q=0) are ignored
+func parseAccept(accept string) []acceptRange {
+	ranges := []acceptRange{}
+	for _, value := range strings.Split(accept, ",") {
+		mediaType, params, _ := strings.Cut(value, ";")
+		mediaType = strings.ToLower(strings.TrimSpace(mediaType))
+		if mediaType == "" {
+			continue
+		}
+
+		q := 1.0
+		for _, param := range strings.Split(params, ";") {
+			if key, value, is := strings.Cut(strings.TrimSpace(param), "="); is && strings.TrimSpace(key) == "q" {
+				if parsed, err := strconv.ParseFloat(strings.TrimSpace(value), 64); err == nil {
+					q = parsed
+				}
+			}
+		}
+		if q > 0 {
+			ranges = append(ranges, acceptRange{mediaType: mediaType, q: q})
+		}
+	}
+
+	slices.SortStableFunc(ranges, func(r1, r2 acceptRange) int {
+		switch {
+		case r1.q > r2.q:
+			return -1
+		case r1.q < r2.q:
+			return 1
+		}
+		return 0
+	})
+	return ranges
+}
+
+// matchMediaRange returns true if the {contentType} matches the {mediaRange} ("*/*", "text/*" or "text/plain").
+func matchMediaRange(mediaRange, contentType string) bool {
+	if mediaRange == "*/*" || mediaRange == contentType {
+		return true
+	}
+	if prefix, is := strings.CutSuffix(mediaRange, "/*"); is {
+		return strings.HasPrefix(contentType, prefix+"/")
+	}
+	return false
+}
+
+// ApplyVariant returns the mocked request with the content type, the charset, the status and the body of the variant
+// which best matches the request "Accept" header, the mocked request is returned as is if its own content type matches first.
+func (m MockedRequest) ApplyVariant(req *http.Request) MockedRequest {
+	if req == nil || len(m.Variants) == 0 || req.Header.Get("Accept") == "" {
+		return m
+	}
+
+	for _, mediaRange := range parseAccept(req.Header.Get("Accept")) {
+		if matchMediaRange(mediaRange.mediaType, m.ContentType) {
+			return m
+		}
+		for _, variant := range m.Variants {
+			if matchMediaRange(mediaRange.mediaType, variant.ContentType) {
+				m.Status = variant.Status
+				m.ContentType = variant.ContentType
+				m.Charset = variant.Charset
+				m.Body = ""
+				m.Body64 = variant.Body64
+				return m
+			}
+		}
+	}
+	return m
+}
+
+// newVariants builds the variants of the {parent} from the {value} JSON array,
+// each variant inherits the status and the charset (display contents only) of the {parent} if they are not defined.
+func newVariants(parent MockedRequest, value string) ([]MockedRequest, error) {
+	variants, err := jsonsutil.Unmarshal[[]MockedRequest]([]byte(value))
+	if err != nil || len(variants) == 0 {
+		return nil, errors.New("variants must be a non-empty JSON array of responses")
+	}
+
+	for i, variant := range variants {
+		variant.Status = genericsutil.OrElse(variant.Status, func() bool { return variant.Status != 0 }, parent.Status)
+		variant.canonicalize()
+		if variant.Charset == "" && slicesutil.Exist(pkg.IS_DISPLAY_CONTENT, variant.ContentType) {
+			variant.Charset = parent.Charset
+		}
+		if len(variant.Body) > 0 {
+			variant.Body64 = []byte(variant.Body)
+			variant.Body = ""
+		}
+
+		if err := validate(variant); err != nil {
+			return nil, fmt.Errorf("variants[%d]: %w", i, err)
+		}
+		variants[i] = MockedRequest{
+			MockedRequestLight: MockedRequestLight{MockedRequestHeader: MockedRequestHeader{
+				Status:      variant.Status,
+				ContentType: variant.ContentType,
+				Charset:     variant.Charset,
+			}},
+			Body64: variant.Body64,
+		}
+	}
+	return variants, nil
+}
@@ -0,0 +1,79 @@
+package internal
+
+import (
+	"net/http/httptest"
+	"testing"
+)
+
+// TestParseAccept calls parseAccept(string),
+// checking for a valid return value.
+func TestParseAccept(t *testing.T) {
+	ranges := parseAccept("text/html;q=0.5, application/xml;q=0.9, Application/JSON, image/png;q=0, */*;q=0.1")
+
+	expected := []string{"application/json", "application/xml", "text/html", "*/*"}
+	if len(ranges) != len(expected) {
+		t.Fatalf(`result: {%v} but expected {%v}`, ranges, expected)
+	}
+	for i, mediaRange := range ranges {
+		if mediaRange.mediaType != expected[i] {
+			t.Fatalf(`result: {%v} but expected {%v}`, ranges, expected)
+		}
+	}
+}
+
+// TestApplyVariant calls MockedRequest.ApplyVariant(*http.Request),
+// checking for a valid return value.
+func TestApplyVariant(t *testing.T) {
+	mocker := NewInMemoryMock(*logger)
+	id, err := mocker.New(map[string][]string{
+		"contentType": {"application/json"},
+		"charset":     {"UTF-8"},
+		"variants":    {`[{"contentType": "application/xml", "body": "<name>mockapic</name>"}, {"contentType": "text/plain", "status": 203, "body": "mockapic"}]`},
+	}, []byte(`{"name": "mockapic"}`))
+	if err != nil {
+		t.Fatalf(err.Error())
+	}
+	mock, _ := mocker.Get(*id)
+
+	tests := []struct {
+		accept      string
+		contentType string
+		body        string
+		status      int
+	}{
+		{"", "application/json", `{"name": "mockapic"}`, 200},
+		{"application/json", "application/json", `{"name": "mockapic"}`, 200},
+		{"application/xml", "application/xml", "<name>mockapic</name>", 200},
+		{"application/json;q=0.5, text/xml, application/xml;q=0.8", "application/xml", "<name>mockapic</name>", 200},
+		{"text/*", "text/plain", "mockapic", 203},
+		{"*/*", "application/json", `{"name": "mockapic"}`, 200},
+		{"image/png", "application/json", `{"name": "mockapic"}`, 200},
+	}
+	for _, test := range tests {
+		req := httptest.NewRequest("GET", "http://localhost:3333/v1/"+*id, nil)
+		req.Header.Set("Accept", test.accept)
+
+		r := mock.ApplyVariant(req)
+		if r.ContentType != test.contentType || string(r.Body64) != test.body || r.Status != test.status {
+			t.Fatalf(`%s - result: {%v, %v, %v} but expected {%v}`, test.accept, r.ContentType, string(r.Body64), r.Status, test)
+		}
+	}
+}
+
+// TestNewWithBadVariants calls Mocker.New,
+// checking for a valid return value.
+func TestNewWithBadVariants(t *testing.T) {
+	tests := map[string]string{
+		"wrong":                                  "variants must be a non-empty JSON array of responses",
+		"[]":                                     "variants must be a non-empty JSON array of responses",
+		`[{"contentType": "application/wrong"}]`: "variants[0]: content type {application/wrong} does not exist",
+	}
+	for variants, expected := range tests {
+		_, err := NewInMemoryMock(*logger).New(map[string][]string{
+			"contentType": {"application/json"}, "charset": {"UTF-8"}, "variants": {variants},
+		}, nil)
+		if err == nil || err.Error() != expected {
+			t.Fatalf(`result: {%v} but expected {%v}`, err, expected)
+		}
+	}
+}
@@ -0,0 +1,20 @@
+package internal
+
+import "github.com/joakim-ribier/go-utils/pkg/stringsutil"
+
+// the build info are injected at build time, e.g.
+// go build -ldflags "-X github.com/joakim-ribier/mockapic/internal.VERSION=1.0.0 -X github.com/joakim-ribier/mockapic/internal.COMMIT=$(git rev-parse HEAD)"
+var (
+	VERSION    string
+	COMMIT     string
+	BUILD_DATE string
+)
+
+// BuildInfo returns the version, the git commit and the build date of the binary ("dev" if not injected).
+func BuildInfo() map[string]string {
+	return map[string]string{
+		"version":   stringsutil.OrElse(VERSION, "dev"),
+		"commit":    stringsutil.OrElse(COMMIT, "dev"),
+		"buildDate": stringsutil.OrElse(BUILD_DATE, "dev"),
+	}
+}
@@ -0,0 +1,71 @@
+package internal
+
+import (
+	"errors"
+	"fmt"
+	"slices"
+
+	"github.com/joakim-ribier/go-utils/pkg/jsonsutil"
+	"github.com/joakim-ribier/mockapic/pkg"
+)
+
+// ApplyStatusWeights returns the mocked request to serve: the status is drawn from the weighted distribution
+// with the random {draw} (in [0, 1)) and the body of this status (if defined) replaces the default body.
+func (m MockedRequest) ApplyStatusWeights(draw float64) MockedRequest {
+	total := 0.0
+	statuses := make([]int, 0, len(m.StatusWeights))
+	for status, weight := range m.StatusWeights {
+		total += weight
+		statuses = append(statuses, status)
+	}
+	if total <= 0 {
+		return m
+	}
+
+	// the statuses are sorted to always draw the same status from the same {draw}
+	slices.Sort(statuses)
+	status := statuses[len(statuses)-1]
+	cumulative := 0.0
+	for _, s := range statuses {
+		cumulative += m.StatusWeights[s]
+		if draw*total < cumulative {
+			status = s
+			break
+		}
+	}
+
+	m.Status = status
+	if body, is := m.StatusBodies[status]; is {
+		m.Body = ""
+		m.Body64 = []byte(body)
+	}
+	return m
+}
+
+// newStatusWeights parses the JSON object {value} of the weights by status ({"200": 90, "500": 8, "503": 2}).
+func newStatusWeights(value string) (map[int]float64, error) {
+	statusWeights, err := jsonsutil.Unmarshal[map[int]float64]([]byte(value))
+	if err != nil || len(statusWeights) == 0 {
+		return nil, errors.New("status weights must be a non-empty JSON object of weights by status")
+	}
+	return statusWeights, nil
+}
+
+// validateStatusWeights returns an error if a status of the weights (or of the bodies) does not exist,
+// if a weight is negative or if a body is defined for a status without weight.
+func validateStatusWeights(mock MockedRequest) error {
+	for status, weight := range mock.StatusWeights {
+		if _, is := pkg.HTTP_CODES[status]; !is {
+			return fmt.Errorf("weighted status {%d} does not exist", status)
+		}
+		if weight < 0 {
+			return fmt.Errorf("weight {%v} of the status {%d} must be positive", weight, status)
+		}
+	}
+	for status := range mock.StatusBodies {
+		if _, is := mock.StatusWeights[status]; !is {
+			return fmt.Errorf("body of the status {%d} is defined without weight", status)
+		}
+	}
+	return nil
+}
@@ -0,0 +1,88 @@
+package internal
+
+import (
+	"reflect"
+	"testing"
+)
+
+// TestApplyStatusWeights calls MockedRequest.ApplyStatusWeights(float64),
+// checking for a valid return value.
+func TestApplyStatusWeights(t *testing.T) {
+	mock := MockedRequest{
+		MockedRequestLight: MockedRequestLight{MockedRequestHeader: MockedRequestHeader{Status: 200}},
+		StatusWeights:      map[int]float64{200: 90, 500: 8, 503: 2},
+		StatusBodies:       map[int]string{503: "Service Unavailable"},
+		Body64:             []byte("Hello World"),
+	}
+
+	tests := []struct {
+		draw     float64
+		status   int
+		expected string
+	}{
+		{0, 200, "Hello World"},
+		{0.89, 200, "Hello World"},
+		{0.90, 500, "Hello World"},
+		{0.97, 500, "Hello World"},
+		{0.98, 503, "Service Unavailable"},
+		{0.99, 503, "Service Unavailable"},
+	}
+	for _, test := range tests {
+		if r := mock.ApplyStatusWeights(test.draw); r.Status != test.status || string(r.Body64) != test.expected {
+			t.Fatalf(`%v - result: {%v %s} but expected {%v %s}`, test.draw, r.Status, string(r.Body64), test.status, test.expected)
+		}
+	}
+
+	// test if a single weight always draws the same status
+	mock.StatusWeights = map[int]float64{503: 1}
+	for _, draw := range []float64{0, 0.25, 0.5, 0.75, 0.99} {
+		if r := mock.ApplyStatusWeights(draw); r.Status != 503 || string(r.Body64) != "Service Unavailable" {
+			t.Fatalf(`%v - result: {%v} but expected {%v}`, draw, r.Status, 503)
+		}
+	}
+
+	// test if the default weights keep the fixed status
+	mock.StatusWeights = nil
+	if r := mock.ApplyStatusWeights(0.99); r.Status != 200 || string(r.Body64) != "Hello World" {
+		t.Fatalf(`result: {%v} but expected {%v}`, r.Status, 200)
+	}
+}
+
+// TestNewWithStatusWeights calls Mocker.New,
+// checking for a valid return value.
+func TestNewWithStatusWeights(t *testing.T) {
+	mocker := NewInMemoryMock(*logger)
+	params := func(statusWeights string, statusBodies map[string]string) map[string][]string {
+		params := map[string][]string{"contentType": {"text/plain"}, "charset": {"UTF-8"}, "statusWeights": {statusWeights}}
+		for status, body := range statusBodies {
+			params["statusBody."+status] = []string{body}
+		}
+		return params
+	}
+
+	id, err := mocker.New(params(`{"200": 90, "500": 8, "503": 2}`, map[string]string{"503": "Service Unavailable"}), []byte("Hello World"))
+	if err != nil {
+		t.Fatalf(err.Error())
+	}
+	r, _ := mocker.Get(*id)
+	if !reflect.DeepEqual(r.StatusWeights, map[int]float64{200: 90, 500: 8, 503: 2}) ||
+		!reflect.DeepEqual(r.StatusBodies, map[int]string{503: "Service Unavailable"}) {
+		t.Fatalf(`result: {%v %v} but expected {%v}`, r.StatusWeights, r.StatusBodies, "the weights and the bodies")
+	}
+
+	tests := map[string][]string{
+		"status weights must be a non-empty JSON object of weights by status": {`{}`, ""},
+		"weighted status {999} does not exist":                                {`{"200": 90, "999": 10}`, ""},
+		"weight {-1} of the status {500} must be positive":                    {`{"200": 90, "500": -1}`, ""},
+		"body of the status {404} is defined without weight":                  {`{"200": 1}`, "404"},
+	}
+	for expected, values := range tests {
+		statusBodies := map[string]string{}
+		if values[1] != "" {
+			statusBodies[values[1]] = "Not Found"
+		}
+		if _, err := mocker.New(params(values[0], statusBodies), nil); err == nil || err.Error() != expected {
+			t.Fatalf(`result: {%v} but expected {%v}`, err, expected)
+		}
+	}
+}
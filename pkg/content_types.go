@@ -8,6 +8,9 @@ import (
 
 var CONTENT_TYPES = []string{
 	"application/json",
+	"application/msgpack",
+	"application/octet-stream",
+	"application/x-protobuf",
 	"application/x-www-form-urlencoded",
 	"application/xhtml+xml",
 	"application/xml",
@@ -27,6 +30,11 @@ var IS_DISPLAY_CONTENT = slicesutil.FilterT(CONTENT_TYPES, func(arg string) bool
 	return arg == "application/json" || arg == "application/xml" || strings.Contains(arg, "text/")
 })
 
+var IS_BINARY_CONTENT = slicesutil.FilterT(CONTENT_TYPES, func(arg string) bool {
+	return arg == "image/jpeg" || arg == "image/png" || arg == "application/octet-stream" || arg == "application/msgpack" ||
+		arg == "application/x-protobuf"
+})
+
 var CHARSET = []string{
 	"UTF-8",
 	"ISO-8859-1",
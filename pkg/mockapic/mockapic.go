@@ -0,0 +1,93 @@
+// Package mockapic builds a mockapic HTTP server (file storage) to embed it in a Go service.
+package mockapic
+
+import (
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"time"
+
+	"github.com/joakim-ribier/go-utils/pkg/logsutil"
+	"github.com/joakim-ribier/go-utils/pkg/slicesutil"
+	"github.com/joakim-ribier/go-utils/pkg/stringsutil"
+	"github.com/joakim-ribier/mockapic/internal"
+	"github.com/joakim-ribier/mockapic/internal/server"
+	"github.com/joakim-ribier/mockapic/pkg"
+)
+
+// HTTPServer is the mockapic server, see {HTTPServer.Listen}, {HTTPServer.Shutdown} and {HTTPServer.Handler}
+type HTTPServer = server.HTTPServer
+
+// Options defines the configuration of the server, the zero values keep the defaults
+type Options struct {
+	// Port of the server ("3333" by default)
+	Port string
+	// Directory is the home of the server (required), the mocked requests are stored in "{Directory}/requests"
+	Directory string
+	// Mocks is a read-only file system of mocked requests (e.g. an embed.FS) served instead of the "{Directory}/requests" storage,
+	// it has the same layout as the storage ("{id}.json" files and the namespaces as directories)
+	Mocks fs.FS
+	// MaxDelay of the served mocked requests (60s by default)
+	MaxDelay time.Duration
+	// DisableDelay ignores all the delays of the served mocked requests to respond immediately
+	DisableDelay bool
+	// BodyMaxSize is the max size (in bytes) of the body of a new mocked request (10MB by default)
+	BodyMaxSize int64
+	// StorageMaxSize is the max size (in bytes) of the storage, the oldest mocked requests are removed above
+	StorageMaxSize int64
+	// RateLimit is the number of served mocked requests per second by client address (with a RateBurst)
+	RateLimit float64
+	RateBurst int
+	// DefaultContentType and DefaultCharset of the new mocked requests which do not define them
+	DefaultContentType string
+	DefaultCharset     string
+}
+
+// New creates the logger (in "{Directory}/application.log"), the file storage and the server from the {opts}
+func New(opts Options) (*HTTPServer, error) {
+	if opts.Directory == "" {
+		return nil, errors.New("directory is required")
+	}
+	if opts.DefaultContentType != "" && !slicesutil.Exist(pkg.CONTENT_TYPES, opts.DefaultContentType) {
+		return nil, fmt.Errorf("default content type {%s} is not supported", opts.DefaultContentType)
+	}
+	if opts.DefaultCharset != "" && !slicesutil.Exist(pkg.CHARSET, opts.DefaultCharset) {
+		return nil, fmt.Errorf("default charset {%s} is not supported", opts.DefaultCharset)
+	}
+
+	if err := os.MkdirAll(opts.Directory, os.ModePerm); err != nil {
+		return nil, err
+	}
+
+	logger, err := logsutil.NewLogger(filepath.Join(opts.Directory, "application.log"), "mockapic")
+	if err != nil {
+		return nil, err
+	}
+
+	var mocker internal.Mocker
+	if opts.Mocks != nil {
+		mocker = internal.NewEmbeddedMock(opts.Mocks, *logger)
+	} else {
+		requestsDirectory := filepath.Join(opts.Directory, "requests")
+		if err := os.MkdirAll(requestsDirectory, os.ModePerm); err != nil {
+			return nil, err
+		}
+		mocker = internal.NewMock(requestsDirectory, nil, *logger,
+			internal.WithDefaults(opts.DefaultContentType, opts.DefaultCharset))
+	}
+
+	return server.NewHTTPServer(
+		stringsutil.OrElse(opts.Port, "3333"),
+		false,
+		"",
+		opts.Directory,
+		mocker,
+		*logger,
+		server.WithMaxDelay(opts.MaxDelay),
+		server.WithoutDelay(opts.DisableDelay),
+		server.WithMaxBodySize(opts.BodyMaxSize),
+		server.WithStorageQuota(opts.StorageMaxSize, time.Minute),
+		server.WithRateLimit(opts.RateLimit, opts.RateBurst)), nil
+}
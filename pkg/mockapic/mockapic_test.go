@@ -0,0 +1,106 @@
+package mockapic
+
+import (
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"testing/fstest"
+	"time"
+)
+
+// TestNew calls New(Options),
+// checking for a valid return value.
+func TestNew(t *testing.T) {
+	s, err := New(Options{Directory: t.TempDir(), MaxDelay: time.Second})
+	if err != nil {
+		t.Fatalf(`result: {%v} but expected {%v}`, err, nil)
+	}
+
+	server := httptest.NewServer(s.Handler())
+	defer server.Close()
+
+	resp, err := http.Post(server.URL+"/v1/new?status=201&contentType=text/plain&charset=UTF-8", "text/plain", strings.NewReader("Hello World"))
+	if err != nil {
+		t.Fatalf(err.Error())
+	}
+	var data map[string]any
+	json.NewDecoder(resp.Body).Decode(&data)
+	resp.Body.Close()
+
+	resp, err = http.Get(server.URL + "/v1/" + data["id"].(string))
+	if err != nil {
+		t.Fatalf(err.Error())
+	}
+	defer resp.Body.Close()
+
+	body, _ := io.ReadAll(resp.Body)
+	if resp.StatusCode != 201 || string(body) != "Hello World" {
+		t.Fatalf(`result: {%v} but expected {%v}`, string(body), "Hello World")
+	}
+
+	resp, err = http.Get(server.URL + "/v1/config")
+	if err != nil {
+		t.Fatalf(err.Error())
+	}
+	defer resp.Body.Close()
+
+	if body, _ := io.ReadAll(resp.Body); !strings.Contains(string(body), `"maxDelay":"1s"`) {
+		t.Fatalf(`result: {%v} but expected {%v}`, string(body), "1s")
+	}
+}
+
+// TestNewWithBadOptions calls New(Options),
+// checking for a valid return value.
+func TestNewWithBadOptions(t *testing.T) {
+	tests := map[string]Options{
+		"directory is required":                         {},
+		"default content type {wrong} is not supported": {Directory: t.TempDir(), DefaultContentType: "wrong"},
+		"default charset {wrong} is not supported":      {Directory: t.TempDir(), DefaultCharset: "wrong"},
+	}
+	for expected, opts := range tests {
+		if _, err := New(opts); err == nil || err.Error() != expected {
+			t.Fatalf(`result: {%v} but expected {%v}`, err, expected)
+		}
+	}
+}
+
+// TestNewWithMocks calls New(Options),
+// checking for a valid return value.
+func TestNewWithMocks(t *testing.T) {
+	mocks := fstest.MapFS{
+		"0f6c1c8e-6a3b-4c47-8d62-7c1b9b0a5e11.json": {Data: []byte(
+			`{"id":"0f6c1c8e-6a3b-4c47-8d62-7c1b9b0a5e11","status":200,"contentType":"text/plain","charset":"UTF-8","body64":"SGVsbG8gV29ybGQ="}`)},
+	}
+
+	s, err := New(Options{Directory: t.TempDir(), Mocks: mocks})
+	if err != nil {
+		t.Fatalf(`result: {%v} but expected {%v}`, err, nil)
+	}
+
+	server := httptest.NewServer(s.Handler())
+	defer server.Close()
+
+	resp, err := http.Get(server.URL + "/v1/0f6c1c8e-6a3b-4c47-8d62-7c1b9b0a5e11")
+	if err != nil {
+		t.Fatalf(err.Error())
+	}
+	defer resp.Body.Close()
+
+	if body, _ := io.ReadAll(resp.Body); resp.StatusCode != 200 || string(body) != "Hello World" {
+		t.Fatalf(`result: {%v} but expected {%v}`, string(body), "Hello World")
+	}
+
+	// test if the mocked requests are read-only
+	resp, err = http.Post(server.URL+"/v1/new?status=201&contentType=text/plain&charset=UTF-8", "text/plain", strings.NewReader("Hello World"))
+	if err != nil {
+		t.Fatalf(err.Error())
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != 403 {
+		t.Fatalf(`result: {%v} but expected {%v}`, resp.StatusCode, 403)
+	}
+}
@@ -0,0 +1,17 @@
+package pkg
+
+// Statics groups the values allowed to create a mocked request
+type Statics struct {
+	ContentTypes        []string       `json:"contentTypes"`
+	DisplayContentTypes []string       `json:"displayContentTypes"`
+	Charsets            []string       `json:"charsets"`
+	StatusCodes         map[int]string `json:"statusCodes"`
+}
+
+// ALL_STATICS references the {CONTENT_TYPES}, {IS_DISPLAY_CONTENT}, {CHARSET} and {HTTP_CODES} values
+var ALL_STATICS = Statics{
+	ContentTypes:        CONTENT_TYPES,
+	DisplayContentTypes: IS_DISPLAY_CONTENT,
+	Charsets:            CHARSET,
+	StatusCodes:         HTTP_CODES,
+}